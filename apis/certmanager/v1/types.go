@@ -0,0 +1,237 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1 models the cert-manager.io/v1 API types of cert-manager, including the ACME issuer types.
+// Only the fields needed to build the objects are modelled so kopts doesn't depend on the cert-manager module
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gatewayv1 "github.com/CoverWhale/kopts/apis/gateway/v1"
+)
+
+const (
+	GroupName  = "cert-manager.io"
+	APIVersion = GroupName + "/v1"
+)
+
+// PrivateKeyAlgorithm is the key algorithm of a certificate private key
+type PrivateKeyAlgorithm string
+
+const (
+	RSAKeyAlgorithm     PrivateKeyAlgorithm = "RSA"
+	ECDSAKeyAlgorithm   PrivateKeyAlgorithm = "ECDSA"
+	Ed25519KeyAlgorithm PrivateKeyAlgorithm = "Ed25519"
+)
+
+// PrivateKeyEncoding is the encoding of a certificate private key
+type PrivateKeyEncoding string
+
+const (
+	PKCS1 PrivateKeyEncoding = "PKCS1"
+	PKCS8 PrivateKeyEncoding = "PKCS8"
+)
+
+// PrivateKeyRotationPolicy describes whether the private key is regenerated when the certificate is reissued
+type PrivateKeyRotationPolicy string
+
+const (
+	RotationPolicyNever  PrivateKeyRotationPolicy = "Never"
+	RotationPolicyAlways PrivateKeyRotationPolicy = "Always"
+)
+
+// KeyUsage is a key usage requested for a certificate
+type KeyUsage string
+
+const (
+	UsageDigitalSignature KeyUsage = "digital signature"
+	UsageKeyEncipherment  KeyUsage = "key encipherment"
+	UsageCertSign         KeyUsage = "cert sign"
+	UsageCRLSign          KeyUsage = "crl sign"
+	UsageServerAuth       KeyUsage = "server auth"
+	UsageClientAuth       KeyUsage = "client auth"
+)
+
+// CNAMEStrategy describes whether DNS01 challenges follow CNAME records
+type CNAMEStrategy string
+
+const (
+	NoneStrategy   CNAMEStrategy = "None"
+	FollowStrategy CNAMEStrategy = "Follow"
+)
+
+// ObjectReference refers to an issuer
+type ObjectReference struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind,omitempty"`
+	Group string `json:"group,omitempty"`
+}
+
+// SecretKeySelector refers to a key of a secret in the issuer namespace, or the cluster resource
+// namespace for cluster issuers
+type SecretKeySelector struct {
+	Name string `json:"name"`
+	Key  string `json:"key,omitempty"`
+}
+
+// Certificate is a request for a signed certificate stored in a secret
+type Certificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CertificateSpec `json:"spec"`
+}
+
+// CertificateSpec is the desired state of a certificate
+type CertificateSpec struct {
+	CommonName  string                 `json:"commonName,omitempty"`
+	Duration    *metav1.Duration       `json:"duration,omitempty"`
+	RenewBefore *metav1.Duration       `json:"renewBefore,omitempty"`
+	DNSNames    []string               `json:"dnsNames,omitempty"`
+	IPAddresses []string               `json:"ipAddresses,omitempty"`
+	SecretName  string                 `json:"secretName"`
+	IssuerRef   ObjectReference        `json:"issuerRef"`
+	IsCA        bool                   `json:"isCA,omitempty"`
+	Usages      []KeyUsage             `json:"usages,omitempty"`
+	PrivateKey  *CertificatePrivateKey `json:"privateKey,omitempty"`
+}
+
+// CertificatePrivateKey holds the private key settings of a certificate
+type CertificatePrivateKey struct {
+	RotationPolicy PrivateKeyRotationPolicy `json:"rotationPolicy,omitempty"`
+	Encoding       PrivateKeyEncoding       `json:"encoding,omitempty"`
+	Algorithm      PrivateKeyAlgorithm      `json:"algorithm,omitempty"`
+	Size           int                      `json:"size,omitempty"`
+}
+
+// Issuer is a certificate authority that signs certificates in its namespace
+type Issuer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IssuerSpec `json:"spec"`
+}
+
+// ClusterIssuer is a certificate authority that signs certificates in all namespaces
+type ClusterIssuer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IssuerSpec `json:"spec"`
+}
+
+// IssuerSpec is the configuration of an issuer. Exactly one of the issuer types must be set
+type IssuerSpec struct {
+	ACME       *ACMEIssuer       `json:"acme,omitempty"`
+	CA         *CAIssuer         `json:"ca,omitempty"`
+	SelfSigned *SelfSignedIssuer `json:"selfSigned,omitempty"`
+}
+
+// CAIssuer signs certificates with the CA key pair stored in a secret
+type CAIssuer struct {
+	SecretName            string   `json:"secretName"`
+	CRLDistributionPoints []string `json:"crlDistributionPoints,omitempty"`
+	OCSPServers           []string `json:"ocspServers,omitempty"`
+}
+
+// SelfSignedIssuer signs certificates with their own private key
+type SelfSignedIssuer struct {
+	CRLDistributionPoints []string `json:"crlDistributionPoints,omitempty"`
+}
+
+// ACMEIssuer obtains certificates from an ACME server such as Let's Encrypt
+type ACMEIssuer struct {
+	Email          string                `json:"email,omitempty"`
+	Server         string                `json:"server"`
+	PreferredChain string                `json:"preferredChain,omitempty"`
+	PrivateKey     SecretKeySelector     `json:"privateKeySecretRef"`
+	Solvers        []ACMEChallengeSolver `json:"solvers,omitempty"`
+}
+
+// ACMEChallengeSolver solves ACME challenges for the names matched by the selector.
+// Exactly one of HTTP01 and DNS01 must be set
+type ACMEChallengeSolver struct {
+	Selector *CertificateDNSNameSelector `json:"selector,omitempty"`
+	HTTP01   *ACMEChallengeSolverHTTP01  `json:"http01,omitempty"`
+	DNS01    *ACMEChallengeSolverDNS01   `json:"dns01,omitempty"`
+}
+
+// CertificateDNSNameSelector selects the certificates a solver is used for
+type CertificateDNSNameSelector struct {
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+	DNSNames    []string          `json:"dnsNames,omitempty"`
+	DNSZones    []string          `json:"dnsZones,omitempty"`
+}
+
+// ACMEChallengeSolverHTTP01 solves HTTP01 challenges through an ingress or a Gateway API route
+type ACMEChallengeSolverHTTP01 struct {
+	Ingress          *ACMEChallengeSolverHTTP01Ingress          `json:"ingress,omitempty"`
+	GatewayHTTPRoute *ACMEChallengeSolverHTTP01GatewayHTTPRoute `json:"gatewayHTTPRoute,omitempty"`
+}
+
+// ACMEChallengeSolverHTTP01Ingress configures the ingress created to solve challenges
+type ACMEChallengeSolverHTTP01Ingress struct {
+	ServiceType      corev1.ServiceType `json:"serviceType,omitempty"`
+	IngressClassName *string            `json:"ingressClassName,omitempty"`
+	Class            *string            `json:"class,omitempty"`
+	Name             string             `json:"name,omitempty"`
+}
+
+// ACMEChallengeSolverHTTP01GatewayHTTPRoute configures the HTTP route created to solve challenges
+type ACMEChallengeSolverHTTP01GatewayHTTPRoute struct {
+	ServiceType corev1.ServiceType          `json:"serviceType,omitempty"`
+	Labels      map[string]string           `json:"labels,omitempty"`
+	ParentRefs  []gatewayv1.ParentReference `json:"parentRefs,omitempty"`
+}
+
+// ACMEChallengeSolverDNS01 solves DNS01 challenges with a DNS provider. Exactly one provider must be set
+type ACMEChallengeSolverDNS01 struct {
+	CNAMEStrategy CNAMEStrategy                        `json:"cnameStrategy,omitempty"`
+	CloudDNS      *ACMEIssuerDNS01ProviderCloudDNS     `json:"cloudDNS,omitempty"`
+	Cloudflare    *ACMEIssuerDNS01ProviderCloudflare   `json:"cloudflare,omitempty"`
+	Route53       *ACMEIssuerDNS01ProviderRoute53      `json:"route53,omitempty"`
+	DigitalOcean  *ACMEIssuerDNS01ProviderDigitalOcean `json:"digitalocean,omitempty"`
+}
+
+// ACMEIssuerDNS01ProviderCloudDNS configures Google Cloud DNS. Without a service account secret
+// the ambient credentials are used
+type ACMEIssuerDNS01ProviderCloudDNS struct {
+	ServiceAccount *SecretKeySelector `json:"serviceAccountSecretRef,omitempty"`
+	Project        string             `json:"project"`
+	HostedZoneName string             `json:"hostedZoneName,omitempty"`
+}
+
+// ACMEIssuerDNS01ProviderCloudflare configures Cloudflare with an API token or an API key
+type ACMEIssuerDNS01ProviderCloudflare struct {
+	Email    string             `json:"email,omitempty"`
+	APIKey   *SecretKeySelector `json:"apiKeySecretRef,omitempty"`
+	APIToken *SecretKeySelector `json:"apiTokenSecretRef,omitempty"`
+}
+
+// ACMEIssuerDNS01ProviderRoute53 configures AWS Route53. Without access keys the ambient credentials are used
+type ACMEIssuerDNS01ProviderRoute53 struct {
+	AccessKeyID       string             `json:"accessKeyID,omitempty"`
+	SecretAccessKeyID *SecretKeySelector `json:"accessKeyIDSecretRef,omitempty"`
+	SecretAccessKey   *SecretKeySelector `json:"secretAccessKeySecretRef,omitempty"`
+	Role              string             `json:"role,omitempty"`
+	HostedZoneID      string             `json:"hostedZoneID,omitempty"`
+	Region            string             `json:"region"`
+}
+
+// ACMEIssuerDNS01ProviderDigitalOcean configures DigitalOcean DNS
+type ACMEIssuerDNS01ProviderDigitalOcean struct {
+	Token SecretKeySelector `json:"tokenSecretRef"`
+}
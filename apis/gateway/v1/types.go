@@ -0,0 +1,387 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1 models the gateway.networking.k8s.io/v1 API types of the Kubernetes Gateway API.
+// Only the fields needed to build the objects are modelled so kopts doesn't depend on the Gateway API module
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	GroupName  = "gateway.networking.k8s.io"
+	APIVersion = GroupName + "/v1"
+)
+
+// ProtocolType is the protocol a listener accepts
+type ProtocolType string
+
+const (
+	HTTPProtocolType  ProtocolType = "HTTP"
+	HTTPSProtocolType ProtocolType = "HTTPS"
+	TLSProtocolType   ProtocolType = "TLS"
+	TCPProtocolType   ProtocolType = "TCP"
+	UDPProtocolType   ProtocolType = "UDP"
+)
+
+// TLSModeType is the TLS behavior of a listener
+type TLSModeType string
+
+const (
+	TLSModeTerminate   TLSModeType = "Terminate"
+	TLSModePassthrough TLSModeType = "Passthrough"
+)
+
+// FromNamespaces is the namespaces routes may be attached from
+type FromNamespaces string
+
+const (
+	NamespacesFromAll      FromNamespaces = "All"
+	NamespacesFromSelector FromNamespaces = "Selector"
+	NamespacesFromSame     FromNamespaces = "Same"
+)
+
+// Gateway is an instance of a service-traffic handling infrastructure
+type Gateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GatewaySpec `json:"spec"`
+}
+
+// GatewaySpec is the desired state of a gateway
+type GatewaySpec struct {
+	GatewayClassName string           `json:"gatewayClassName"`
+	Listeners        []Listener       `json:"listeners"`
+	Addresses        []GatewayAddress `json:"addresses,omitempty"`
+}
+
+// Listener is a logical endpoint where the gateway accepts connections
+type Listener struct {
+	Name          string            `json:"name"`
+	Hostname      *string           `json:"hostname,omitempty"`
+	Port          int32             `json:"port"`
+	Protocol      ProtocolType      `json:"protocol"`
+	TLS           *GatewayTLSConfig `json:"tls,omitempty"`
+	AllowedRoutes *AllowedRoutes    `json:"allowedRoutes,omitempty"`
+}
+
+// GatewayTLSConfig is the TLS configuration of a listener
+type GatewayTLSConfig struct {
+	Mode            *TLSModeType            `json:"mode,omitempty"`
+	CertificateRefs []SecretObjectReference `json:"certificateRefs,omitempty"`
+	Options         map[string]string       `json:"options,omitempty"`
+}
+
+// AllowedRoutes restricts the routes that can attach to a listener
+type AllowedRoutes struct {
+	Namespaces *RouteNamespaces `json:"namespaces,omitempty"`
+	Kinds      []RouteGroupKind `json:"kinds,omitempty"`
+}
+
+// RouteNamespaces selects the namespaces routes may be attached from
+type RouteNamespaces struct {
+	From     *FromNamespaces       `json:"from,omitempty"`
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// RouteGroupKind is the group and kind of a route resource
+type RouteGroupKind struct {
+	Group *string `json:"group,omitempty"`
+	Kind  string  `json:"kind"`
+}
+
+// GatewayAddress is an address requested for a gateway
+type GatewayAddress struct {
+	Type  *string `json:"type,omitempty"`
+	Value string  `json:"value"`
+}
+
+// ParentReference identifies the gateway a route attaches to
+type ParentReference struct {
+	Group       *string `json:"group,omitempty"`
+	Kind        *string `json:"kind,omitempty"`
+	Namespace   *string `json:"namespace,omitempty"`
+	Name        string  `json:"name"`
+	SectionName *string `json:"sectionName,omitempty"`
+	Port        *int32  `json:"port,omitempty"`
+}
+
+// CommonRouteSpec holds the fields shared by all route types
+type CommonRouteSpec struct {
+	ParentRefs []ParentReference `json:"parentRefs,omitempty"`
+}
+
+// SecretObjectReference identifies a secret, by default in the namespace of the referrer
+type SecretObjectReference struct {
+	Group     *string `json:"group,omitempty"`
+	Kind      *string `json:"kind,omitempty"`
+	Name      string  `json:"name"`
+	Namespace *string `json:"namespace,omitempty"`
+}
+
+// BackendObjectReference identifies a backend, by default a service in the namespace of the route
+type BackendObjectReference struct {
+	Group     *string `json:"group,omitempty"`
+	Kind      *string `json:"kind,omitempty"`
+	Name      string  `json:"name"`
+	Namespace *string `json:"namespace,omitempty"`
+	Port      *int32  `json:"port,omitempty"`
+}
+
+// BackendRef is a weighted backend reference
+type BackendRef struct {
+	BackendObjectReference `json:",inline"`
+	Weight                 *int32 `json:"weight,omitempty"`
+}
+
+// LocalObjectReference identifies an object in the namespace of the referrer
+type LocalObjectReference struct {
+	Group string `json:"group"`
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+}
+
+// HTTPRoute routes HTTP requests from a gateway listener to backends
+type HTTPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HTTPRouteSpec `json:"spec"`
+}
+
+// HTTPRouteSpec is the desired state of a HTTP route
+type HTTPRouteSpec struct {
+	CommonRouteSpec `json:",inline"`
+	Hostnames       []string        `json:"hostnames,omitempty"`
+	Rules           []HTTPRouteRule `json:"rules,omitempty"`
+}
+
+// HTTPRouteRule matches requests and forwards them to the backends. Any of the matches selects the rule
+type HTTPRouteRule struct {
+	Name        *string            `json:"name,omitempty"`
+	Matches     []HTTPRouteMatch   `json:"matches,omitempty"`
+	Filters     []HTTPRouteFilter  `json:"filters,omitempty"`
+	BackendRefs []HTTPBackendRef   `json:"backendRefs,omitempty"`
+	Timeouts    *HTTPRouteTimeouts `json:"timeouts,omitempty"`
+}
+
+// HTTPRouteTimeouts holds the timeouts of a rule as Gateway API durations such as "10s"
+type HTTPRouteTimeouts struct {
+	Request        *string `json:"request,omitempty"`
+	BackendRequest *string `json:"backendRequest,omitempty"`
+}
+
+// PathMatchType is how a path is matched
+type PathMatchType string
+
+const (
+	PathMatchExact             PathMatchType = "Exact"
+	PathMatchPathPrefix        PathMatchType = "PathPrefix"
+	PathMatchRegularExpression PathMatchType = "RegularExpression"
+)
+
+// HeaderMatchType is how a header or query parameter value is matched
+type HeaderMatchType string
+
+const (
+	HeaderMatchExact             HeaderMatchType = "Exact"
+	HeaderMatchRegularExpression HeaderMatchType = "RegularExpression"
+)
+
+// HTTPMethod is a HTTP request method
+type HTTPMethod string
+
+// HTTPRouteMatch holds the predicates of a request match. All of the predicates must match
+type HTTPRouteMatch struct {
+	Path        *HTTPPathMatch        `json:"path,omitempty"`
+	Headers     []HTTPHeaderMatch     `json:"headers,omitempty"`
+	QueryParams []HTTPQueryParamMatch `json:"queryParams,omitempty"`
+	Method      *HTTPMethod           `json:"method,omitempty"`
+}
+
+// HTTPPathMatch matches the request path
+type HTTPPathMatch struct {
+	Type  *PathMatchType `json:"type,omitempty"`
+	Value *string        `json:"value,omitempty"`
+}
+
+// HTTPHeaderMatch matches a request header
+type HTTPHeaderMatch struct {
+	Type  *HeaderMatchType `json:"type,omitempty"`
+	Name  string           `json:"name"`
+	Value string           `json:"value"`
+}
+
+// HTTPQueryParamMatch matches a request query parameter
+type HTTPQueryParamMatch struct {
+	Type  *HeaderMatchType `json:"type,omitempty"`
+	Name  string           `json:"name"`
+	Value string           `json:"value"`
+}
+
+// HTTPRouteFilterType is the type of a route filter
+type HTTPRouteFilterType string
+
+const (
+	HTTPRouteFilterRequestHeaderModifier  HTTPRouteFilterType = "RequestHeaderModifier"
+	HTTPRouteFilterResponseHeaderModifier HTTPRouteFilterType = "ResponseHeaderModifier"
+	HTTPRouteFilterRequestRedirect        HTTPRouteFilterType = "RequestRedirect"
+	HTTPRouteFilterURLRewrite             HTTPRouteFilterType = "URLRewrite"
+	HTTPRouteFilterRequestMirror          HTTPRouteFilterType = "RequestMirror"
+	HTTPRouteFilterExtensionRef           HTTPRouteFilterType = "ExtensionRef"
+)
+
+// HTTPRouteFilter modifies a request or response. The field matching the type must be set
+type HTTPRouteFilter struct {
+	Type                   HTTPRouteFilterType        `json:"type"`
+	RequestHeaderModifier  *HTTPHeaderFilter          `json:"requestHeaderModifier,omitempty"`
+	ResponseHeaderModifier *HTTPHeaderFilter          `json:"responseHeaderModifier,omitempty"`
+	RequestMirror          *HTTPRequestMirrorFilter   `json:"requestMirror,omitempty"`
+	RequestRedirect        *HTTPRequestRedirectFilter `json:"requestRedirect,omitempty"`
+	URLRewrite             *HTTPURLRewriteFilter      `json:"urlRewrite,omitempty"`
+	ExtensionRef           *LocalObjectReference      `json:"extensionRef,omitempty"`
+}
+
+// HTTPHeader is a header name and value
+type HTTPHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HTTPHeaderFilter sets, adds or removes headers
+type HTTPHeaderFilter struct {
+	Set    []HTTPHeader `json:"set,omitempty"`
+	Add    []HTTPHeader `json:"add,omitempty"`
+	Remove []string     `json:"remove,omitempty"`
+}
+
+// HTTPPathModifierType is how a path is modified
+type HTTPPathModifierType string
+
+const (
+	FullPathHTTPPathModifier    HTTPPathModifierType = "ReplaceFullPath"
+	PrefixMatchHTTPPathModifier HTTPPathModifierType = "ReplacePrefixMatch"
+)
+
+// HTTPPathModifier replaces the full path or the matched prefix
+type HTTPPathModifier struct {
+	Type               HTTPPathModifierType `json:"type"`
+	ReplaceFullPath    *string              `json:"replaceFullPath,omitempty"`
+	ReplacePrefixMatch *string              `json:"replacePrefixMatch,omitempty"`
+}
+
+// HTTPRequestRedirectFilter responds with a redirect
+type HTTPRequestRedirectFilter struct {
+	Scheme     *string           `json:"scheme,omitempty"`
+	Hostname   *string           `json:"hostname,omitempty"`
+	Path       *HTTPPathModifier `json:"path,omitempty"`
+	Port       *int32            `json:"port,omitempty"`
+	StatusCode *int              `json:"statusCode,omitempty"`
+}
+
+// HTTPURLRewriteFilter rewrites the request before it is forwarded
+type HTTPURLRewriteFilter struct {
+	Hostname *string           `json:"hostname,omitempty"`
+	Path     *HTTPPathModifier `json:"path,omitempty"`
+}
+
+// HTTPRequestMirrorFilter mirrors requests to another backend
+type HTTPRequestMirrorFilter struct {
+	BackendRef BackendObjectReference `json:"backendRef"`
+	Percent    *int32                 `json:"percent,omitempty"`
+}
+
+// HTTPBackendRef is a backend of a HTTP route rule
+type HTTPBackendRef struct {
+	BackendRef `json:",inline"`
+	Filters    []HTTPRouteFilter `json:"filters,omitempty"`
+}
+
+// GRPCRoute routes gRPC requests from a gateway listener to backends
+type GRPCRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GRPCRouteSpec `json:"spec,omitempty"`
+}
+
+// GRPCRouteSpec is the desired state of a gRPC route
+type GRPCRouteSpec struct {
+	CommonRouteSpec `json:",inline"`
+	Hostnames       []string        `json:"hostnames,omitempty"`
+	Rules           []GRPCRouteRule `json:"rules,omitempty"`
+}
+
+// GRPCRouteRule matches requests and forwards them to the backends. Any of the matches selects the rule
+type GRPCRouteRule struct {
+	Name        *string           `json:"name,omitempty"`
+	Matches     []GRPCRouteMatch  `json:"matches,omitempty"`
+	Filters     []GRPCRouteFilter `json:"filters,omitempty"`
+	BackendRefs []GRPCBackendRef  `json:"backendRefs,omitempty"`
+}
+
+// GRPCMethodMatchType is how a gRPC service and method are matched
+type GRPCMethodMatchType string
+
+const (
+	GRPCMethodMatchExact             GRPCMethodMatchType = "Exact"
+	GRPCMethodMatchRegularExpression GRPCMethodMatchType = "RegularExpression"
+)
+
+// GRPCRouteMatch holds the predicates of a gRPC request match. All of the predicates must match
+type GRPCRouteMatch struct {
+	Method  *GRPCMethodMatch  `json:"method,omitempty"`
+	Headers []GRPCHeaderMatch `json:"headers,omitempty"`
+}
+
+// GRPCMethodMatch matches the gRPC service and method. An empty service or method matches all
+type GRPCMethodMatch struct {
+	Type    *GRPCMethodMatchType `json:"type,omitempty"`
+	Service *string              `json:"service,omitempty"`
+	Method  *string              `json:"method,omitempty"`
+}
+
+// GRPCHeaderMatch matches a gRPC request header
+type GRPCHeaderMatch struct {
+	Type  *HeaderMatchType `json:"type,omitempty"`
+	Name  string           `json:"name"`
+	Value string           `json:"value"`
+}
+
+// GRPCRouteFilterType is the type of a gRPC route filter
+type GRPCRouteFilterType string
+
+const (
+	GRPCRouteFilterRequestHeaderModifier  GRPCRouteFilterType = "RequestHeaderModifier"
+	GRPCRouteFilterResponseHeaderModifier GRPCRouteFilterType = "ResponseHeaderModifier"
+	GRPCRouteFilterRequestMirror          GRPCRouteFilterType = "RequestMirror"
+	GRPCRouteFilterExtensionRef           GRPCRouteFilterType = "ExtensionRef"
+)
+
+// GRPCRouteFilter modifies a gRPC request or response. The field matching the type must be set
+type GRPCRouteFilter struct {
+	Type                   GRPCRouteFilterType      `json:"type"`
+	RequestHeaderModifier  *HTTPHeaderFilter        `json:"requestHeaderModifier,omitempty"`
+	ResponseHeaderModifier *HTTPHeaderFilter        `json:"responseHeaderModifier,omitempty"`
+	RequestMirror          *HTTPRequestMirrorFilter `json:"requestMirror,omitempty"`
+	ExtensionRef           *LocalObjectReference    `json:"extensionRef,omitempty"`
+}
+
+// GRPCBackendRef is a backend of a gRPC route rule
+type GRPCBackendRef struct {
+	BackendRef `json:",inline"`
+	Filters    []GRPCRouteFilter `json:"filters,omitempty"`
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1alpha1 models the keda.sh/v1alpha1 API types of KEDA.
+// Only the fields needed to build the objects are modelled so kopts doesn't depend on the KEDA module
+package v1alpha1
+
+import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	GroupName  = "keda.sh"
+	APIVersion = GroupName + "/v1alpha1"
+)
+
+// ScaledObject scales a deployment, stateful set or custom resource with the scale subresource
+type ScaledObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ScaledObjectSpec `json:"spec"`
+}
+
+// ScaledObjectSpec is the desired state of a scaled object
+type ScaledObjectSpec struct {
+	ScaleTargetRef   *ScaleTarget    `json:"scaleTargetRef"`
+	PollingInterval  *int32          `json:"pollingInterval,omitempty"`
+	CooldownPeriod   *int32          `json:"cooldownPeriod,omitempty"`
+	IdleReplicaCount *int32          `json:"idleReplicaCount,omitempty"`
+	MinReplicaCount  *int32          `json:"minReplicaCount,omitempty"`
+	MaxReplicaCount  *int32          `json:"maxReplicaCount,omitempty"`
+	Triggers         []ScaleTriggers `json:"triggers"`
+}
+
+// ScaleTarget refers to the scaled object
+type ScaleTarget struct {
+	Name                   string `json:"name"`
+	APIVersion             string `json:"apiVersion,omitempty"`
+	Kind                   string `json:"kind,omitempty"`
+	EnvSourceContainerName string `json:"envSourceContainerName,omitempty"`
+}
+
+// ScaledJob creates jobs from the job template based on the triggers
+type ScaledJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ScaledJobSpec `json:"spec"`
+}
+
+// ScaledJobSpec is the desired state of a scaled job
+type ScaledJobSpec struct {
+	JobTargetRef               *batchv1.JobSpec `json:"jobTargetRef"`
+	PollingInterval            *int32           `json:"pollingInterval,omitempty"`
+	SuccessfulJobsHistoryLimit *int32           `json:"successfulJobsHistoryLimit,omitempty"`
+	FailedJobsHistoryLimit     *int32           `json:"failedJobsHistoryLimit,omitempty"`
+	MinReplicaCount            *int32           `json:"minReplicaCount,omitempty"`
+	MaxReplicaCount            *int32           `json:"maxReplicaCount,omitempty"`
+	Triggers                   []ScaleTriggers  `json:"triggers"`
+}
+
+// ScaleTriggers is a scaler with its metadata. The metadata keys depend on the scaler type
+type ScaleTriggers struct {
+	Type              string                         `json:"type"`
+	Name              string                         `json:"name,omitempty"`
+	Metadata          map[string]string              `json:"metadata"`
+	AuthenticationRef *AuthenticationRef             `json:"authenticationRef,omitempty"`
+	MetricType        autoscalingv2.MetricTargetType `json:"metricType,omitempty"`
+}
+
+// AuthenticationRef refers to a TriggerAuthentication or ClusterTriggerAuthentication
+type AuthenticationRef struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+}
+
+// TriggerAuthentication holds the credentials used by triggers
+type TriggerAuthentication struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TriggerAuthenticationSpec `json:"spec"`
+}
+
+// TriggerAuthenticationSpec holds the sources of the trigger parameters
+type TriggerAuthenticationSpec struct {
+	PodIdentity     *AuthPodIdentity      `json:"podIdentity,omitempty"`
+	SecretTargetRef []AuthSecretTargetRef `json:"secretTargetRef,omitempty"`
+}
+
+// AuthPodIdentity uses the workload identity of the KEDA operator
+type AuthPodIdentity struct {
+	Provider string `json:"provider"`
+}
+
+// AuthSecretTargetRef sets a trigger parameter from a secret key in the same namespace
+type AuthSecretTargetRef struct {
+	Parameter string `json:"parameter"`
+	Name      string `json:"name"`
+	Key       string `json:"key"`
+}
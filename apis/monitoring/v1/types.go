@@ -0,0 +1,118 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1 models the monitoring.coreos.com/v1 API types of the Prometheus Operator.
+// Only the fields needed to build the objects are modelled so kopts doesn't depend on the Prometheus Operator module
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	GroupName  = "monitoring.coreos.com"
+	APIVersion = GroupName + "/v1"
+)
+
+// Duration is a Prometheus duration such as 30s or 1h5m
+type Duration string
+
+// NamespaceSelector selects the namespaces of the scraped objects. Without a selector only the
+// namespace of the monitor is used
+type NamespaceSelector struct {
+	Any        bool     `json:"any,omitempty"`
+	MatchNames []string `json:"matchNames,omitempty"`
+}
+
+// ServiceMonitor scrapes the endpoints of the services matched by the selector
+type ServiceMonitor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ServiceMonitorSpec `json:"spec"`
+}
+
+// ServiceMonitorSpec is the desired state of a service monitor
+type ServiceMonitorSpec struct {
+	JobLabel          string               `json:"jobLabel,omitempty"`
+	Endpoints         []Endpoint           `json:"endpoints"`
+	Selector          metav1.LabelSelector `json:"selector"`
+	NamespaceSelector NamespaceSelector    `json:"namespaceSelector,omitempty"`
+}
+
+// Endpoint is a scraped service port
+type Endpoint struct {
+	Port          string   `json:"port,omitempty"`
+	Path          string   `json:"path,omitempty"`
+	Scheme        string   `json:"scheme,omitempty"`
+	Interval      Duration `json:"interval,omitempty"`
+	ScrapeTimeout Duration `json:"scrapeTimeout,omitempty"`
+	HonorLabels   bool     `json:"honorLabels,omitempty"`
+}
+
+// PodMonitor scrapes the pods matched by the selector
+type PodMonitor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PodMonitorSpec `json:"spec"`
+}
+
+// PodMonitorSpec is the desired state of a pod monitor
+type PodMonitorSpec struct {
+	JobLabel            string               `json:"jobLabel,omitempty"`
+	PodMetricsEndpoints []PodMetricsEndpoint `json:"podMetricsEndpoints,omitempty"`
+	Selector            metav1.LabelSelector `json:"selector"`
+	NamespaceSelector   NamespaceSelector    `json:"namespaceSelector,omitempty"`
+}
+
+// PodMetricsEndpoint is a scraped container port
+type PodMetricsEndpoint struct {
+	Port          string   `json:"port,omitempty"`
+	Path          string   `json:"path,omitempty"`
+	Scheme        string   `json:"scheme,omitempty"`
+	Interval      Duration `json:"interval,omitempty"`
+	ScrapeTimeout Duration `json:"scrapeTimeout,omitempty"`
+	HonorLabels   bool     `json:"honorLabels,omitempty"`
+}
+
+// PrometheusRule holds alerting and recording rules loaded by Prometheus
+type PrometheusRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PrometheusRuleSpec `json:"spec"`
+}
+
+// PrometheusRuleSpec holds the rule groups
+type PrometheusRuleSpec struct {
+	Groups []RuleGroup `json:"groups,omitempty"`
+}
+
+// RuleGroup is a list of rules evaluated sequentially at the same interval
+type RuleGroup struct {
+	Name     string    `json:"name"`
+	Interval *Duration `json:"interval,omitempty"`
+	Rules    []Rule    `json:"rules,omitempty"`
+}
+
+// Rule is an alerting rule when Alert is set or a recording rule when Record is set
+type Rule struct {
+	Record      string            `json:"record,omitempty"`
+	Alert       string            `json:"alert,omitempty"`
+	Expr        string            `json:"expr"`
+	For         *Duration         `json:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
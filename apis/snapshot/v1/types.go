@@ -0,0 +1,64 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1 models the snapshot.storage.k8s.io/v1 API types of the CSI external snapshotter.
+// Only the fields needed to build the objects are modelled so kopts doesn't depend on the snapshotter module
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	GroupName  = "snapshot.storage.k8s.io"
+	APIVersion = GroupName + "/v1"
+)
+
+// DeletionPolicy describes what happens to the snapshot content when the snapshot is deleted
+type DeletionPolicy string
+
+const (
+	VolumeSnapshotContentDelete DeletionPolicy = "Delete"
+	VolumeSnapshotContentRetain DeletionPolicy = "Retain"
+)
+
+// VolumeSnapshotClass holds the CSI driver and parameters used to take snapshots
+type VolumeSnapshotClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Driver         string            `json:"driver"`
+	Parameters     map[string]string `json:"parameters,omitempty"`
+	DeletionPolicy DeletionPolicy    `json:"deletionPolicy"`
+}
+
+// VolumeSnapshot is a user's request for a snapshot of a persistent volume claim
+type VolumeSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VolumeSnapshotSpec `json:"spec"`
+}
+
+// VolumeSnapshotSpec describes the snapshot source and class
+type VolumeSnapshotSpec struct {
+	Source                  VolumeSnapshotSource `json:"source"`
+	VolumeSnapshotClassName *string              `json:"volumeSnapshotClassName,omitempty"`
+}
+
+// VolumeSnapshotSource is either a claim to snapshot or an existing snapshot content. Exactly one must be set
+type VolumeSnapshotSource struct {
+	PersistentVolumeClaimName *string `json:"persistentVolumeClaimName,omitempty"`
+	VolumeSnapshotContentName *string `json:"volumeSnapshotContentName,omitempty"`
+}
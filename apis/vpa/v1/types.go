@@ -0,0 +1,97 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1 models the autoscaling.k8s.io/v1 API types of the Kubernetes vertical pod autoscaler.
+// Only the fields needed to build the objects are modelled so kopts doesn't depend on the autoscaler module
+package v1
+
+import (
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	GroupName  = "autoscaling.k8s.io"
+	APIVersion = GroupName + "/v1"
+)
+
+// DefaultContainerResourcePolicy is the container name of the policy applying to all containers without their own policy
+const DefaultContainerResourcePolicy = "*"
+
+// UpdateMode controls when the recommended resources are applied to the pods
+type UpdateMode string
+
+const (
+	// UpdateModeOff only computes recommendations without changing the pods
+	UpdateModeOff UpdateMode = "Off"
+	// UpdateModeInitial applies the recommendations when pods are created
+	UpdateModeInitial UpdateMode = "Initial"
+	// UpdateModeRecreate applies the recommendations when pods are created and evicts running pods to update them
+	UpdateModeRecreate UpdateMode = "Recreate"
+	// UpdateModeAuto currently behaves like Recreate and will use in-place updates once available
+	UpdateModeAuto UpdateMode = "Auto"
+)
+
+// ContainerScalingMode controls whether the autoscaler scales a container
+type ContainerScalingMode string
+
+const (
+	ContainerScalingModeAuto ContainerScalingMode = "Auto"
+	ContainerScalingModeOff  ContainerScalingMode = "Off"
+)
+
+// ContainerControlledValues controls which resource values the autoscaler sets
+type ContainerControlledValues string
+
+const (
+	ContainerControlledValuesRequestsAndLimits ContainerControlledValues = "RequestsAndLimits"
+	ContainerControlledValuesRequestsOnly      ContainerControlledValues = "RequestsOnly"
+)
+
+// VerticalPodAutoscaler sets the resource requests of the pods of its target
+type VerticalPodAutoscaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VerticalPodAutoscalerSpec `json:"spec"`
+}
+
+// VerticalPodAutoscalerSpec is the desired state of a vertical pod autoscaler
+type VerticalPodAutoscalerSpec struct {
+	TargetRef      *autoscalingv1.CrossVersionObjectReference `json:"targetRef"`
+	UpdatePolicy   *PodUpdatePolicy                           `json:"updatePolicy,omitempty"`
+	ResourcePolicy *PodResourcePolicy                         `json:"resourcePolicy,omitempty"`
+}
+
+// PodUpdatePolicy describes how the recommendations are applied
+type PodUpdatePolicy struct {
+	UpdateMode  *UpdateMode `json:"updateMode,omitempty"`
+	MinReplicas *int32      `json:"minReplicas,omitempty"`
+}
+
+// PodResourcePolicy holds the resource policies of the containers
+type PodResourcePolicy struct {
+	ContainerPolicies []ContainerResourcePolicy `json:"containerPolicies,omitempty"`
+}
+
+// ContainerResourcePolicy bounds the recommendations of a container
+type ContainerResourcePolicy struct {
+	ContainerName       string                     `json:"containerName,omitempty"`
+	Mode                *ContainerScalingMode      `json:"mode,omitempty"`
+	MinAllowed          corev1.ResourceList        `json:"minAllowed,omitempty"`
+	MaxAllowed          corev1.ResourceList        `json:"maxAllowed,omitempty"`
+	ControlledResources *[]corev1.ResourceName     `json:"controlledResources,omitempty"`
+	ControlledValues    *ContainerControlledValues `json:"controlledValues,omitempty"`
+}
@@ -0,0 +1,151 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Kubernetes recommended labels
+const (
+	LabelAppName      = "app.kubernetes.io/name"
+	LabelAppInstance  = "app.kubernetes.io/instance"
+	LabelAppVersion   = "app.kubernetes.io/version"
+	LabelAppComponent = "app.kubernetes.io/component"
+	LabelAppPartOf    = "app.kubernetes.io/part-of"
+	LabelAppManagedBy = "app.kubernetes.io/managed-by"
+)
+
+// App holds the identity of an application, set on each of its objects as the Kubernetes recommended labels.
+// Empty fields are not set
+type App struct {
+	// Name is the name of the application such as mysql
+	Name string
+	// Instance identifies the instance of the application such as mysql-abcxzy
+	Instance string
+	// Version is the current version of the application such as 5.7.21
+	Version string
+	// Component is the role of the component within the application such as database
+	Component string
+	// PartOf is the name of the higher level application this one is part of such as wordpress
+	PartOf string
+	// ManagedBy is the tool managing the application such as helm
+	ManagedBy string
+}
+
+// Labels returns the recommended labels of the application
+func (a App) Labels() map[string]string {
+	labels := a.SelectorLabels()
+	for k, v := range map[string]string{
+		LabelAppVersion:   a.Version,
+		LabelAppPartOf:    a.PartOf,
+		LabelAppManagedBy: a.ManagedBy,
+	} {
+		if v != "" {
+			labels[k] = v
+		}
+	}
+
+	return labels
+}
+
+// SelectorLabels returns the labels identifying the pods of the application. They only include the name, instance
+// and component, which do not change between releases, so selectors stay valid when the version is upgraded
+func (a App) SelectorLabels() map[string]string {
+	labels := make(map[string]string)
+	for k, v := range map[string]string{
+		LabelAppName:      a.Name,
+		LabelAppInstance:  a.Instance,
+		LabelAppComponent: a.Component,
+	} {
+		if v != "" {
+			labels[k] = v
+		}
+	}
+
+	return labels
+}
+
+// MetaApp sets the recommended labels of the application. Deployments, stateful sets and daemon sets also get them
+// on the pod template, and the selector labels on the selector when it is empty. A workload selector that is already
+// set is kept, since the API server rejects selector changes of existing workloads. Jobs and cron jobs get the labels
+// on the pod template, and services and pod disruption budgets get the selector labels on their selector. Pod spec
+// options keep the labels, so MetaApp can be applied before or after them
+func MetaApp(a App) MetaOpt {
+	return func(o metav1.Object) {
+		labels := a.Labels()
+		addAppLabels(labels, o)
+
+		switch o := o.(type) {
+		case *Deployment:
+			o.Spec.Selector = a.workloadSelector(o.Spec.Selector)
+			addAppLabels(labels, &o.Spec.Template.ObjectMeta)
+		case *StatefulSet:
+			o.Spec.Selector = a.workloadSelector(o.Spec.Selector)
+			addAppLabels(labels, &o.Spec.Template.ObjectMeta)
+		case *DaemonSet:
+			o.Spec.Selector = a.workloadSelector(o.Spec.Selector)
+			addAppLabels(labels, &o.Spec.Template.ObjectMeta)
+		case *Job:
+			addAppLabels(labels, &o.Spec.Template.ObjectMeta)
+		case *CronJob:
+			addAppLabels(labels, &o.Spec.JobTemplate.ObjectMeta)
+			addAppLabels(labels, &o.Spec.JobTemplate.Spec.Template.ObjectMeta)
+		case *Service:
+			if o.Spec.Selector == nil {
+				o.Spec.Selector = make(map[string]string)
+			}
+			for k, v := range a.SelectorLabels() {
+				o.Spec.Selector[k] = v
+			}
+		case *PodDisruptionBudget:
+			o.Spec.Selector = a.selector(o.Spec.Selector)
+		}
+	}
+}
+
+// PodApp sets the recommended labels of the application on the pod template
+func PodApp(a App) PodOpt {
+	return func(p *PodSpec) {
+		addAppLabels(a.Labels(), &p.Spec.ObjectMeta)
+	}
+}
+
+func (a App) selector(s *metav1.LabelSelector) *metav1.LabelSelector {
+	if s == nil {
+		s = &metav1.LabelSelector{}
+	}
+	for k, v := range a.SelectorLabels() {
+		s = metav1.AddLabelToSelector(s, k, v)
+	}
+
+	return s
+}
+
+// workloadSelector returns the selector labels of the application when the workload selector is empty, or the
+// workload selector unchanged as it is immutable
+func (a App) workloadSelector(s *metav1.LabelSelector) *metav1.LabelSelector {
+	if !emptySelector(s) {
+		return s
+	}
+
+	return a.selector(s)
+}
+
+func addAppLabels(labels map[string]string, m metav1.Object) {
+	for k, v := range labels {
+		addLabel(k, v, m)
+	}
+}
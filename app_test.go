@@ -0,0 +1,173 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAppLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		app      App
+		labels   map[string]string
+		selector map[string]string
+	}{
+		{
+			name: "all fields",
+			app:  App{Name: "web", Instance: "web-prod", Version: "1.2.0", Component: "frontend", PartOf: "shop", ManagedBy: "kopts"},
+			labels: map[string]string{
+				LabelAppName: "web", LabelAppInstance: "web-prod", LabelAppVersion: "1.2.0",
+				LabelAppComponent: "frontend", LabelAppPartOf: "shop", LabelAppManagedBy: "kopts",
+			},
+			selector: map[string]string{LabelAppName: "web", LabelAppInstance: "web-prod", LabelAppComponent: "frontend"},
+		},
+		{
+			name:     "empty fields are skipped",
+			app:      App{Name: "web", Version: "1.2.0"},
+			labels:   map[string]string{LabelAppName: "web", LabelAppVersion: "1.2.0"},
+			selector: map[string]string{LabelAppName: "web"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.app.Labels(); !reflect.DeepEqual(got, tt.labels) {
+				t.Errorf("Labels() = %v, want %v", got, tt.labels)
+			}
+			if got := tt.app.SelectorLabels(); !reflect.DeepEqual(got, tt.selector) {
+				t.Errorf("SelectorLabels() = %v, want %v", got, tt.selector)
+			}
+		})
+	}
+}
+
+func TestMetaAppKinds(t *testing.T) {
+	app := App{Name: "web", Instance: "web-prod", Version: "1.2.0", PartOf: "shop"}
+	opt := MetaApp(app)
+	labels := app.Labels()
+	selector := app.SelectorLabels()
+
+	deployment := NewDeployment("web", Meta[*Deployment](opt), DeploymentPodSpec(clonePodSpec()))
+	statefulSet := NewStatefulSet("web", Meta[*StatefulSet](opt), StatefulSetPodSpec(clonePodSpec()))
+	daemonSet := NewDaemonSet("web", DaemonSetPodSpec(clonePodSpec()), Meta[*DaemonSet](opt))
+	job := NewJob("web", JobSpec(JobSpecPodSpec(clonePodSpec())), Meta[*Job](opt))
+	cronJob := NewCronJob("web", Meta[*CronJob](opt), CronJobPodSpec(clonePodSpec()))
+	service := NewService("web", ServicePort(80, 8080), Meta[*Service](opt))
+	pdb := NewPodDisruptionBudget("web", Meta[*PodDisruptionBudget](opt))
+
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		template map[string]string
+		selector map[string]string
+	}{
+		{name: "deployment", labels: deployment.Labels, template: deployment.Spec.Template.Labels, selector: deployment.Spec.Selector.MatchLabels},
+		{name: "stateful set", labels: statefulSet.Labels, template: statefulSet.Spec.Template.Labels, selector: statefulSet.Spec.Selector.MatchLabels},
+		{name: "daemon set", labels: daemonSet.Labels, template: daemonSet.Spec.Template.Labels, selector: daemonSet.Spec.Selector.MatchLabels},
+		{name: "job", labels: job.Labels, template: job.Spec.Template.Labels},
+		{name: "cron job", labels: cronJob.Labels, template: cronJob.Spec.JobTemplate.Spec.Template.Labels},
+		{name: "service", labels: service.Labels, selector: service.Spec.Selector},
+		{name: "pod disruption budget", labels: pdb.Labels, selector: pdb.Spec.Selector.MatchLabels},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.labels, labels) {
+				t.Errorf("labels = %v, want %v", tt.labels, labels)
+			}
+			for k, v := range labels {
+				if tt.template != nil && tt.template[k] != v {
+					t.Errorf("pod template label %s = %q, want %q", k, tt.template[k], v)
+				}
+			}
+			if tt.selector != nil && !reflect.DeepEqual(tt.selector, selector) {
+				t.Errorf("selector = %v, want %v without the version, part-of and managed-by labels", tt.selector, selector)
+			}
+		})
+	}
+
+	for _, v := range []Validator{deployment, statefulSet, daemonSet} {
+		if err := v.Validate(); err != nil {
+			t.Errorf("Validate() = %v", err)
+		}
+	}
+}
+
+func TestMetaAppSelector(t *testing.T) {
+	v1 := App{Name: "web", Instance: "web-prod", Version: "1.0.0"}
+	v2 := App{Name: "web", Instance: "web-prod", Version: "2.0.0"}
+
+	tests := []struct {
+		name     string
+		opts     []DeploymentOpt
+		selector map[string]string
+		version  string
+		fields   []string
+	}{
+		{
+			name:     "version upgrade keeps the selector",
+			opts:     []DeploymentOpt{Meta[*Deployment](MetaApp(v1)), Meta[*Deployment](MetaApp(v2))},
+			selector: map[string]string{LabelAppName: "web", LabelAppInstance: "web-prod"},
+			version:  "2.0.0",
+		},
+		{
+			name:     "existing selector is kept",
+			opts:     []DeploymentOpt{DeploymentSelector("app", "web"), DeploymentPodSpec(clonePodSpec().With(PodLabel("app", "web"))), Meta[*Deployment](MetaApp(v2))},
+			selector: map[string]string{"app": "web"},
+			version:  "2.0.0",
+		},
+		{
+			name:     "renamed application keeps the selector",
+			opts:     []DeploymentOpt{Meta[*Deployment](MetaApp(v1)), Meta[*Deployment](MetaApp(App{Name: "site", Version: "1.0.0"}))},
+			selector: map[string]string{LabelAppName: "web", LabelAppInstance: "web-prod"},
+			version:  "1.0.0",
+			fields:   []string{"spec.selector"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDeployment("web", append([]DeploymentOpt{DeploymentPodSpec(clonePodSpec())}, tt.opts...)...)
+
+			if !reflect.DeepEqual(d.Spec.Selector.MatchLabels, tt.selector) {
+				t.Errorf("selector = %v, want %v", d.Spec.Selector.MatchLabels, tt.selector)
+			}
+			if got := d.Spec.Template.Labels[LabelAppVersion]; got != tt.version {
+				t.Errorf("pod template version = %q, want %q", got, tt.version)
+			}
+			if fields := validationFields(t, d.Validate()); !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestMetaAppPodSpecOrder(t *testing.T) {
+	app := App{Name: "web", Version: "1.0.0"}
+
+	after := NewDeployment("web", Meta[*Deployment](MetaApp(app)), DeploymentPodSpec(clonePodSpec()))
+	before := NewDeployment("web", DeploymentPodSpec(clonePodSpec()), Meta[*Deployment](MetaApp(app)))
+	pod := NewDeployment("web", DeploymentPodSpec(NewPodSpec("web", PodApp(app))), Meta[*Deployment](MetaApp(app)))
+
+	for name, d := range map[string]*Deployment{"pod spec after": after, "pod spec before": before, "pod app": pod} {
+		for k, v := range app.Labels() {
+			if got := d.Spec.Template.Labels[k]; got != v {
+				t.Errorf("%s: pod template label %s = %q, want %q", name, k, got, v)
+			}
+		}
+	}
+}
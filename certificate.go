@@ -0,0 +1,276 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	certmanagerv1 "github.com/CoverWhale/kopts/apis/certmanager/v1"
+)
+
+// Certificate holds a cert-manager certificate
+type Certificate struct {
+	certmanagerv1.Certificate
+}
+
+type CertificateOpt func(*Certificate)
+
+// NewCertificate returns a certificate with the given name and options.
+// The secret name defaults to the certificate name with a -tls suffix
+func NewCertificate(name string, opts ...CertificateOpt) Certificate {
+	c := Certificate{
+		Certificate: certmanagerv1.Certificate{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Certificate",
+				APIVersion: certmanagerv1.APIVersion,
+			},
+			ObjectMeta: newObjectMeta(name),
+			Spec: certmanagerv1.CertificateSpec{
+				SecretName: fmt.Sprintf("%s-tls", name),
+			},
+		},
+	}
+
+	for _, v := range opts {
+		v(&c)
+	}
+
+	return c
+}
+
+// CertificateNamespace sets the namespace for the certificate
+func CertificateNamespace(n string) CertificateOpt {
+	return func(c *Certificate) {
+		setNamespace(n, &c.ObjectMeta)
+	}
+}
+
+// CertificateSecretName sets the name of the secret the signed certificate is stored in
+func CertificateSecretName(name string) CertificateOpt {
+	return func(c *Certificate) {
+		c.Spec.SecretName = name
+	}
+}
+
+// CertificateCommonName sets the common name of the certificate
+func CertificateCommonName(name string) CertificateOpt {
+	return func(c *Certificate) {
+		c.Spec.CommonName = name
+	}
+}
+
+// CertificateDNSNames adds DNS subject alternative names to the certificate
+func CertificateDNSNames(names ...string) CertificateOpt {
+	return func(c *Certificate) {
+		c.Spec.DNSNames = append(c.Spec.DNSNames, names...)
+	}
+}
+
+// CertificateIPAddresses adds IP address subject alternative names to the certificate
+func CertificateIPAddresses(addresses ...string) CertificateOpt {
+	return func(c *Certificate) {
+		c.Spec.IPAddresses = append(c.Spec.IPAddresses, addresses...)
+	}
+}
+
+// CertificateDuration sets the requested lifetime of the certificate
+func CertificateDuration(d time.Duration) CertificateOpt {
+	return func(c *Certificate) {
+		c.Spec.Duration = &metav1.Duration{Duration: d}
+	}
+}
+
+// CertificateRenewBefore sets how long before expiry the certificate is renewed
+func CertificateRenewBefore(d time.Duration) CertificateOpt {
+	return func(c *Certificate) {
+		c.Spec.RenewBefore = &metav1.Duration{Duration: d}
+	}
+}
+
+// CertificateIssuer sets the issuer signing the certificate. The certificate is placed in the issuer namespace
+// since an issuer only signs certificates in its own namespace
+func CertificateIssuer(i Issuer) CertificateOpt {
+	name := i.Name
+	namespace := i.Namespace
+	return func(c *Certificate) {
+		setNamespace(namespace, &c.ObjectMeta)
+		c.Spec.IssuerRef = certmanagerv1.ObjectReference{
+			Name:  name,
+			Kind:  "Issuer",
+			Group: certmanagerv1.GroupName,
+		}
+	}
+}
+
+// CertificateClusterIssuer sets the cluster issuer signing the certificate
+func CertificateClusterIssuer(ci ClusterIssuer) CertificateOpt {
+	name := ci.Name
+	return func(c *Certificate) {
+		c.Spec.IssuerRef = certmanagerv1.ObjectReference{
+			Name:  name,
+			Kind:  "ClusterIssuer",
+			Group: certmanagerv1.GroupName,
+		}
+	}
+}
+
+// CertificateIsCA marks the certificate as a CA certificate, for instance to back a CA issuer
+func CertificateIsCA() CertificateOpt {
+	return func(c *Certificate) {
+		c.Spec.IsCA = true
+	}
+}
+
+// CertificateUsages sets the key usages of the certificate
+func CertificateUsages(usages ...certmanagerv1.KeyUsage) CertificateOpt {
+	usages = slices.Clone(usages)
+	return func(c *Certificate) {
+		c.Spec.Usages = slices.Clone(usages)
+	}
+}
+
+// CertificatePrivateKeyAlgorithm sets the private key algorithm and size. The size is the RSA key length
+// in bits or the ECDSA curve size and is ignored for Ed25519
+func CertificatePrivateKeyAlgorithm(a certmanagerv1.PrivateKeyAlgorithm, size int) CertificateOpt {
+	return func(c *Certificate) {
+		c.privateKey().Algorithm = a
+		c.privateKey().Size = size
+	}
+}
+
+// CertificatePrivateKeyEncoding sets the encoding of the private key
+func CertificatePrivateKeyEncoding(e certmanagerv1.PrivateKeyEncoding) CertificateOpt {
+	return func(c *Certificate) {
+		c.privateKey().Encoding = e
+	}
+}
+
+// CertificatePrivateKeyRotationPolicy sets whether a new private key is generated when the certificate is reissued
+func CertificatePrivateKeyRotationPolicy(p certmanagerv1.PrivateKeyRotationPolicy) CertificateOpt {
+	return func(c *Certificate) {
+		c.privateKey().RotationPolicy = p
+	}
+}
+
+func (c *Certificate) privateKey() *certmanagerv1.CertificatePrivateKey {
+	if c.Spec.PrivateKey == nil {
+		c.Spec.PrivateKey = &certmanagerv1.CertificatePrivateKey{}
+	}
+	return c.Spec.PrivateKey
+}
+
+// Apply applies the options to the certificate
+func (c *Certificate) Apply(opts ...CertificateOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// With returns a copy of the certificate with the options applied
+func (c Certificate) With(opts ...CertificateOpt) Certificate {
+	clone := c.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the certificate
+func (c Certificate) Clone() Certificate {
+	return Certificate{Certificate: deepCopyJSON(c.Certificate)}
+}
+
+// Validate checks the metadata, the secret name and issuer, that at least one subject is set, the DNS names and IP
+// addresses, the duration and renewal window and the private key settings
+func (c Certificate) Validate() error {
+	errs := validateObjectMeta(&c.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateDNSSubdomain(c.Spec.SecretName, path.Child("secretName"))...)
+	if c.Spec.IssuerRef.Name == "" {
+		errs = append(errs, field.Required(path.Child("issuerRef", "name"), ""))
+	}
+	errs = append(errs, validateEnum(c.Spec.IssuerRef.Kind, path.Child("issuerRef", "kind"), "Issuer", "ClusterIssuer")...)
+
+	if c.Spec.CommonName == "" && len(c.Spec.DNSNames) == 0 && len(c.Spec.IPAddresses) == 0 {
+		errs = append(errs, field.Required(path, "at least one of commonName, dnsNames or ipAddresses must be set"))
+	}
+	if len(c.Spec.CommonName) > 64 {
+		errs = append(errs, field.TooLong(path.Child("commonName"), c.Spec.CommonName, 64))
+	}
+	for i, n := range c.Spec.DNSNames {
+		errs = append(errs, validateHostname(n, path.Child("dnsNames").Index(i))...)
+	}
+	for i, ip := range c.Spec.IPAddresses {
+		if net.ParseIP(ip) == nil {
+			errs = append(errs, field.Invalid(path.Child("ipAddresses").Index(i), ip, "must be a valid IP address"))
+		}
+	}
+
+	duration := 90 * 24 * time.Hour
+	if c.Spec.Duration != nil {
+		duration = c.Spec.Duration.Duration
+		if duration < time.Hour {
+			errs = append(errs, field.Invalid(path.Child("duration"), duration.String(), "must be at least 1h"))
+		}
+	}
+	if c.Spec.RenewBefore != nil {
+		renewBefore := c.Spec.RenewBefore.Duration
+		switch {
+		case renewBefore < 5*time.Minute:
+			errs = append(errs, field.Invalid(path.Child("renewBefore"), renewBefore.String(), "must be at least 5m"))
+		case renewBefore >= duration:
+			errs = append(errs, field.Invalid(path.Child("renewBefore"), renewBefore.String(),
+				fmt.Sprintf("must be less than the duration of %s", duration)))
+		}
+	}
+
+	if k := c.Spec.PrivateKey; k != nil {
+		keyPath := path.Child("privateKey")
+		errs = append(errs, validateEnum(k.Algorithm, keyPath.Child("algorithm"),
+			certmanagerv1.RSAKeyAlgorithm, certmanagerv1.ECDSAKeyAlgorithm, certmanagerv1.Ed25519KeyAlgorithm)...)
+		errs = append(errs, validateEnum(k.Encoding, keyPath.Child("encoding"), certmanagerv1.PKCS1, certmanagerv1.PKCS8)...)
+		errs = append(errs, validateEnum(k.RotationPolicy, keyPath.Child("rotationPolicy"),
+			certmanagerv1.RotationPolicyNever, certmanagerv1.RotationPolicyAlways)...)
+		errs = append(errs, validatePrivateKeySize(k.Algorithm, k.Size, keyPath.Child("size"))...)
+	}
+
+	return toAggregate(errs)
+}
+
+func validatePrivateKeySize(a certmanagerv1.PrivateKeyAlgorithm, size int, path *field.Path) field.ErrorList {
+	if size == 0 {
+		return nil
+	}
+
+	switch a {
+	case "", certmanagerv1.RSAKeyAlgorithm:
+		if size < 2048 || size > 8192 {
+			return field.ErrorList{field.Invalid(path, size, "must be between 2048 and 8192 for RSA keys")}
+		}
+	case certmanagerv1.ECDSAKeyAlgorithm:
+		if size != 256 && size != 384 && size != 521 {
+			return field.ErrorList{field.NotSupported(path, size, []string{"256", "384", "521"})}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	certmanagerv1 "github.com/CoverWhale/kopts/apis/certmanager/v1"
+)
+
+func TestCertificate(t *testing.T) {
+	issuer := NewIssuer("internal-ca", IssuerNamespace("pki"), IssuerSpec(IssuerSpecCA("ca-key-pair")))
+	usages := []certmanagerv1.KeyUsage{certmanagerv1.UsageDigitalSignature, certmanagerv1.UsageServerAuth}
+	c := NewCertificate("api",
+		CertificateNamespace("apps"),
+		CertificateIssuer(issuer),
+		CertificateDNSNames("api.example.com", "*.api.example.com"),
+		CertificateDuration(720*time.Hour),
+		CertificateRenewBefore(240*time.Hour),
+		CertificateUsages(usages...),
+		CertificatePrivateKeyAlgorithm(certmanagerv1.ECDSAKeyAlgorithm, 384),
+		CertificatePrivateKeyRotationPolicy(certmanagerv1.RotationPolicyAlways),
+	)
+	usages[0] = certmanagerv1.UsageCertSign
+
+	if c.Spec.SecretName != "api-tls" {
+		t.Errorf("secretName = %q, want api-tls", c.Spec.SecretName)
+	}
+	if c.Namespace != "pki" {
+		t.Errorf("namespace = %q, want the issuer namespace pki", c.Namespace)
+	}
+	if want := (certmanagerv1.ObjectReference{Name: "internal-ca", Kind: "Issuer", Group: certmanagerv1.GroupName}); c.Spec.IssuerRef != want {
+		t.Errorf("issuerRef = %+v, want %+v", c.Spec.IssuerRef, want)
+	}
+	if want := []certmanagerv1.KeyUsage{certmanagerv1.UsageDigitalSignature, certmanagerv1.UsageServerAuth}; !reflect.DeepEqual(c.Spec.Usages, want) {
+		t.Errorf("usages = %v after changing the caller's slice, want %v", c.Spec.Usages, want)
+	}
+	if k := c.Spec.PrivateKey; k.Algorithm != certmanagerv1.ECDSAKeyAlgorithm || k.Size != 384 || k.RotationPolicy != certmanagerv1.RotationPolicyAlways {
+		t.Errorf("privateKey = %+v, want ECDSA 384 rotated always", *k)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestCertificateClusterIssuer(t *testing.T) {
+	c := NewCertificate("api", CertificateNamespace("apps"), CertificateClusterIssuer(NewClusterIssuer("letsencrypt-staging")))
+
+	if c.Namespace != "apps" {
+		t.Errorf("namespace = %q, want apps", c.Namespace)
+	}
+	if c.Spec.IssuerRef.Kind != "ClusterIssuer" || c.Spec.IssuerRef.Name != "letsencrypt-staging" {
+		t.Errorf("issuerRef = %+v, want the letsencrypt-staging cluster issuer", c.Spec.IssuerRef)
+	}
+}
+
+func TestCertificateValidate(t *testing.T) {
+	issuer := CertificateClusterIssuer(NewClusterIssuer("letsencrypt-prod"))
+	hosts := CertificateDNSNames("api.example.com")
+
+	tests := []struct {
+		name   string
+		opts   []CertificateOpt
+		fields []string
+	}{
+		{
+			name:   "without issuer",
+			opts:   []CertificateOpt{hosts},
+			fields: []string{"spec.issuerRef.name"},
+		},
+		{
+			name:   "without subject",
+			opts:   []CertificateOpt{issuer},
+			fields: []string{"spec"},
+		},
+		{
+			name:   "invalid names and addresses",
+			opts:   []CertificateOpt{issuer, CertificateDNSNames("api_example.com"), CertificateIPAddresses("10.0.0.300")},
+			fields: []string{"spec.dnsNames[0]", "spec.ipAddresses[0]"},
+		},
+		{
+			name:   "renewal after expiry",
+			opts:   []CertificateOpt{issuer, hosts, CertificateDuration(24 * time.Hour), CertificateRenewBefore(48 * time.Hour)},
+			fields: []string{"spec.renewBefore"},
+		},
+		{
+			name:   "renewal under the minimum",
+			opts:   []CertificateOpt{issuer, hosts, CertificateRenewBefore(time.Minute)},
+			fields: []string{"spec.renewBefore"},
+		},
+		{
+			name:   "short duration",
+			opts:   []CertificateOpt{issuer, hosts, CertificateDuration(30 * time.Minute)},
+			fields: []string{"spec.duration"},
+		},
+		{
+			name:   "RSA key too small",
+			opts:   []CertificateOpt{issuer, hosts, CertificatePrivateKeyAlgorithm(certmanagerv1.RSAKeyAlgorithm, 1024)},
+			fields: []string{"spec.privateKey.size"},
+		},
+		{
+			name:   "unsupported ECDSA curve",
+			opts:   []CertificateOpt{issuer, hosts, CertificatePrivateKeyAlgorithm(certmanagerv1.ECDSAKeyAlgorithm, 512)},
+			fields: []string{"spec.privateKey.size"},
+		},
+		{
+			name:   "Ed25519 ignores the size",
+			opts:   []CertificateOpt{issuer, hosts, CertificatePrivateKeyAlgorithm(certmanagerv1.Ed25519KeyAlgorithm, 256)},
+			fields: nil,
+		},
+		{
+			name:   "unknown encoding",
+			opts:   []CertificateOpt{issuer, hosts, CertificatePrivateKeyEncoding("PEM")},
+			fields: []string{"spec.privateKey.encoding"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCertificate("api", append([]CertificateOpt{CertificateNamespace("apps")}, tt.opts...)...)
+			fields := validationFields(t, c.Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func clonePodSpec() PodSpec {
+	return NewPodSpec("web", PodContainer(NewContainer("web", ContainerImage("nginx:1.25"), ContainerArgs([]string{"-g", "daemon off;"}))))
+}
+
+func TestClone(t *testing.T) {
+	nodeSelector := map[string]string{"pool": "general"}
+	tolerations := []Toleration{{Key: "dedicated", Value: "web", Operator: corev1.TolerationOpEqual, Effect: corev1.TaintEffectNoSchedule}}
+
+	deployment := NewDeployment("web", DeploymentLabel("app", "web"), DeploymentSelector("app", "web"),
+		DeploymentPodSpec(clonePodSpec()), DeploymentNodeSelector(nodeSelector), DeploymentTolerations(tolerations))
+	statefulSet := NewStatefulSet("web", StatefulSetLabel("app", "web"), StatefulSetSelector("app", "web"),
+		StatefulSetPodSpec(clonePodSpec()), StatefulSetNodeSelector(nodeSelector))
+	daemonSet := NewDaemonSet("web", DaemonSetLabel("app", "web"), DaemonSetSelector("app", "web"), DaemonSetPodSpec(clonePodSpec()))
+	job := NewJob("web", Meta[*Job](MetaLabel("app", "web")), JobSpec(JobSpecPodSpec(clonePodSpec())))
+	cronJob := NewCronJob("web", Meta[*CronJob](MetaLabel("app", "web")), CronJobPodSpec(clonePodSpec()))
+	service := NewService("web", ServiceLabel("app", "web"), ServiceSelector("app", "web"))
+	pdb := NewPodDisruptionBudget("web", Meta[*PodDisruptionBudget](MetaLabel("app", "web")), PodDisruptionBudgetSelector("app", "web"))
+	networkPolicy := NewNetworkPolicy("web", Meta[*NetworkPolicy](MetaLabel("app", "web")), NetworkPolicyPodSelector("app", "web"))
+
+	tests := []struct {
+		name            string
+		original, clone metav1.Object
+		// podSpec returns the pod spec of a workload, nil for other kinds
+		podSpec func(metav1.Object) *corev1.PodSpec
+		// selector returns the selector labels, nil for kinds without a selector
+		selector func(metav1.Object) map[string]string
+	}{
+		{
+			name:     "deployment",
+			original: deployment,
+			clone:    deployment.Clone(),
+			podSpec:  func(o metav1.Object) *corev1.PodSpec { return &o.(*Deployment).Spec.Template.Spec },
+			selector: func(o metav1.Object) map[string]string { return o.(*Deployment).Spec.Selector.MatchLabels },
+		},
+		{
+			name:     "deployment with options",
+			original: deployment,
+			clone:    deployment.With(DeploymentReplicas(3)),
+			podSpec:  func(o metav1.Object) *corev1.PodSpec { return &o.(*Deployment).Spec.Template.Spec },
+			selector: func(o metav1.Object) map[string]string { return o.(*Deployment).Spec.Selector.MatchLabels },
+		},
+		{
+			name:     "stateful set",
+			original: statefulSet,
+			clone:    statefulSet.Clone(),
+			podSpec:  func(o metav1.Object) *corev1.PodSpec { return &o.(*StatefulSet).Spec.Template.Spec },
+			selector: func(o metav1.Object) map[string]string { return o.(*StatefulSet).Spec.Selector.MatchLabels },
+		},
+		{
+			name:     "daemon set",
+			original: daemonSet,
+			clone:    daemonSet.Clone(),
+			podSpec:  func(o metav1.Object) *corev1.PodSpec { return &o.(*DaemonSet).Spec.Template.Spec },
+			selector: func(o metav1.Object) map[string]string { return o.(*DaemonSet).Spec.Selector.MatchLabels },
+		},
+		{
+			name:     "job",
+			original: job,
+			clone:    job.Clone(),
+			podSpec:  func(o metav1.Object) *corev1.PodSpec { return &o.(*Job).Spec.Template.Spec },
+		},
+		{
+			name:     "cron job",
+			original: cronJob,
+			clone:    cronJob.Clone(),
+			podSpec:  func(o metav1.Object) *corev1.PodSpec { return &o.(*CronJob).Spec.JobTemplate.Spec.Template.Spec },
+		},
+		{
+			name:     "service",
+			original: &service,
+			clone:    ptrTo(service.Clone()),
+			selector: func(o metav1.Object) map[string]string { return o.(*Service).Spec.Selector },
+		},
+		{
+			name:     "pod disruption budget",
+			original: &pdb,
+			clone:    ptrTo(pdb.Clone()),
+			selector: func(o metav1.Object) map[string]string { return o.(*PodDisruptionBudget).Spec.Selector.MatchLabels },
+		},
+		{
+			name:     "network policy",
+			original: &networkPolicy,
+			clone:    ptrTo(networkPolicy.Clone()),
+			selector: func(o metav1.Object) map[string]string { return o.(*NetworkPolicy).Spec.PodSelector.MatchLabels },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.clone.GetLabels()["app"] = "changed"
+			if got := tt.original.GetLabels()["app"]; got != "web" {
+				t.Errorf("original app label = %q after changing the clone, want web", got)
+			}
+
+			if tt.selector != nil {
+				tt.selector(tt.clone)["app"] = "changed"
+				if got := tt.selector(tt.original)["app"]; got != "web" {
+					t.Errorf("original selector app = %q after changing the clone, want web", got)
+				}
+			}
+
+			if tt.podSpec != nil {
+				spec := tt.podSpec(tt.clone)
+				spec.Containers[0].Image = "changed"
+				spec.Containers[0].Args[0] = "changed"
+				spec.Containers = append(spec.Containers, corev1.Container{Name: "sidecar"})
+
+				original := tt.podSpec(tt.original)
+				if c := original.Containers; len(c) != 1 || c[0].Image != "nginx:1.25" || c[0].Args[0] != "-g" {
+					t.Errorf("original containers = %+v after changing the clone, want the nginx:1.25 container", c)
+				}
+				if spec.NodeSelector != nil {
+					spec.NodeSelector["pool"] = "changed"
+					if got := original.NodeSelector["pool"]; got != "general" {
+						t.Errorf("original node selector pool = %q after changing the clone, want general", got)
+					}
+				}
+				if len(spec.Tolerations) > 0 {
+					spec.Tolerations[0].Value = "changed"
+					if got := original.Tolerations[0].Value; got != "web" {
+						t.Errorf("original toleration value = %q after changing the clone, want web", got)
+					}
+				}
+			}
+		})
+	}
+
+	if deployment.Spec.Replicas != nil {
+		t.Errorf("original replicas = %d after With, want unset", *deployment.Spec.Replicas)
+	}
+	nodeSelector["pool"] = "changed"
+	tolerations[0].Value = "changed"
+	if got := deployment.Spec.Template.Spec.NodeSelector["pool"]; got != "general" {
+		t.Errorf("node selector pool = %q after changing the caller's map, want general", got)
+	}
+	if got := deployment.Spec.Template.Spec.Tolerations[0].Value; got != "web" {
+		t.Errorf("toleration value = %q after changing the caller's slice, want web", got)
+	}
+}
+
+func TestCloneSharedOptions(t *testing.T) {
+	args := []string{"serve"}
+	container := NewContainer("api", ContainerImage("api:1.0"), ContainerArgs(args), ContainerCommands([]string{"/api"}))
+	args[0] = "changed"
+	if got := container.Args[0]; got != "serve" {
+		t.Errorf("args = %q after changing the caller's slice, want serve", got)
+	}
+
+	replicas := DeploymentReplicas(2)
+	pod := PodContainer(container)
+	first := NewDeployment("first", replicas, DeploymentPodSpec(NewPodSpec("first", pod)))
+	*first.Spec.Replicas = 10
+	first.Spec.Template.Spec.Containers[0].Command[0] = "changed"
+
+	second := NewDeployment("second", replicas, DeploymentPodSpec(NewPodSpec("second", pod)))
+	if got := ptrValue(second.Spec.Replicas); got != 2 {
+		t.Errorf("replicas = %d for a second deployment, want 2", got)
+	}
+	if got := second.Spec.Template.Spec.Containers[0].Command[0]; got != "/api" {
+		t.Errorf("command = %q for a second deployment, want /api", got)
+	}
+	if got := container.Command[0]; got != "/api" {
+		t.Errorf("container command = %q after changing the deployment, want /api", got)
+	}
+
+	immutable := ConfigMapImmutable(true)
+	cm := NewConfigMap("settings", immutable)
+	*cm.Immutable = false
+	if got := ptrValue(NewConfigMap("second", immutable).Immutable); !got {
+		t.Error("immutable = false for a second config map, want true")
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"maps"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/validation/path"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Default user facing cluster roles that other cluster roles can aggregate to
+const (
+	AggregateToAdmin = "admin"
+	AggregateToEdit  = "edit"
+	AggregateToView  = "view"
+)
+
+// ClusterRole is a Kubernetes cluster role
+type ClusterRole struct {
+	rbacv1.ClusterRole
+}
+
+type ClusterRoleOpt func(*ClusterRole)
+
+// NewClusterRole returns a cluster role with the given name and options
+func NewClusterRole(name string, opts ...ClusterRoleOpt) ClusterRole {
+	r := ClusterRole{
+		ClusterRole: rbacv1.ClusterRole{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ClusterRole",
+				APIVersion: "rbac.authorization.k8s.io/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		},
+	}
+
+	for _, v := range opts {
+		v(&r)
+	}
+
+	return r
+}
+
+// Set single cluster role label
+func ClusterRoleLabel(key, value string) ClusterRoleOpt {
+	return func(r *ClusterRole) {
+		addLabel(key, value, &r.ObjectMeta)
+	}
+}
+
+// Set cluster role policy rule
+func ClusterRolePolicyRule(pr PolicyRule) ClusterRoleOpt {
+	return clusterRolePolicyRules(pr)
+}
+
+// Set multiple cluster role policy rules
+func ClusterRolePolicyRules(pr []PolicyRule) ClusterRoleOpt {
+	return clusterRolePolicyRules(pr...)
+}
+
+func clusterRolePolicyRules(pr ...PolicyRule) ClusterRoleOpt {
+	return func(r *ClusterRole) {
+		for _, v := range pr {
+			r.Rules = append(r.Rules, *v.PolicyRule.DeepCopy())
+		}
+	}
+}
+
+// Add a cluster role selector to the aggregation rule. The rules of every cluster role
+// matching the labels are aggregated into this cluster role by the controller manager
+func ClusterRoleAggregationSelector(labels map[string]string) ClusterRoleOpt {
+	return func(r *ClusterRole) {
+		if r.AggregationRule == nil {
+			r.AggregationRule = &rbacv1.AggregationRule{}
+		}
+		r.AggregationRule.ClusterRoleSelectors = append(r.AggregationRule.ClusterRoleSelectors, metav1.LabelSelector{
+			MatchLabels: maps.Clone(labels),
+		})
+	}
+}
+
+// Aggregate the cluster role rules to another cluster role such as AggregateToAdmin
+// by setting the rbac.authorization.k8s.io/aggregate-to-<role> label
+func ClusterRoleAggregateTo(role string) ClusterRoleOpt {
+	return ClusterRoleLabel(AggregateToLabel(role), "true")
+}
+
+// AggregateToLabel returns the aggregation label key for the cluster role
+func AggregateToLabel(role string) string {
+	return fmt.Sprintf("rbac.authorization.k8s.io/aggregate-to-%s", role)
+}
+
+// Apply applies the options to the cluster role
+func (cr *ClusterRole) Apply(opts ...ClusterRoleOpt) {
+	for _, opt := range opts {
+		opt(cr)
+	}
+}
+
+// With returns a copy of the cluster role with the options applied
+func (cr ClusterRole) With(opts ...ClusterRoleOpt) ClusterRole {
+	clone := cr.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the cluster role
+func (cr ClusterRole) Clone() ClusterRole {
+	return ClusterRole{ClusterRole: *cr.ClusterRole.DeepCopy()}
+}
+
+// Validate checks the metadata, the policy rules and the aggregation selectors
+func (cr ClusterRole) Validate() error {
+	errs := validateObjectMeta(&cr.ObjectMeta, false, path.ValidatePathSegmentName)
+	for i, pr := range cr.Rules {
+		errs = append(errs, validatePolicyRule(pr, false, field.NewPath("rules").Index(i))...)
+	}
+
+	if cr.AggregationRule != nil {
+		selectorsPath := field.NewPath("aggregationRule", "clusterRoleSelectors")
+		for i, s := range cr.AggregationRule.ClusterRoleSelectors {
+			errs = append(errs, metav1validation.ValidateLabelSelector(&s, metav1validation.LabelSelectorValidationOptions{}, selectorsPath.Index(i))...)
+		}
+	}
+
+	return toAggregate(errs)
+}
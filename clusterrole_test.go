@@ -0,0 +1,122 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestClusterRoleAggregation(t *testing.T) {
+	selectorLabels := map[string]string{"example.com/aggregate-to-monitoring": "true"}
+	monitoring := NewClusterRole("monitoring", ClusterRoleAggregationSelector(selectorLabels))
+	widgets := NewClusterRole("widgets-view",
+		ClusterRoleAggregateTo(AggregateToView),
+		ClusterRoleLabel("example.com/aggregate-to-monitoring", "true"),
+		ClusterRolePolicyRule(NewPolicyRule("widgets", PolicyRuleAPIGroup("example.com"), PolicyRuleResource("widgets"), PolicyRuleVerbs([]Verb{Get, List, Watch}))),
+	)
+
+	selectorLabels["example.com/aggregate-to-monitoring"] = "false"
+	want := &rbacv1.AggregationRule{ClusterRoleSelectors: []metav1.LabelSelector{
+		{MatchLabels: map[string]string{"example.com/aggregate-to-monitoring": "true"}},
+	}}
+	if !reflect.DeepEqual(monitoring.AggregationRule, want) {
+		t.Errorf("aggregationRule = %+v, want %+v", monitoring.AggregationRule, want)
+	}
+
+	wantLabels := map[string]string{
+		"rbac.authorization.k8s.io/aggregate-to-view": "true",
+		"example.com/aggregate-to-monitoring":         "true",
+	}
+	if !reflect.DeepEqual(widgets.Labels, wantLabels) {
+		t.Errorf("labels = %v, want %v", widgets.Labels, wantLabels)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&monitoring.AggregationRule.ClusterRoleSelectors[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !selector.Matches(labels.Set(widgets.Labels)) {
+		t.Error("the aggregation selector does not match the aggregated cluster role")
+	}
+}
+
+func TestClusterRoleBindingRoleRef(t *testing.T) {
+	cr := NewClusterRole("widgets-view")
+	subjects := []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "widgets", Namespace: "apps"}}
+
+	crb := NewClusterRoleBinding("widgets-view", ClusterRoleBindingClusterRole(cr), ClusterRoleBindingSubjects(subjects))
+	rb := NewRoleBinding("widgets-view", RoleBindingNamespace("apps"), RoleBindingClusterRole(cr))
+
+	want := rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "widgets-view"}
+	if crb.RoleRef != want || rb.RoleRef != want {
+		t.Errorf("roleRefs = %+v and %+v, want %+v", crb.RoleRef, rb.RoleRef, want)
+	}
+
+	subjects[0].Name = "other"
+	if crb.Subjects[0].Name != "widgets" {
+		t.Errorf("subject name = %q after changing the caller's slice, want widgets", crb.Subjects[0].Name)
+	}
+}
+
+func TestClusterRoleValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		object Validator
+		fields []string
+	}{
+		{
+			name: "non-resource URL rule",
+			object: NewClusterRole("metrics", ClusterRolePolicyRule(NewPolicyRule("metrics",
+				PolicyRuleNonResourceURL("/metrics"), PolicyRuleVerb(Get)))),
+		},
+		{
+			name:   "rule without verbs",
+			object: NewClusterRole("widgets", ClusterRolePolicyRule(NewPolicyRule("widgets", PolicyRuleAPIGroup(""), PolicyRuleResource("pods")))),
+			fields: []string{"rules[0].verbs"},
+		},
+		{
+			name:   "invalid aggregation selector",
+			object: NewClusterRole("monitoring", ClusterRoleAggregationSelector(map[string]string{"-bad": "true"})),
+			fields: []string{"aggregationRule.clusterRoleSelectors[0].matchLabels"},
+		},
+		{
+			name: "binding to a role",
+			object: NewClusterRoleBinding("widgets", func(c *ClusterRoleBinding) {
+				c.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "widgets"}
+			}),
+			fields: []string{"roleRef.kind"},
+		},
+		{
+			name: "service account without namespace",
+			object: NewClusterRoleBinding("widgets", ClusterRoleBindingClusterRole(NewClusterRole("widgets")),
+				ClusterRoleBindingSubject(rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "widgets"})),
+			fields: []string{"subjects[0].namespace"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, tt.object.Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"slices"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/validation/path"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ClusterRoleBinding is a Kubernetes cluster role binding
+type ClusterRoleBinding struct {
+	rbacv1.ClusterRoleBinding
+}
+
+type ClusterRoleBindingOpt func(*ClusterRoleBinding)
+
+// NewClusterRoleBinding returns a cluster role binding with the given name and options
+func NewClusterRoleBinding(name string, opts ...ClusterRoleBindingOpt) ClusterRoleBinding {
+	rb := ClusterRoleBinding{
+		ClusterRoleBinding: rbacv1.ClusterRoleBinding{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ClusterRoleBinding",
+				APIVersion: "rbac.authorization.k8s.io/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		},
+	}
+
+	for _, v := range opts {
+		v(&rb)
+	}
+
+	return rb
+}
+
+// Set cluster role binding subject
+func ClusterRoleBindingSubject(s rbacv1.Subject) ClusterRoleBindingOpt {
+	return clusterRoleBindingSubject(s)
+}
+
+// Set multiple cluster role binding subjects
+func ClusterRoleBindingSubjects(s []rbacv1.Subject) ClusterRoleBindingOpt {
+	return clusterRoleBindingSubject(s...)
+}
+
+func clusterRoleBindingSubject(s ...rbacv1.Subject) ClusterRoleBindingOpt {
+	return func(r *ClusterRoleBinding) {
+		r.Subjects = slices.Clone(s)
+	}
+}
+
+// Set the cluster role the binding refers to
+func ClusterRoleBindingClusterRole(cr ClusterRole) ClusterRoleBindingOpt {
+	return func(r *ClusterRoleBinding) {
+		r.RoleRef = clusterRoleRef(cr)
+	}
+}
+
+func clusterRoleRef(cr ClusterRole) rbacv1.RoleRef {
+	return rbacv1.RoleRef{
+		APIGroup: rbacv1.GroupName,
+		Kind:     "ClusterRole",
+		Name:     cr.Name,
+	}
+}
+
+// Apply applies the options to the cluster role binding
+func (c *ClusterRoleBinding) Apply(opts ...ClusterRoleBindingOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// With returns a copy of the cluster role binding with the options applied
+func (c ClusterRoleBinding) With(opts ...ClusterRoleBindingOpt) ClusterRoleBinding {
+	clone := c.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the cluster role binding
+func (c ClusterRoleBinding) Clone() ClusterRoleBinding {
+	return ClusterRoleBinding{ClusterRoleBinding: *c.ClusterRoleBinding.DeepCopy()}
+}
+
+// Validate checks the metadata, that the role reference points to a ClusterRole and that the subjects are valid
+func (c ClusterRoleBinding) Validate() error {
+	errs := validateObjectMeta(&c.ObjectMeta, false, path.ValidatePathSegmentName)
+	errs = append(errs, validateRoleRef(c.RoleRef, field.NewPath("roleRef"), "ClusterRole")...)
+	for i, s := range c.Subjects {
+		errs = append(errs, validateSubject(s, false, field.NewPath("subjects").Index(i))...)
+	}
+
+	return toAggregate(errs)
+}
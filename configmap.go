@@ -16,7 +16,9 @@ package kopts
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 type ConfigMap struct {
@@ -56,7 +58,7 @@ func ConfigMapNamespace(n string) ConfigMapOpt {
 // Set if configmap is immutable
 func ConfigMapImmutable(b bool) ConfigMapOpt {
 	return func(c *ConfigMap) {
-		c.ConfigMap.Immutable = &b
+		c.ConfigMap.Immutable = ptrTo(b)
 	}
 }
 
@@ -102,3 +104,38 @@ func (c *ConfigMap) AsVolumeSource() corev1.VolumeSource {
 		},
 	}
 }
+
+// Apply applies the options to the config map
+func (c *ConfigMap) Apply(opts ...ConfigMapOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// With returns a copy of the config map with the options applied
+func (c ConfigMap) With(opts ...ConfigMapOpt) ConfigMap {
+	clone := c.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the config map
+func (c ConfigMap) Clone() ConfigMap {
+	return ConfigMap{ConfigMap: *c.ConfigMap.DeepCopy()}
+}
+
+// Validate checks the metadata and that the data keys are valid and not set in both data and binaryData
+func (c ConfigMap) Validate() error {
+	errs := validateObjectMeta(&c.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	errs = append(errs, validateDataKeys(c.Data, field.NewPath("data"))...)
+	errs = append(errs, validateDataKeys(c.BinaryData, field.NewPath("binaryData"))...)
+
+	for k := range c.BinaryData {
+		if _, ok := c.Data[k]; ok {
+			errs = append(errs, field.Duplicate(field.NewPath("binaryData").Key(k), k))
+		}
+	}
+
+	return toAggregate(errs)
+}
@@ -15,6 +15,8 @@
 package kopts
 
 import (
+	"slices"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -95,7 +97,7 @@ func ContainerEnvFromConfigMap(configmap, name, key string) ContainerOpt {
 // Set container commands
 func ContainerCommands(commands []string) ContainerOpt {
 	return func(c *Container) {
-		c.Command = commands
+		c.Command = slices.Clone(commands)
 	}
 }
 
@@ -109,7 +111,7 @@ func ContainerImagePullPolicy(policy corev1.PullPolicy) ContainerOpt {
 // Set Container args
 func ContainerArgs(args []string) ContainerOpt {
 	return func(c *Container) {
-		c.Args = args
+		c.Args = slices.Clone(args)
 	}
 }
 
@@ -124,6 +126,8 @@ func ContainerPort(name string, port int) ContainerOpt {
 }
 
 // Add container Volume
+//
+// Deprecated: the persistent volume name is not a pod volume. Use ContainerVolumeClaim with PodVolumeClaim instead
 func ContainerVolume(path string, pv PersistentVolume) ContainerOpt {
 	return func(c *Container) {
 		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
@@ -133,6 +137,17 @@ func ContainerVolume(path string, pv PersistentVolume) ContainerOpt {
 	}
 }
 
+// Mount a persistent volume claim in the container. The volume name matches the one set by PodVolumeClaim
+func ContainerVolumeClaim(path string, pvc PersistentVolumeClaim) ContainerOpt {
+	name := pvc.volumeName()
+	return func(c *Container) {
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			MountPath: path,
+			Name:      name,
+		})
+	}
+}
+
 // Mount a volume source in the container
 func ContainerVolumeSource(name, mountPath string, vs corev1.VolumeSource) ContainerOpt {
 	return func(c *Container) {
@@ -167,3 +182,28 @@ func ContainerLivenessProbeHTTP(h HTTPProbe) ContainerOpt {
 		}
 	}
 }
+
+// Apply applies the options to the container
+func (c *Container) Apply(opts ...ContainerOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// With returns a copy of the container with the options applied
+func (c Container) With(opts ...ContainerOpt) Container {
+	clone := c.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the container
+func (c Container) Clone() Container {
+	return Container{Container: *c.Container.DeepCopy()}
+}
+
+// Validate checks the container name, image, ports, image pull policy and probes
+func (c Container) Validate() error {
+	return toAggregate(validateContainer(c.Container, nil))
+}
@@ -0,0 +1,382 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// CRD holds a Kubernetes custom resource definition
+type CRD struct {
+	apiextensionsv1.CustomResourceDefinition
+}
+
+type CRDOpt func(*CRD)
+
+// CustomResourceDefinitionVersion holds a version of a custom resource definition
+type CustomResourceDefinitionVersion struct {
+	apiextensionsv1.CustomResourceDefinitionVersion
+}
+
+type CRDVersionOpt func(*CustomResourceDefinitionVersion)
+
+// NewCustomResourceDefinition returns a namespaced custom resource definition with the given name and options.
+// The name must be the plural resource name and the group such as widgets.example.com, and is used to set
+// the group and the plural name
+func NewCustomResourceDefinition(name string, opts ...CRDOpt) CRD {
+	plural, group, _ := strings.Cut(name, ".")
+	c := CRD{
+		CustomResourceDefinition: apiextensionsv1.CustomResourceDefinition{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "CustomResourceDefinition",
+				APIVersion: "apiextensions.k8s.io/v1",
+			},
+			ObjectMeta: newObjectMeta(name),
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: group,
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Plural: plural,
+				},
+				Scope: apiextensionsv1.NamespaceScoped,
+			},
+		},
+	}
+
+	for _, v := range opts {
+		v(&c)
+	}
+
+	return c
+}
+
+// CRDKind sets the kind of the custom resource. The list kind and singular name are derived from the kind
+func CRDKind(kind string) CRDOpt {
+	return func(c *CRD) {
+		c.Spec.Names.Kind = kind
+		c.Spec.Names.ListKind = kind + "List"
+		c.Spec.Names.Singular = strings.ToLower(kind)
+	}
+}
+
+// CRDSingular sets the singular resource name
+func CRDSingular(name string) CRDOpt {
+	return func(c *CRD) {
+		c.Spec.Names.Singular = name
+	}
+}
+
+// CRDShortNames sets the short names usable with kubectl
+func CRDShortNames(names ...string) CRDOpt {
+	return func(c *CRD) {
+		c.Spec.Names.ShortNames = names
+	}
+}
+
+// CRDCategories sets the categories the resource belongs to, such as all
+func CRDCategories(categories ...string) CRDOpt {
+	return func(c *CRD) {
+		c.Spec.Names.Categories = categories
+	}
+}
+
+// CRDScope sets whether the custom resources are namespaced or cluster scoped
+func CRDScope(s apiextensionsv1.ResourceScope) CRDOpt {
+	return func(c *CRD) {
+		c.Spec.Scope = s
+	}
+}
+
+// CRDVersion adds a version
+func CRDVersion(v CustomResourceDefinitionVersion) CRDOpt {
+	return func(c *CRD) {
+		c.Spec.Versions = append(c.Spec.Versions, v.CustomResourceDefinitionVersion)
+	}
+}
+
+// Apply applies the options to the custom resource definition
+func (c *CRD) Apply(opts ...CRDOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// With returns a copy of the custom resource definition with the options applied
+func (c CRD) With(opts ...CRDOpt) CRD {
+	clone := c.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the custom resource definition
+func (c CRD) Clone() CRD {
+	return CRD{CustomResourceDefinition: *c.CustomResourceDefinition.DeepCopy()}
+}
+
+// Validate checks that the name is the plural name and the group, the names, the scope and that exactly one
+// version is the storage version
+func (c CRD) Validate() error {
+	errs := validateObjectMeta(&c.ObjectMeta, false, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+	names := c.Spec.Names
+
+	if c.Spec.Group == "" {
+		errs = append(errs, field.Required(path.Child("group"), ""))
+	} else {
+		errs = append(errs, validateDNSSubdomain(c.Spec.Group, path.Child("group"))...)
+		if len(strings.Split(c.Spec.Group, ".")) < 2 {
+			errs = append(errs, field.Invalid(path.Child("group"), c.Spec.Group, "should be a domain with at least one dot"))
+		}
+	}
+
+	if c.Name != "" && c.Name != names.Plural+"."+c.Spec.Group {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), c.Name, "must be spec.names.plural+\".\"+spec.group"))
+	}
+
+	namesPath := path.Child("names")
+	errs = append(errs, validateDNSLabel(names.Plural, namesPath.Child("plural"))...)
+	if names.Singular != "" {
+		errs = append(errs, validateDNSLabel(names.Singular, namesPath.Child("singular"))...)
+	}
+	if names.Kind == "" {
+		errs = append(errs, field.Required(namesPath.Child("kind"), ""))
+	} else if msgs := validation.IsDNS1035Label(strings.ToLower(names.Kind)); len(msgs) > 0 {
+		errs = append(errs, field.Invalid(namesPath.Child("kind"), names.Kind, strings.Join(msgs, ", ")))
+	}
+	if names.ListKind == "" {
+		errs = append(errs, field.Required(namesPath.Child("listKind"), ""))
+	} else if names.ListKind == names.Kind {
+		errs = append(errs, field.Invalid(namesPath.Child("listKind"), names.ListKind, "must not be the same as kind"))
+	}
+	for i, n := range names.ShortNames {
+		errs = append(errs, validateDNSLabel(n, namesPath.Child("shortNames").Index(i))...)
+	}
+	for i, n := range names.Categories {
+		errs = append(errs, validateDNSLabel(n, namesPath.Child("categories").Index(i))...)
+	}
+
+	if c.Spec.Scope == "" {
+		errs = append(errs, field.Required(path.Child("scope"), ""))
+	}
+	errs = append(errs, validateEnum(c.Spec.Scope, path.Child("scope"), apiextensionsv1.NamespaceScoped, apiextensionsv1.ClusterScoped)...)
+
+	versionsPath := path.Child("versions")
+	if len(c.Spec.Versions) == 0 {
+		errs = append(errs, field.Required(versionsPath, "at least one version is required"))
+	}
+
+	storage := 0
+	versions := sets.New[string]()
+	for i, v := range c.Spec.Versions {
+		errs = append(errs, validateCRDVersion(v, versionsPath.Index(i))...)
+		if versions.Has(v.Name) {
+			errs = append(errs, field.Duplicate(versionsPath.Index(i).Child("name"), v.Name))
+		}
+		versions.Insert(v.Name)
+		if v.Storage {
+			storage++
+		}
+	}
+	if len(c.Spec.Versions) > 0 && storage != 1 {
+		errs = append(errs, field.Invalid(versionsPath, storage, "exactly one version must be the storage version"))
+	}
+
+	return toAggregate(errs)
+}
+
+// NewCustomResourceDefinitionVersion returns a served custom resource definition version with the given name and options
+func NewCustomResourceDefinitionVersion(name string, opts ...CRDVersionOpt) CustomResourceDefinitionVersion {
+	v := CustomResourceDefinitionVersion{
+		CustomResourceDefinitionVersion: apiextensionsv1.CustomResourceDefinitionVersion{
+			Name:   name,
+			Served: true,
+		},
+	}
+
+	for _, o := range opts {
+		o(&v)
+	}
+
+	return v
+}
+
+// CRDVersionServed sets whether the version is served by the API server
+func CRDVersionServed(b bool) CRDVersionOpt {
+	return func(v *CustomResourceDefinitionVersion) {
+		v.Served = b
+	}
+}
+
+// CRDVersionStorage makes the version the one custom resources are stored as. Exactly one version must be the storage version
+func CRDVersionStorage() CRDVersionOpt {
+	return func(v *CustomResourceDefinitionVersion) {
+		v.Storage = true
+	}
+}
+
+// CRDVersionDeprecated marks the version as deprecated with the warning returned to API clients
+func CRDVersionDeprecated(warning string) CRDVersionOpt {
+	return func(v *CustomResourceDefinitionVersion) {
+		v.Deprecated = true
+		if warning != "" {
+			v.DeprecationWarning = &warning
+		}
+	}
+}
+
+// CRDVersionSchema sets the OpenAPI v3 schema of the version
+func CRDVersionSchema(s apiextensionsv1.JSONSchemaProps) CRDVersionOpt {
+	return func(v *CustomResourceDefinitionVersion) {
+		v.Schema = &apiextensionsv1.CustomResourceValidation{
+			OpenAPIV3Schema: s.DeepCopy(),
+		}
+	}
+}
+
+// CRDVersionSchemaFromStruct sets the OpenAPI v3 schema of the version generated from the Go struct with OpenAPISchema
+func CRDVersionSchemaFromStruct(i interface{}) (CRDVersionOpt, error) {
+	s, err := OpenAPISchema(i)
+	if err != nil {
+		return nil, err
+	}
+
+	return CRDVersionSchema(s), nil
+}
+
+// CRDVersionStatus enables the status subresource
+func CRDVersionStatus() CRDVersionOpt {
+	return func(v *CustomResourceDefinitionVersion) {
+		v.subresources().Status = &apiextensionsv1.CustomResourceSubresourceStatus{}
+	}
+}
+
+// CRDVersionScale enables the scale subresource with the JSON paths of the replicas. The label selector path can
+// be empty, but is needed for the horizontal pod autoscaler
+func CRDVersionScale(specReplicasPath, statusReplicasPath, labelSelectorPath string) CRDVersionOpt {
+	return func(v *CustomResourceDefinitionVersion) {
+		scale := &apiextensionsv1.CustomResourceSubresourceScale{
+			SpecReplicasPath:   specReplicasPath,
+			StatusReplicasPath: statusReplicasPath,
+		}
+
+		if labelSelectorPath != "" {
+			scale.LabelSelectorPath = &labelSelectorPath
+		}
+
+		v.subresources().Scale = scale
+	}
+}
+
+func (v *CustomResourceDefinitionVersion) subresources() *apiextensionsv1.CustomResourceSubresources {
+	if v.Subresources == nil {
+		v.Subresources = &apiextensionsv1.CustomResourceSubresources{}
+	}
+	return v.Subresources
+}
+
+// CRDVersionPrinterColumn adds a kubectl get column showing the JSON path. The type is an OpenAPI type such as
+// string, integer or date
+func CRDVersionPrinterColumn(name, columnType, jsonPath string) CRDVersionOpt {
+	return func(v *CustomResourceDefinitionVersion) {
+		v.AdditionalPrinterColumns = append(v.AdditionalPrinterColumns, apiextensionsv1.CustomResourceColumnDefinition{
+			Name:     name,
+			Type:     columnType,
+			JSONPath: jsonPath,
+		})
+	}
+}
+
+// Apply applies the options to the version
+func (v *CustomResourceDefinitionVersion) Apply(opts ...CRDVersionOpt) {
+	for _, opt := range opts {
+		opt(v)
+	}
+}
+
+// With returns a copy of the version with the options applied
+func (v CustomResourceDefinitionVersion) With(opts ...CRDVersionOpt) CustomResourceDefinitionVersion {
+	clone := v.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the version
+func (v CustomResourceDefinitionVersion) Clone() CustomResourceDefinitionVersion {
+	return CustomResourceDefinitionVersion{CustomResourceDefinitionVersion: *v.CustomResourceDefinitionVersion.DeepCopy()}
+}
+
+// Validate checks the version name, that a served version has a schema, the subresource paths and the printer columns
+func (v CustomResourceDefinitionVersion) Validate() error {
+	return toAggregate(validateCRDVersion(v.CustomResourceDefinitionVersion, nil))
+}
+
+func validateCRDVersion(v apiextensionsv1.CustomResourceDefinitionVersion, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if v.Name == "" {
+		errs = append(errs, field.Required(path.Child("name"), ""))
+	} else {
+		for _, msg := range validation.IsDNS1035Label(v.Name) {
+			errs = append(errs, field.Invalid(path.Child("name"), v.Name, msg))
+		}
+	}
+
+	if v.Served && (v.Schema == nil || v.Schema.OpenAPIV3Schema == nil) {
+		errs = append(errs, field.Required(path.Child("schema", "openAPIV3Schema"), "served versions require a schema"))
+	}
+
+	if v.Subresources != nil && v.Subresources.Scale != nil {
+		scalePath := path.Child("subresources", "scale")
+		scale := v.Subresources.Scale
+		if !strings.HasPrefix(scale.SpecReplicasPath, ".spec.") {
+			errs = append(errs, field.Invalid(scalePath.Child("specReplicasPath"), scale.SpecReplicasPath, "should be a JSON path under .spec"))
+		}
+		if !strings.HasPrefix(scale.StatusReplicasPath, ".status.") {
+			errs = append(errs, field.Invalid(scalePath.Child("statusReplicasPath"), scale.StatusReplicasPath, "should be a JSON path under .status"))
+		}
+		if p := scale.LabelSelectorPath; p != nil && !strings.HasPrefix(*p, ".spec.") && !strings.HasPrefix(*p, ".status.") {
+			errs = append(errs, field.Invalid(scalePath.Child("labelSelectorPath"), *p, "should be a JSON path under .spec or .status"))
+		}
+	}
+
+	columns := sets.New[string]()
+	for i, col := range v.AdditionalPrinterColumns {
+		colPath := path.Child("additionalPrinterColumns").Index(i)
+		if col.Name == "" {
+			errs = append(errs, field.Required(colPath.Child("name"), ""))
+		} else if columns.Has(col.Name) {
+			errs = append(errs, field.Duplicate(colPath.Child("name"), col.Name))
+		}
+		columns.Insert(col.Name)
+
+		if col.Type == "" {
+			errs = append(errs, field.Required(colPath.Child("type"), ""))
+		}
+		errs = append(errs, validateEnum(col.Type, colPath.Child("type"), "integer", "number", "string", "boolean", "date")...)
+
+		if col.JSONPath == "" {
+			errs = append(errs, field.Required(colPath.Child("jsonPath"), ""))
+		}
+	}
+
+	return errs
+}
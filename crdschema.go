@@ -0,0 +1,384 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// quantityPattern matches the serialized form of a resource quantity
+const quantityPattern = `^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+
+var (
+	objectMetaType   = reflect.TypeOf(metav1.ObjectMeta{})
+	metaTimeType     = reflect.TypeOf(metav1.Time{})
+	metaMicroType    = reflect.TypeOf(metav1.MicroTime{})
+	metaDurationType = reflect.TypeOf(metav1.Duration{})
+	timeType         = reflect.TypeOf(time.Time{})
+	quantityType     = reflect.TypeOf(resource.Quantity{})
+	intOrStringType  = reflect.TypeOf(intstr.IntOrString{})
+	rawExtensionType = reflect.TypeOf(runtime.RawExtension{})
+	jsonType         = reflect.TypeOf(apiextensionsv1.JSON{})
+)
+
+// schemaMarkers holds the validation markers of the kopts struct tag and whether they take a value
+var schemaMarkers = map[string]bool{
+	"optional":              false,
+	"required":              false,
+	"nullable":              false,
+	"exclusiveMinimum":      false,
+	"exclusiveMaximum":      false,
+	"preserveUnknownFields": false,
+	"minimum":               true,
+	"maximum":               true,
+	"multipleOf":            true,
+	"minLength":             true,
+	"maxLength":             true,
+	"minItems":              true,
+	"maxItems":              true,
+	"minProperties":         true,
+	"maxProperties":         true,
+	"pattern":               true,
+	"format":                true,
+	"description":           true,
+	"enum":                  true,
+	"default":               true,
+	"listType":              true,
+	"listMapKeys":           true,
+}
+
+// OpenAPISchema generates the structural OpenAPI v3 schema of a custom resource from its Go type by reflection.
+// Field names are taken from the json tags, embedded structs and inline fields are flattened, and fields are
+// required unless they are tagged omitempty. ObjectMeta is left to the API server as a plain object, and
+// quantities and IntOrString fields accept integers and strings.
+//
+// Validation markers are set in the kopts tag as a comma separated list, for instance
+// `kopts:"minimum=1,maximum=10"` or `kopts:"enum=Always;Never,default=Always"`. Enum values and list map keys are
+// separated by semicolons. Defaults and enum values of non string fields are JSON values. The optional and required
+// flags override omitempty. Recursive types are not supported since structural schemas can't refer to themselves
+func OpenAPISchema(i interface{}) (apiextensionsv1.JSONSchemaProps, error) {
+	t := reflect.TypeOf(i)
+	if t == nil {
+		return apiextensionsv1.JSONSchemaProps{}, fmt.Errorf("cannot generate a schema for nil")
+	}
+
+	g := schemaGenerator{
+		visiting: make(map[reflect.Type]bool),
+	}
+
+	return g.schema(t)
+}
+
+type schemaGenerator struct {
+	visiting map[reflect.Type]bool
+}
+
+func (g schemaGenerator) schema(t reflect.Type) (apiextensionsv1.JSONSchemaProps, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case objectMetaType:
+		return apiextensionsv1.JSONSchemaProps{Type: "object"}, nil
+	case metaTimeType, metaMicroType, timeType:
+		return apiextensionsv1.JSONSchemaProps{Type: "string", Format: "date-time"}, nil
+	case metaDurationType:
+		return apiextensionsv1.JSONSchemaProps{Type: "string"}, nil
+	case quantityType:
+		return apiextensionsv1.JSONSchemaProps{
+			AnyOf:        []apiextensionsv1.JSONSchemaProps{{Type: "integer"}, {Type: "string"}},
+			Pattern:      quantityPattern,
+			XIntOrString: true,
+		}, nil
+	case intOrStringType:
+		return apiextensionsv1.JSONSchemaProps{
+			AnyOf:        []apiextensionsv1.JSONSchemaProps{{Type: "integer"}, {Type: "string"}},
+			XIntOrString: true,
+		}, nil
+	case rawExtensionType:
+		return apiextensionsv1.JSONSchemaProps{Type: "object", XPreserveUnknownFields: ptrTo(true)}, nil
+	case jsonType:
+		return apiextensionsv1.JSONSchemaProps{XPreserveUnknownFields: ptrTo(true)}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return apiextensionsv1.JSONSchemaProps{Type: "boolean"}, nil
+	case reflect.Int32:
+		return apiextensionsv1.JSONSchemaProps{Type: "integer", Format: "int32"}, nil
+	case reflect.Int64:
+		return apiextensionsv1.JSONSchemaProps{Type: "integer", Format: "int64"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return apiextensionsv1.JSONSchemaProps{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return apiextensionsv1.JSONSchemaProps{Type: "number"}, nil
+	case reflect.String:
+		return apiextensionsv1.JSONSchemaProps{Type: "string"}, nil
+	case reflect.Interface:
+		return apiextensionsv1.JSONSchemaProps{XPreserveUnknownFields: ptrTo(true)}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return apiextensionsv1.JSONSchemaProps{Type: "string", Format: "byte"}, nil
+		}
+
+		items, err := g.schema(t.Elem())
+		if err != nil {
+			return apiextensionsv1.JSONSchemaProps{}, err
+		}
+
+		return apiextensionsv1.JSONSchemaProps{
+			Type: "array",
+			Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+				Schema: &items,
+			},
+		}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return apiextensionsv1.JSONSchemaProps{}, fmt.Errorf("map key of %s must be a string", t)
+		}
+
+		values, err := g.schema(t.Elem())
+		if err != nil {
+			return apiextensionsv1.JSONSchemaProps{}, err
+		}
+
+		return apiextensionsv1.JSONSchemaProps{
+			Type: "object",
+			AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+				Allows: true,
+				Schema: &values,
+			},
+		}, nil
+	case reflect.Struct:
+		return g.structSchema(t)
+	}
+
+	return apiextensionsv1.JSONSchemaProps{}, fmt.Errorf("unsupported type %s", t)
+}
+
+func (g schemaGenerator) structSchema(t reflect.Type) (apiextensionsv1.JSONSchemaProps, error) {
+	if g.visiting[t] {
+		return apiextensionsv1.JSONSchemaProps{}, fmt.Errorf("recursive type %s is not supported", t)
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	s := apiextensionsv1.JSONSchemaProps{
+		Type:       "object",
+		Properties: make(map[string]apiextensionsv1.JSONSchemaProps),
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if (f.Anonymous && name == "" || hasTagOption(opts, "inline")) && ft.Kind() == reflect.Struct {
+			inline, err := g.schema(ft)
+			if err != nil {
+				return apiextensionsv1.JSONSchemaProps{}, err
+			}
+			for k, v := range inline.Properties {
+				s.Properties[k] = v
+			}
+			s.Required = append(s.Required, inline.Required...)
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		fs, err := g.schema(f.Type)
+		if err != nil {
+			return apiextensionsv1.JSONSchemaProps{}, fmt.Errorf("%s.%s: %w", t.Name(), f.Name, err)
+		}
+
+		optional, err := applySchemaMarkers(&fs, f.Tag.Get("kopts"), hasTagOption(opts, "omitempty"))
+		if err != nil {
+			return apiextensionsv1.JSONSchemaProps{}, fmt.Errorf("%s.%s: %w", t.Name(), f.Name, err)
+		}
+
+		s.Properties[name] = fs
+		if !optional {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	return s, nil
+}
+
+func hasTagOption(opts, option string) bool {
+	for _, v := range strings.Split(opts, ",") {
+		if v == option {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSchemaMarkers splits the kopts tag into markers. Commas in values such as patterns or JSON defaults
+// are kept since a comma only starts a new marker when it is followed by a marker name
+func parseSchemaMarkers(tag string) ([][2]string, error) {
+	var markers [][2]string
+	for _, v := range strings.Split(tag, ",") {
+		key, value, hasValue := strings.Cut(v, "=")
+		takesValue, known := schemaMarkers[key]
+		if known && takesValue == hasValue {
+			markers = append(markers, [2]string{key, value})
+			continue
+		}
+
+		if len(markers) == 0 || !schemaMarkers[markers[len(markers)-1][0]] {
+			return nil, fmt.Errorf("invalid marker %q", v)
+		}
+		markers[len(markers)-1][1] += "," + v
+	}
+
+	return markers, nil
+}
+
+func applySchemaMarkers(s *apiextensionsv1.JSONSchemaProps, tag string, optional bool) (bool, error) {
+	if tag == "" {
+		return optional, nil
+	}
+
+	markers, err := parseSchemaMarkers(tag)
+	if err != nil {
+		return optional, err
+	}
+
+	for _, m := range markers {
+		key, value := m[0], m[1]
+		var err error
+
+		switch key {
+		case "optional":
+			optional = true
+		case "required":
+			optional = false
+		case "nullable":
+			s.Nullable = true
+		case "exclusiveMinimum":
+			s.ExclusiveMinimum = true
+		case "exclusiveMaximum":
+			s.ExclusiveMaximum = true
+		case "preserveUnknownFields":
+			s.XPreserveUnknownFields = ptrTo(true)
+		case "minimum":
+			s.Minimum, err = parseSchemaFloat(value)
+		case "maximum":
+			s.Maximum, err = parseSchemaFloat(value)
+		case "multipleOf":
+			s.MultipleOf, err = parseSchemaFloat(value)
+		case "minLength":
+			s.MinLength, err = parseSchemaInt(value)
+		case "maxLength":
+			s.MaxLength, err = parseSchemaInt(value)
+		case "minItems":
+			s.MinItems, err = parseSchemaInt(value)
+		case "maxItems":
+			s.MaxItems, err = parseSchemaInt(value)
+		case "minProperties":
+			s.MinProperties, err = parseSchemaInt(value)
+		case "maxProperties":
+			s.MaxProperties, err = parseSchemaInt(value)
+		case "pattern":
+			s.Pattern = value
+		case "format":
+			s.Format = value
+		case "description":
+			s.Description = value
+		case "listType":
+			s.XListType = &value
+		case "listMapKeys":
+			s.XListMapKeys = strings.Split(value, ";")
+		case "enum":
+			for _, v := range strings.Split(value, ";") {
+				e, err := schemaValue(*s, v)
+				if err != nil {
+					return optional, fmt.Errorf("enum: %w", err)
+				}
+				s.Enum = append(s.Enum, e)
+			}
+		case "default":
+			var d apiextensionsv1.JSON
+			d, err = schemaValue(*s, value)
+			s.Default = &d
+		}
+
+		if err != nil {
+			return optional, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	return optional, nil
+}
+
+func parseSchemaFloat(v string) (*float64, error) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func parseSchemaInt(v string) (*int64, error) {
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// schemaValue converts a marker value to JSON. Values of string fields are quoted and values of int or string
+// fields are quoted unless they are numbers
+func schemaValue(s apiextensionsv1.JSONSchemaProps, v string) (apiextensionsv1.JSON, error) {
+	if s.Type == "string" || s.XIntOrString {
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil || s.Type == "string" {
+			raw, err := json.Marshal(v)
+			return apiextensionsv1.JSON{Raw: raw}, err
+		}
+	}
+
+	if !json.Valid([]byte(v)) {
+		return apiextensionsv1.JSON{}, fmt.Errorf("%q is not a valid JSON value", v)
+	}
+
+	return apiextensionsv1.JSON{Raw: []byte(v)}, nil
+}
@@ -0,0 +1,250 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type schemaNode struct {
+	Name     string       `json:"name"`
+	Children []schemaNode `json:"children,omitempty"`
+}
+
+type schemaLinked struct {
+	Next *schemaLinked `json:"next,omitempty"`
+}
+
+type schemaBase struct {
+	ID string `json:"id"`
+}
+
+type schemaWidget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec schemaWidgetSpec `json:"spec"`
+}
+
+type schemaWidgetSpec struct {
+	schemaBase `json:",inline"`
+
+	Size     resource.Quantity `json:"size"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Tags     []string          `json:"tags,omitempty" kopts:"required"`
+	Replicas *int32            `json:"replicas" kopts:"optional"`
+	Data     []byte            `json:"data,omitempty"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+func TestOpenAPISchemaMarkers(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  interface{}
+		schema apiextensionsv1.JSONSchemaProps
+		err    string
+	}{
+		{
+			name: "numeric bounds",
+			value: struct {
+				F int `json:"f" kopts:"minimum=1,maximum=10,exclusiveMaximum,multipleOf=2"`
+			}{},
+			schema: apiextensionsv1.JSONSchemaProps{
+				Type:             "integer",
+				Minimum:          ptrTo(1.0),
+				Maximum:          ptrTo(10.0),
+				ExclusiveMaximum: true,
+				MultipleOf:       ptrTo(2.0),
+			},
+		},
+		{
+			name: "string markers",
+			value: struct {
+				F string `json:"f" kopts:"minLength=1,maxLength=63,pattern=^[a-z]{1,3}$,description=the name"`
+			}{},
+			schema: apiextensionsv1.JSONSchemaProps{
+				Type:        "string",
+				MinLength:   ptrTo[int64](1),
+				MaxLength:   ptrTo[int64](63),
+				Pattern:     "^[a-z]{1,3}$",
+				Description: "the name",
+			},
+		},
+		{
+			name: "string enum and default",
+			value: struct {
+				F string `json:"f" kopts:"enum=Always;Never,default=Always"`
+			}{},
+			schema: apiextensionsv1.JSONSchemaProps{
+				Type:    "string",
+				Enum:    []apiextensionsv1.JSON{{Raw: []byte(`"Always"`)}, {Raw: []byte(`"Never"`)}},
+				Default: &apiextensionsv1.JSON{Raw: []byte(`"Always"`)},
+			},
+		},
+		{
+			name: "JSON default",
+			value: struct {
+				F map[string]int `json:"f" kopts:"default={\"a\":1,\"b\":2}"`
+			}{},
+			schema: apiextensionsv1.JSONSchemaProps{
+				Type: "object",
+				AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+					Allows: true,
+					Schema: &apiextensionsv1.JSONSchemaProps{Type: "integer"},
+				},
+				Default: &apiextensionsv1.JSON{Raw: []byte(`{"a":1,"b":2}`)},
+			},
+		},
+		{
+			name: "list map",
+			value: struct {
+				F []struct {
+					Name string `json:"name"`
+				} `json:"f" kopts:"listType=map,listMapKeys=name,minItems=1"`
+			}{},
+			schema: apiextensionsv1.JSONSchemaProps{
+				Type: "array",
+				Items: &apiextensionsv1.JSONSchemaPropsOrArray{
+					Schema: &apiextensionsv1.JSONSchemaProps{
+						Type:       "object",
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{"name": {Type: "string"}},
+						Required:   []string{"name"},
+					},
+				},
+				XListType:    ptrTo("map"),
+				XListMapKeys: []string{"name"},
+				MinItems:     ptrTo[int64](1),
+			},
+		},
+		{
+			name: "unknown marker",
+			value: struct {
+				F string `json:"f" kopts:"minimun=1"`
+			}{},
+			err: `invalid marker "minimun=1"`,
+		},
+		{
+			name: "invalid number",
+			value: struct {
+				F int `json:"f" kopts:"minimum=one"`
+			}{},
+			err: "minimum",
+		},
+		{
+			name: "invalid JSON default",
+			value: struct {
+				F int `json:"f" kopts:"default=one"`
+			}{},
+			err: "is not a valid JSON value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := OpenAPISchema(tt.value)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("OpenAPISchema() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OpenAPISchema() error = %v", err)
+			}
+			if got := s.Properties["f"]; !reflect.DeepEqual(got, tt.schema) {
+				t.Errorf("OpenAPISchema() f = %+v, want %+v", got, tt.schema)
+			}
+		})
+	}
+}
+
+func TestOpenAPISchemaStruct(t *testing.T) {
+	s, err := OpenAPISchema(schemaWidget{})
+	if err != nil {
+		t.Fatalf("OpenAPISchema() error = %v", err)
+	}
+
+	if _, ok := s.Properties["metadata"]; !ok {
+		t.Errorf("metadata is missing from %v", s.Properties)
+	}
+	if _, ok := s.Properties["kind"]; !ok {
+		t.Errorf("inline type meta is missing from %v", s.Properties)
+	}
+
+	spec := s.Properties["spec"]
+	var properties []string
+	for k := range spec.Properties {
+		properties = append(properties, k)
+	}
+	want := []string{"data", "id", "labels", "replicas", "size", "tags"}
+	if !reflect.DeepEqual(sortedStrings(properties), want) {
+		t.Errorf("spec properties = %v, want %v", sortedStrings(properties), want)
+	}
+	if want := []string{"id", "size", "tags"}; !reflect.DeepEqual(sortedStrings(spec.Required), want) {
+		t.Errorf("spec required = %v, want %v", sortedStrings(spec.Required), want)
+	}
+	if size := spec.Properties["size"]; !size.XIntOrString || size.Pattern != quantityPattern {
+		t.Errorf("size = %+v, want an int or string quantity", size)
+	}
+	if data := spec.Properties["data"]; data.Type != "string" || data.Format != "byte" {
+		t.Errorf("data = %+v, want a byte string", data)
+	}
+	if replicas := spec.Properties["replicas"]; replicas.Format != "int32" {
+		t.Errorf("replicas = %+v, want an int32", replicas)
+	}
+}
+
+func TestOpenAPISchemaRecursion(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		err   bool
+	}{
+		{name: "recursive slice", value: schemaNode{}, err: true},
+		{name: "recursive pointer", value: schemaLinked{}, err: true},
+		{
+			name: "repeated sibling type",
+			value: struct {
+				A schemaBase `json:"a"`
+				B schemaBase `json:"b"`
+			}{},
+		},
+		{name: "nil", value: nil, err: true},
+		{name: "map with int keys", value: map[int]string{}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := OpenAPISchema(tt.value)
+			if (err != nil) != tt.err {
+				t.Errorf("OpenAPISchema() error = %v, want error %t", err, tt.err)
+			}
+		})
+	}
+}
+
+func sortedStrings(s []string) []string {
+	s = append([]string(nil), s...)
+	sort.Strings(s)
+	return s
+}
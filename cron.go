@@ -16,10 +16,15 @@ package kopts
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // CronSchedule is a cronjob schedule string
@@ -34,8 +39,8 @@ const (
 	Yearly         CronSchedule = "0 0 1 1 *"
 	Every15Minutes CronSchedule = "*/15 * * * *"
 	Every5Minutes  CronSchedule = "*/5 * * * *"
-	Every10Minutes CronSchedule = "*/10 * * *"
-	EveryHalfHour  CronSchedule = "*/30 * * *"
+	Every10Minutes CronSchedule = "*/10 * * * *"
+	EveryHalfHour  CronSchedule = "*/30 * * * *"
 )
 
 // CronJob is a Kubernetes cron job
@@ -76,50 +81,43 @@ func CronJobNamespace(n string) CronJobOpt {
 	}
 }
 
-// CronJobRestartPolicy sets the restart policy for the cronjob
-func CronJobRestartPolicy(r corev1.RestartPolicy) CronJobOpt {
+// CronJobJobSpec applies the job spec options to the job template of the cronjob
+func CronJobJobSpec(opts ...JobSpecOpt) CronJobOpt {
 	return func(c *CronJob) {
-		c.Spec.JobTemplate.Spec.Template.Spec.RestartPolicy = r
+		for _, v := range opts {
+			v(&c.Spec.JobTemplate.Spec)
+		}
 	}
 }
 
+// CronJobRestartPolicy sets the restart policy for the cronjob
+func CronJobRestartPolicy(r corev1.RestartPolicy) CronJobOpt {
+	return CronJobJobSpec(JobSpecRestartPolicy(r))
+}
+
 // CronJobParallelism sets the parallelism for the cronjob
 func CronJobParallelism(i int) CronJobOpt {
-	parallel := int32(i)
-	return func(c *CronJob) {
-		c.Spec.JobTemplate.Spec.Parallelism = &parallel
-	}
+	return CronJobJobSpec(JobSpecParallelism(i))
 }
 
 // CronJobCompletions sets the completions for the cronjob
 func CronJobCompletions(i int) CronJobOpt {
-	completions := int32(i)
-	return func(c *CronJob) {
-		c.Spec.JobTemplate.Spec.Completions = &completions
-	}
+	return CronJobJobSpec(JobSpecCompletions(i))
 }
 
 // CronJobActiveDeadlineSeconds sets the active deadline seconds for the cronjob
 func CronJobActiveDeadlineSeconds(i int) CronJobOpt {
-	seconds := int64(i)
-	return func(c *CronJob) {
-		c.Spec.JobTemplate.Spec.ActiveDeadlineSeconds = &seconds
-	}
+	return CronJobJobSpec(JobSpecActiveDeadlineSeconds(i))
 }
 
 // CronJobBackoffLimit sets the backoff limit for the cronjob
 func CronJobBackoffLimit(i int) CronJobOpt {
-	limit := int32(i)
-	return func(c *CronJob) {
-		c.Spec.JobTemplate.Spec.BackoffLimit = &limit
-	}
+	return CronJobJobSpec(JobSpecBackoffLimit(i))
 }
 
 // CronJobPodSpec sets the pod spec for the cronjob
 func CronJobPodSpec(p PodSpec) CronJobOpt {
-	return func(c *CronJob) {
-		c.Spec.JobTemplate.Spec.Template = p.Spec
-	}
+	return CronJobJobSpec(JobSpecPodSpec(p))
 }
 
 // CronJobSchedule sets the schedule for the cronjob
@@ -135,3 +133,74 @@ func CronJobConcurrency(p batchv1.ConcurrencyPolicy) CronJobOpt {
 		c.Spec.ConcurrencyPolicy = p
 	}
 }
+
+var (
+	cronField       = regexp.MustCompile(`^[0-9A-Za-z*?,/\-]+$`)
+	cronDescriptors = []string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+)
+
+// Apply applies the options to the cron job
+func (c *CronJob) Apply(opts ...CronJobOpt) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// With returns a copy of the cron job with the options applied
+func (c *CronJob) With(opts ...CronJobOpt) *CronJob {
+	clone := c.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the cron job
+func (c *CronJob) Clone() *CronJob {
+	return &CronJob{CronJob: *c.CronJob.DeepCopy()}
+}
+
+// Validate checks the metadata, the schedule syntax, the concurrency policy and the job template
+func (c CronJob) Validate() error {
+	errs := validateObjectMeta(&c.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateSchedule(c.Spec.Schedule, path.Child("schedule"))...)
+	errs = append(errs, validateEnum(c.Spec.ConcurrencyPolicy, path.Child("concurrencyPolicy"),
+		batchv1.AllowConcurrent, batchv1.ForbidConcurrent, batchv1.ReplaceConcurrent)...)
+	errs = append(errs, validateNonNegative(c.Spec.SuccessfulJobsHistoryLimit, path.Child("successfulJobsHistoryLimit"))...)
+	errs = append(errs, validateNonNegative(c.Spec.FailedJobsHistoryLimit, path.Child("failedJobsHistoryLimit"))...)
+
+	return toAggregate(append(errs, validateJobSpec(c.Spec.JobTemplate.Spec, path.Child("jobTemplate", "spec"))...))
+}
+
+// validateSchedule checks that the schedule has five cron fields or is a descriptor such as @daily. The time zone
+// must be set with the timeZone field rather than a TZ prefix
+func validateSchedule(s string, path *field.Path) field.ErrorList {
+	if s == "" {
+		return field.ErrorList{field.Required(path, "")}
+	}
+
+	if strings.HasPrefix(s, "TZ=") || strings.HasPrefix(s, "CRON_TZ=") {
+		return field.ErrorList{field.Invalid(path, s, "use the timeZone field to set the time zone")}
+	}
+
+	if strings.HasPrefix(s, "@") {
+		if slices.Contains(cronDescriptors, s) || strings.HasPrefix(s, "@every ") {
+			return nil
+		}
+		return field.ErrorList{field.Invalid(path, s, "unknown descriptor")}
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return field.ErrorList{field.Invalid(path, s, fmt.Sprintf("expected 5 fields, found %d", len(fields)))}
+	}
+
+	for _, f := range fields {
+		if !cronField.MatchString(f) {
+			return field.ErrorList{field.Invalid(path, s, fmt.Sprintf("invalid field %q", f))}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"maps"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// DaemonSet holds a Kubernetes daemon set
+type DaemonSet struct {
+	appsv1.DaemonSet
+}
+
+type DaemonSetOpt func(*DaemonSet)
+
+// NewDaemonSet returns a daemon set with the given name and options
+func NewDaemonSet(name string, opts ...DaemonSetOpt) *DaemonSet {
+	ds := &DaemonSet{
+		appsv1.DaemonSet{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "DaemonSet",
+				APIVersion: "apps/v1",
+			},
+			ObjectMeta: newObjectMeta(name),
+			Spec: appsv1.DaemonSetSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: make(map[string]string),
+				},
+				Template: corev1.PodTemplateSpec{},
+			},
+		},
+	}
+
+	for _, v := range opts {
+		v(ds)
+	}
+
+	return ds
+}
+
+// DaemonSetNamespace sets the namespace for the daemon set
+func DaemonSetNamespace(n string) DaemonSetOpt {
+	return func(ds *DaemonSet) {
+		setNamespace(n, &ds.ObjectMeta)
+	}
+}
+
+// DaemonSetSelector adds a daemon set selector
+func DaemonSetSelector(key, value string) DaemonSetOpt {
+	return func(ds *DaemonSet) {
+		metav1.AddLabelToSelector(ds.Spec.Selector, key, value)
+	}
+}
+
+// DaemonSetLabel adds a single daemon set label
+func DaemonSetLabel(key, value string) DaemonSetOpt {
+	return func(ds *DaemonSet) {
+		addLabel(key, value, &ds.ObjectMeta)
+	}
+}
+
+// DaemonSetLabels adds multiple daemon set labels
+func DaemonSetLabels(labels map[string]string) DaemonSetOpt {
+	return func(ds *DaemonSet) {
+		for k, v := range labels {
+			addLabel(k, v, &ds.ObjectMeta)
+		}
+	}
+}
+
+// DaemonSetPodSpec sets the pod spec for the daemon set
+func DaemonSetPodSpec(p PodSpec) DaemonSetOpt {
+	return func(ds *DaemonSet) {
+		ds.Spec.Template = p.template(ds.Spec.Template)
+	}
+}
+
+// DaemonSetNodeSelector sets the node selector for the daemon set pods
+func DaemonSetNodeSelector(selectors map[string]string) DaemonSetOpt {
+	return func(ds *DaemonSet) {
+		ds.Spec.Template.Spec.NodeSelector = maps.Clone(selectors)
+	}
+}
+
+// DaemonSetTolerations sets the tolerations for the daemon set pods
+func DaemonSetTolerations(tolerations []Toleration) DaemonSetOpt {
+	return func(ds *DaemonSet) {
+		ds.Spec.Template.Spec.Tolerations = coreTolerations(tolerations)
+	}
+}
+
+// DaemonSetMaxUnavailable sets a rolling update strategy with the pods that can be unavailable during the update
+// as an integer or a percentage. The max surge is left unchanged
+func DaemonSetMaxUnavailable(i intstr.IntOrString) DaemonSetOpt {
+	return func(ds *DaemonSet) {
+		ds.rollingUpdate().MaxUnavailable = &i
+	}
+}
+
+// DaemonSetMaxSurge sets a rolling update strategy with the pods that can be started on a node before the old pod
+// is stopped as an integer or a percentage. The max unavailable is left unchanged
+func DaemonSetMaxSurge(i intstr.IntOrString) DaemonSetOpt {
+	return func(ds *DaemonSet) {
+		ds.rollingUpdate().MaxSurge = &i
+	}
+}
+
+func (d *DaemonSet) rollingUpdate() *appsv1.RollingUpdateDaemonSet {
+	d.Spec.UpdateStrategy.Type = appsv1.RollingUpdateDaemonSetStrategyType
+	if d.Spec.UpdateStrategy.RollingUpdate == nil {
+		d.Spec.UpdateStrategy.RollingUpdate = &appsv1.RollingUpdateDaemonSet{}
+	}
+
+	return d.Spec.UpdateStrategy.RollingUpdate
+}
+
+// DaemonSetOnDelete sets the on delete update strategy for the daemon set
+func DaemonSetOnDelete() DaemonSetOpt {
+	return func(ds *DaemonSet) {
+		ds.Spec.UpdateStrategy = appsv1.DaemonSetUpdateStrategy{
+			Type: appsv1.OnDeleteDaemonSetStrategyType,
+		}
+	}
+}
+
+// DaemonSetMinReadySeconds sets the seconds a new pod must be ready before it is considered available
+func DaemonSetMinReadySeconds(i int) DaemonSetOpt {
+	seconds := int32(i)
+	return func(ds *DaemonSet) {
+		ds.Spec.MinReadySeconds = seconds
+	}
+}
+
+// DaemonSetHostNetwork runs the daemon set pods in the host network namespace.
+// The DNS policy is set so the pods can still resolve cluster services
+func DaemonSetHostNetwork() DaemonSetOpt {
+	return func(ds *DaemonSet) {
+		ds.Spec.Template.Spec.HostNetwork = true
+		ds.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+	}
+}
+
+// DaemonSetHostPID runs the daemon set pods in the host PID namespace
+func DaemonSetHostPID() DaemonSetOpt {
+	return func(ds *DaemonSet) {
+		ds.Spec.Template.Spec.HostPID = true
+	}
+}
+
+// DaemonSetNodeAgent is a preset for node agents. It enables host networking and host PID
+// and tolerates every taint so the pods are scheduled on all nodes.
+// Like the other pod level options it must be set after DaemonSetPodSpec
+func DaemonSetNodeAgent() DaemonSetOpt {
+	hostNetwork := DaemonSetHostNetwork()
+	hostPID := DaemonSetHostPID()
+	return func(ds *DaemonSet) {
+		hostNetwork(ds)
+		hostPID(ds)
+		ds.Spec.Template.Spec.Tolerations = append(ds.Spec.Template.Spec.Tolerations, corev1.Toleration{
+			Operator: corev1.TolerationOpExists,
+		})
+	}
+}
+
+// Apply applies the options to the daemon set
+func (d *DaemonSet) Apply(opts ...DaemonSetOpt) {
+	for _, opt := range opts {
+		opt(d)
+	}
+}
+
+// With returns a copy of the daemon set with the options applied
+func (d *DaemonSet) With(opts ...DaemonSetOpt) *DaemonSet {
+	clone := d.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the daemon set
+func (d *DaemonSet) Clone() *DaemonSet {
+	return &DaemonSet{DaemonSet: *d.DaemonSet.DeepCopy()}
+}
+
+// Validate checks the metadata, that the selector matches the pod template, that the pod template is valid and the
+// rolling update values
+func (d DaemonSet) Validate() error {
+	errs := validateObjectMeta(&d.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateSelector(d.Spec.Selector, d.Spec.Template.Labels, path.Child("selector"))...)
+	errs = append(errs, validatePodTemplate(d.Spec.Template, path.Child("template"), corev1.RestartPolicyAlways)...)
+	errs = append(errs, validateEnum(d.Spec.UpdateStrategy.Type, path.Child("updateStrategy", "type"),
+		appsv1.RollingUpdateDaemonSetStrategyType, appsv1.OnDeleteDaemonSetStrategyType)...)
+	if r := d.Spec.UpdateStrategy.RollingUpdate; r != nil {
+		rollingPath := path.Child("updateStrategy", "rollingUpdate")
+		errs = append(errs, validateIntOrPercent(r.MaxUnavailable, rollingPath.Child("maxUnavailable"))...)
+		errs = append(errs, validateIntOrPercent(r.MaxSurge, rollingPath.Child("maxSurge"))...)
+	}
+
+	return toAggregate(errs)
+}
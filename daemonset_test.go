@@ -0,0 +1,150 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestDaemonSetRollingUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []DaemonSetOpt
+		strategy appsv1.DaemonSetUpdateStrategy
+	}{
+		{
+			name: "max unavailable",
+			opts: []DaemonSetOpt{DaemonSetMaxUnavailable(intstr.FromInt(1))},
+			strategy: appsv1.DaemonSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: ptrTo(intstr.FromInt(1))},
+			},
+		},
+		{
+			name: "max surge",
+			opts: []DaemonSetOpt{DaemonSetMaxSurge(intstr.FromString("10%"))},
+			strategy: appsv1.DaemonSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxSurge: ptrTo(intstr.FromString("10%"))},
+			},
+		},
+		{
+			name: "both",
+			opts: []DaemonSetOpt{DaemonSetMaxSurge(intstr.FromInt(1)), DaemonSetMaxUnavailable(intstr.FromInt(0))},
+			strategy: appsv1.DaemonSetUpdateStrategy{
+				Type: appsv1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDaemonSet{
+					MaxUnavailable: ptrTo(intstr.FromInt(0)),
+					MaxSurge:       ptrTo(intstr.FromInt(1)),
+				},
+			},
+		},
+		{
+			name:     "on delete",
+			opts:     []DaemonSetOpt{DaemonSetMaxSurge(intstr.FromInt(1)), DaemonSetOnDelete()},
+			strategy: appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := NewDaemonSet("agent", tt.opts...)
+			if !reflect.DeepEqual(ds.Spec.UpdateStrategy, tt.strategy) {
+				t.Errorf("updateStrategy = %+v, want %+v", ds.Spec.UpdateStrategy, tt.strategy)
+			}
+		})
+	}
+}
+
+func TestDaemonSetPodOptions(t *testing.T) {
+	pod := NewPodSpec("agent", PodLabel("app", "agent"), PodContainer(NewContainer("agent", ContainerImage("fluent-bit"))))
+	nodes := map[string]string{"kubernetes.io/os": "linux"}
+	tolerations := []Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}
+
+	d := NewDeployment("agent", DeploymentSelector("app", "agent"), DeploymentPodSpec(pod),
+		DeploymentNodeSelector(nodes), DeploymentTolerations(tolerations))
+	ds := NewDaemonSet("agent", DaemonSetSelector("app", "agent"), DaemonSetPodSpec(pod),
+		DaemonSetNodeSelector(nodes), DaemonSetTolerations(tolerations))
+
+	if !reflect.DeepEqual(ds.Spec.Selector, d.Spec.Selector) {
+		t.Errorf("selector = %v, want the deployment selector %v", ds.Spec.Selector, d.Spec.Selector)
+	}
+	if !reflect.DeepEqual(ds.Spec.Template, d.Spec.Template) {
+		t.Errorf("template = %+v, want the deployment template %+v", ds.Spec.Template, d.Spec.Template)
+	}
+
+	nodes["kubernetes.io/os"] = "windows"
+	if got := ds.Spec.Template.Spec.NodeSelector["kubernetes.io/os"]; got != "linux" {
+		t.Errorf("node selector = %q after changing the caller's map, want linux", got)
+	}
+}
+
+func TestDaemonSetNodeAgent(t *testing.T) {
+	pod := NewPodSpec("agent", PodLabel("app", "agent"), PodContainer(NewContainer("agent", ContainerImage("node-exporter"))))
+	ds := NewDaemonSet("agent", DaemonSetSelector("app", "agent"), DaemonSetPodSpec(pod), DaemonSetNodeAgent())
+
+	spec := ds.Spec.Template.Spec
+	if !spec.HostNetwork || !spec.HostPID {
+		t.Errorf("hostNetwork = %t and hostPID = %t, want both", spec.HostNetwork, spec.HostPID)
+	}
+	if spec.DNSPolicy != corev1.DNSClusterFirstWithHostNet {
+		t.Errorf("dnsPolicy = %q, want %q", spec.DNSPolicy, corev1.DNSClusterFirstWithHostNet)
+	}
+	if want := []corev1.Toleration{{Operator: corev1.TolerationOpExists}}; !reflect.DeepEqual(spec.Tolerations, want) {
+		t.Errorf("tolerations = %v, want %v", spec.Tolerations, want)
+	}
+	if err := ds.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestDaemonSetValidate(t *testing.T) {
+	pod := NewPodSpec("agent", PodLabel("app", "agent"), PodContainer(NewContainer("agent", ContainerImage("fluent-bit"))))
+
+	tests := []struct {
+		name   string
+		opts   []DaemonSetOpt
+		fields []string
+	}{
+		{
+			name: "valid",
+			opts: []DaemonSetOpt{DaemonSetSelector("app", "agent"), DaemonSetPodSpec(pod), DaemonSetMaxUnavailable(intstr.FromString("25%"))},
+		},
+		{
+			name:   "selector does not match the template",
+			opts:   []DaemonSetOpt{DaemonSetSelector("app", "other"), DaemonSetPodSpec(pod)},
+			fields: []string{"spec.selector"},
+		},
+		{
+			name:   "invalid max surge",
+			opts:   []DaemonSetOpt{DaemonSetSelector("app", "agent"), DaemonSetPodSpec(pod), DaemonSetMaxSurge(intstr.FromString("all"))},
+			fields: []string{"spec.updateStrategy.rollingUpdate.maxSurge"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, NewDaemonSet("agent", tt.opts...).Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -16,10 +16,13 @@ package kopts
 
 import (
 	"fmt"
+	"maps"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 var ErrNameRequired = fmt.Errorf("name is required")
@@ -90,38 +93,70 @@ func DeploymentLabels(labels map[string]string) DeploymentOpt {
 // Set deployment pod spec
 func DeploymentPodSpec(p PodSpec) DeploymentOpt {
 	return func(d *Deployment) {
-		d.Spec.Template = p.Spec
+		d.Spec.Template = p.template(d.Spec.Template)
 	}
 }
 
 // Set deployment replicas
 func DeploymentReplicas(r int) DeploymentOpt {
-	replicas := int32(r)
 	return func(d *Deployment) {
-		d.Spec.Replicas = &replicas
+		d.Spec.Replicas = ptrTo(int32(r))
 	}
 }
 
 func DeploymentNodeSelector(selectors map[string]string) DeploymentOpt {
 	return func(d *Deployment) {
-		d.Spec.Template.Spec.NodeSelector = selectors
+		d.Spec.Template.Spec.NodeSelector = maps.Clone(selectors)
 	}
 }
 
 // Set tolerations
 func DeploymentTolerations(tolerations []Toleration) DeploymentOpt {
-	var coreTolerations []corev1.Toleration
-	for _, v := range tolerations {
-		seconds := int64(v.TolerationSeconds)
-		coreTolerations = append(coreTolerations, corev1.Toleration{
-			Key:               v.Key,
-			Value:             v.Value,
-			TolerationSeconds: &seconds,
-			Operator:          v.Operator,
-			Effect:            v.Effect,
-		})
-	}
 	return func(d *Deployment) {
-		d.Spec.Template.Spec.Tolerations = coreTolerations
+		d.Spec.Template.Spec.Tolerations = coreTolerations(tolerations)
 	}
 }
+
+// podSelector returns a copy of the deployment selector. Deployments without a selector return an error, as an
+// empty selector would select every pod in the namespace
+func (d *Deployment) podSelector() (*metav1.LabelSelector, error) {
+	if emptySelector(d.Spec.Selector) {
+		return nil, fmt.Errorf("deployment %s has no selector", d.Name)
+	}
+
+	return d.Spec.Selector.DeepCopy(), nil
+}
+
+// Apply applies the options to the deployment
+func (d *Deployment) Apply(opts ...DeploymentOpt) {
+	for _, opt := range opts {
+		opt(d)
+	}
+}
+
+// With returns a copy of the deployment with the options applied
+func (d *Deployment) With(opts ...DeploymentOpt) *Deployment {
+	clone := d.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the deployment
+func (d *Deployment) Clone() *Deployment {
+	return &Deployment{Deployment: *d.Deployment.DeepCopy()}
+}
+
+// Validate checks the metadata, that the selector matches the pod template and that the pod template is valid
+func (d Deployment) Validate() error {
+	errs := validateObjectMeta(&d.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateNonNegative(d.Spec.Replicas, path.Child("replicas"))...)
+	errs = append(errs, validateSelector(d.Spec.Selector, d.Spec.Template.Labels, path.Child("selector"))...)
+	errs = append(errs, validatePodTemplate(d.Spec.Template, path.Child("template"), corev1.RestartPolicyAlways)...)
+	errs = append(errs, validateEnum(d.Spec.Strategy.Type, path.Child("strategy", "type"),
+		appsv1.RecreateDeploymentStrategyType, appsv1.RollingUpdateDeploymentStrategyType)...)
+
+	return toAggregate(errs)
+}
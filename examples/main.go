@@ -68,8 +68,7 @@ config
 
 	// can also call the options later for conditionals
 	if true {
-		f := kopts.ContainerEnvVar("added", "later")
-		f(&c)
+		c.Apply(kopts.ContainerEnvVar("added", "later"))
 	}
 
 	p := kopts.NewPodSpec("test",
@@ -78,12 +77,13 @@ config
 		kopts.PodConfigmapAsVolume(conf.Name, conf),
 	)
 
+	seconds := 30
 	t := kopts.Toleration{
 		Key:               "foo",
 		Value:             "bar",
-		TolerationSeconds: 30,
-		Operator:          "equals",
-		Effect:            "NoSchedule",
+		TolerationSeconds: &seconds,
+		Operator:          "Equal",
+		Effect:            "NoExecute",
 	}
 
 	d := kopts.NewDeployment("testing",
@@ -95,11 +95,18 @@ config
 		kopts.DeploymentNodeSelector(map[string]string{"foo": "bar"}),
 	)
 
-	x := kopts.DeploymentReplicas(1)
-	x(d)
+	d.Apply(kopts.DeploymentReplicas(1))
 
 	printYaml(d)
 
+	// clone the deployment for another namespace without changing the original
+	staging := d.With(
+		kopts.DeploymentNamespace("staging"),
+		kopts.DeploymentNodeSelector(map[string]string{"foo": "staging"}),
+	)
+
+	printYaml(staging)
+
 	s := kopts.NewService("test",
 		kopts.ServiceNamespace(namespace),
 		kopts.ServicePort(80, 8080),
@@ -0,0 +1,341 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	gatewayv1 "github.com/CoverWhale/kopts/apis/gateway/v1"
+)
+
+// Gateway holds a Gateway API gateway
+type Gateway struct {
+	gatewayv1.Gateway
+}
+
+type GatewayOpt func(*Gateway)
+
+// NewGateway returns a gateway with the given name and options
+func NewGateway(name string, opts ...GatewayOpt) Gateway {
+	g := Gateway{
+		Gateway: gatewayv1.Gateway{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Gateway",
+				APIVersion: gatewayv1.APIVersion,
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&g)
+	}
+
+	return g
+}
+
+// GatewayNamespace sets the namespace for the gateway
+func GatewayNamespace(n string) GatewayOpt {
+	return func(g *Gateway) {
+		setNamespace(n, &g.ObjectMeta)
+	}
+}
+
+// GatewayClassName sets the gateway class implementing the gateway
+func GatewayClassName(name string) GatewayOpt {
+	return func(g *Gateway) {
+		g.Spec.GatewayClassName = name
+	}
+}
+
+// GatewayListener adds a listener to the gateway
+func GatewayListener(l Listener) GatewayOpt {
+	return func(g *Gateway) {
+		g.Spec.Listeners = append(g.Spec.Listeners, deepCopyJSON(l.Listener))
+	}
+}
+
+// GatewayAddress requests an address for the gateway
+func GatewayAddress(value string) GatewayOpt {
+	return func(g *Gateway) {
+		g.Spec.Addresses = append(g.Spec.Addresses, gatewayv1.GatewayAddress{
+			Value: value,
+		})
+	}
+}
+
+// Apply applies the options to the gateway
+func (g *Gateway) Apply(opts ...GatewayOpt) {
+	for _, opt := range opts {
+		opt(g)
+	}
+}
+
+// With returns a copy of the gateway with the options applied
+func (g Gateway) With(opts ...GatewayOpt) Gateway {
+	clone := g.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the gateway
+func (g Gateway) Clone() Gateway {
+	return Gateway{Gateway: deepCopyJSON(g.Gateway)}
+}
+
+// Validate checks the metadata, that the gateway class is set and that the listeners are valid and uniquely named
+func (g Gateway) Validate() error {
+	errs := validateObjectMeta(&g.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	if g.Spec.GatewayClassName == "" {
+		errs = append(errs, field.Required(path.Child("gatewayClassName"), ""))
+	}
+	if len(g.Spec.Listeners) == 0 {
+		errs = append(errs, field.Required(path.Child("listeners"), "at least one listener is required"))
+	}
+
+	names := sets.New[string]()
+	for i, l := range g.Spec.Listeners {
+		listenerPath := path.Child("listeners").Index(i)
+		if names.Has(l.Name) {
+			errs = append(errs, field.Duplicate(listenerPath.Child("name"), l.Name))
+		}
+		names.Insert(l.Name)
+		errs = append(errs, validateListener(l, listenerPath)...)
+	}
+
+	for i, a := range g.Spec.Addresses {
+		if a.Value == "" {
+			errs = append(errs, field.Required(path.Child("addresses").Index(i).Child("value"), ""))
+		}
+	}
+
+	return toAggregate(errs)
+}
+
+// Listener holds a gateway listener
+type Listener struct {
+	gatewayv1.Listener
+}
+
+type ListenerOpt func(*Listener)
+
+// NewListener returns a listener with the given name, port, protocol and options
+func NewListener(name string, port int, protocol gatewayv1.ProtocolType, opts ...ListenerOpt) Listener {
+	l := Listener{
+		Listener: gatewayv1.Listener{
+			Name:     name,
+			Port:     int32(port),
+			Protocol: protocol,
+		},
+	}
+
+	for _, v := range opts {
+		v(&l)
+	}
+
+	return l
+}
+
+// ListenerHostname sets the hostname the listener matches. It can have a leading wildcard label
+func ListenerHostname(h string) ListenerOpt {
+	return func(l *Listener) {
+		l.Hostname = ptrTo(h)
+	}
+}
+
+// ListenerCertificate adds the TLS secret as a certificate and terminates TLS at the listener.
+// Secrets in another namespace than the gateway need a ReferenceGrant
+func ListenerCertificate(s Secret) ListenerOpt {
+	return ListenerCertificateRef(s.Name, s.Namespace)
+}
+
+// ListenerCertificateRef adds the TLS secret with the name as a certificate and terminates TLS at the listener.
+// An empty namespace refers to the gateway namespace
+func ListenerCertificateRef(name, namespace string) ListenerOpt {
+	return func(l *Listener) {
+		ref := gatewayv1.SecretObjectReference{
+			Name: name,
+		}
+
+		if namespace != "" {
+			ref.Namespace = ptrTo(namespace)
+		}
+
+		l.tls(gatewayv1.TLSModeTerminate)
+		l.TLS.CertificateRefs = append(l.TLS.CertificateRefs, ref)
+	}
+}
+
+// ListenerTLSPassthrough passes TLS connections through to the backends without terminating them
+func ListenerTLSPassthrough() ListenerOpt {
+	return func(l *Listener) {
+		l.tls(gatewayv1.TLSModePassthrough)
+	}
+}
+
+func (l *Listener) tls(mode gatewayv1.TLSModeType) {
+	if l.TLS == nil {
+		l.TLS = &gatewayv1.GatewayTLSConfig{}
+	}
+	l.TLS.Mode = &mode
+}
+
+// ListenerAllowedRoutesFrom sets the namespaces routes may be attached from
+func ListenerAllowedRoutesFrom(from gatewayv1.FromNamespaces) ListenerOpt {
+	return func(l *Listener) {
+		l.routeNamespaces().From = ptrTo(from)
+	}
+}
+
+// ListenerAllowedRoutesSelector allows routes from namespaces with the label
+func ListenerAllowedRoutesSelector(key, value string) ListenerOpt {
+	return func(l *Listener) {
+		ns := l.routeNamespaces()
+		ns.From = ptrTo(gatewayv1.NamespacesFromSelector)
+		if ns.Selector == nil {
+			ns.Selector = &metav1.LabelSelector{}
+		}
+		metav1.AddLabelToSelector(ns.Selector, key, value)
+	}
+}
+
+// ListenerAllowedRouteKind restricts the route kinds, such as HTTPRoute, that can attach to the listener
+func ListenerAllowedRouteKind(kind string) ListenerOpt {
+	return func(l *Listener) {
+		if l.AllowedRoutes == nil {
+			l.AllowedRoutes = &gatewayv1.AllowedRoutes{}
+		}
+		l.AllowedRoutes.Kinds = append(l.AllowedRoutes.Kinds, gatewayv1.RouteGroupKind{
+			Kind: kind,
+		})
+	}
+}
+
+func (l *Listener) routeNamespaces() *gatewayv1.RouteNamespaces {
+	if l.AllowedRoutes == nil {
+		l.AllowedRoutes = &gatewayv1.AllowedRoutes{}
+	}
+	if l.AllowedRoutes.Namespaces == nil {
+		l.AllowedRoutes.Namespaces = &gatewayv1.RouteNamespaces{}
+	}
+
+	return l.AllowedRoutes.Namespaces
+}
+
+func gatewayParentRef(g Gateway, section string) gatewayv1.ParentReference {
+	ref := gatewayv1.ParentReference{
+		Name: g.Name,
+	}
+
+	if g.Namespace != "" {
+		ref.Namespace = ptrTo(g.Namespace)
+	}
+
+	if section != "" {
+		ref.SectionName = &section
+	}
+
+	return ref
+}
+
+func serviceBackendRef(s Service, port int) gatewayv1.BackendObjectReference {
+	ref := gatewayv1.BackendObjectReference{
+		Name: s.Name,
+		Port: ptrTo(int32(port)),
+	}
+
+	if s.Namespace != "" {
+		ref.Namespace = ptrTo(s.Namespace)
+	}
+
+	return ref
+}
+
+// Apply applies the options to the listener
+func (l *Listener) Apply(opts ...ListenerOpt) {
+	for _, opt := range opts {
+		opt(l)
+	}
+}
+
+// With returns a copy of the listener with the options applied
+func (l Listener) With(opts ...ListenerOpt) Listener {
+	clone := l.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the listener
+func (l Listener) Clone() Listener {
+	return Listener{Listener: deepCopyJSON(l.Listener)}
+}
+
+// Validate checks the listener name, port, protocol and hostname and that TLS is only configured for HTTPS and
+// TLS listeners
+func (l Listener) Validate() error {
+	return toAggregate(validateListener(l.Listener, nil))
+}
+
+func validateListener(l gatewayv1.Listener, path *field.Path) field.ErrorList {
+	errs := validateDNSSubdomain(l.Name, path.Child("name"))
+	errs = append(errs, validatePort(l.Port, path.Child("port"))...)
+
+	if l.Protocol == "" {
+		errs = append(errs, field.Required(path.Child("protocol"), ""))
+	}
+	errs = append(errs, validateEnum(l.Protocol, path.Child("protocol"), gatewayv1.HTTPProtocolType, gatewayv1.HTTPSProtocolType,
+		gatewayv1.TLSProtocolType, gatewayv1.TCPProtocolType, gatewayv1.UDPProtocolType)...)
+
+	if l.Hostname != nil {
+		if l.Protocol == gatewayv1.TCPProtocolType || l.Protocol == gatewayv1.UDPProtocolType {
+			errs = append(errs, field.Forbidden(path.Child("hostname"), "not allowed for TCP and UDP listeners"))
+		}
+		errs = append(errs, validateHostname(*l.Hostname, path.Child("hostname"))...)
+	}
+
+	switch l.Protocol {
+	case gatewayv1.HTTPSProtocolType, gatewayv1.TLSProtocolType:
+		if l.TLS == nil {
+			errs = append(errs, field.Required(path.Child("tls"), fmt.Sprintf("required for %s listeners", l.Protocol)))
+			break
+		}
+		mode := gatewayv1.TLSModeTerminate
+		if l.TLS.Mode != nil {
+			mode = *l.TLS.Mode
+		}
+		errs = append(errs, validateEnum(mode, path.Child("tls", "mode"), gatewayv1.TLSModeTerminate, gatewayv1.TLSModePassthrough)...)
+		switch {
+		case mode == gatewayv1.TLSModeTerminate && len(l.TLS.CertificateRefs) == 0 && len(l.TLS.Options) == 0:
+			errs = append(errs, field.Required(path.Child("tls", "certificateRefs"), "required when terminating TLS"))
+		case mode == gatewayv1.TLSModePassthrough && l.Protocol == gatewayv1.HTTPSProtocolType:
+			errs = append(errs, field.Forbidden(path.Child("tls", "mode"), "Passthrough is not allowed for HTTPS listeners"))
+		}
+	default:
+		if l.TLS != nil {
+			errs = append(errs, field.Forbidden(path.Child("tls"), fmt.Sprintf("not allowed for %s listeners", l.Protocol)))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,202 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	gatewayv1 "github.com/CoverWhale/kopts/apis/gateway/v1"
+	gatewayv1beta1 "github.com/CoverWhale/kopts/apis/gateway/v1beta1"
+)
+
+func TestGatewayRoutes(t *testing.T) {
+	cert := NewSecret("web-tls", SecretNamespace("certs"))
+	g := NewGateway("public",
+		GatewayNamespace("gateways"),
+		GatewayClassName("envoy"),
+		GatewayListener(NewListener("http", 80, gatewayv1.HTTPProtocolType)),
+		GatewayListener(NewListener("https", 443, gatewayv1.HTTPSProtocolType,
+			ListenerHostname("*.example.com"),
+			ListenerCertificate(cert),
+			ListenerAllowedRoutesSelector("gateway-access", "public"),
+		)),
+	)
+	svc := NewService("web", ServiceNamespace("apps"), ServicePort(80, 8080))
+
+	route := NewHTTPRoute("web",
+		HTTPRouteNamespace("apps"),
+		HTTPRouteGatewayListener(g, "https"),
+		HTTPRouteHostnames("www.example.com"),
+		HTTPRouteRule(NewHTTPRule(
+			HTTPRuleMatch(HTTPMatchPath(gatewayv1.PathMatchPathPrefix, "/api"), HTTPMatchMethod("GET")),
+			HTTPRuleRewritePrefix("/"),
+			HTTPRuleSetRequestHeader("X-Forwarded-Prefix", "/api"),
+			HTTPRuleWeightedBackend(svc, 80, 90),
+			HTTPRuleWeightedBackend(NewService("web-canary", ServiceNamespace("apps")), 80, 10),
+			HTTPRuleTimeout("10s"),
+		)),
+	)
+	grpc := NewGRPCRoute("orders",
+		GRPCRouteNamespace("apps"),
+		GRPCRouteGateway(g),
+		GRPCRouteRule(NewGRPCRule(GRPCRuleMatch(GRPCMatchMethod("orders.v1.Orders", "")), GRPCRuleBackend(svc, 80))),
+	)
+	grant := NewReferenceGrant("gateway-certs", ReferenceGrantFromGateway(g), ReferenceGrantToSecret(cert))
+
+	wantParent := gatewayv1.ParentReference{Name: "public", Namespace: ptrTo("gateways"), SectionName: ptrTo("https")}
+	if !reflect.DeepEqual(route.Spec.ParentRefs, []gatewayv1.ParentReference{wantParent}) {
+		t.Errorf("parentRefs = %+v, want %+v", route.Spec.ParentRefs, wantParent)
+	}
+	if grpc.Spec.ParentRefs[0].SectionName != nil {
+		t.Errorf("gRPC route sectionName = %q, want none", *grpc.Spec.ParentRefs[0].SectionName)
+	}
+
+	wantGrant := gatewayv1beta1.ReferenceGrantSpec{
+		From: []gatewayv1beta1.ReferenceGrantFrom{{Group: gatewayv1.GroupName, Kind: "Gateway", Namespace: "gateways"}},
+		To:   []gatewayv1beta1.ReferenceGrantTo{{Kind: "Secret", Name: ptrTo("web-tls")}},
+	}
+	if grant.Namespace != "certs" || !reflect.DeepEqual(grant.Spec, wantGrant) {
+		t.Errorf("reference grant %s = %+v, want certs = %+v", grant.Namespace, grant.Spec, wantGrant)
+	}
+
+	for _, v := range []Validator{g, route, grpc, grant} {
+		if err := v.Validate(); err != nil {
+			t.Errorf("%T Validate() = %v", v, err)
+		}
+	}
+}
+
+func TestGatewaySharedOptions(t *testing.T) {
+	listener := NewListener("https", 443, gatewayv1.HTTPSProtocolType, ListenerHostname("example.com"), ListenerCertificateRef("tls", "certs"))
+	rule := NewHTTPRule(HTTPRuleName("api"), HTTPRuleMatch(HTTPMatchPath(gatewayv1.PathMatchExact, "/api")))
+
+	tests := []struct {
+		name   string
+		build  func() interface{}
+		mutate func(interface{})
+		check  func(interface{}) bool
+	}{
+		{
+			name:   "gateway listener",
+			build:  func() interface{} { return NewGateway("public", GatewayListener(listener)) },
+			mutate: func(o interface{}) { *o.(Gateway).Spec.Listeners[0].Hostname = "other.com" },
+			check:  func(o interface{}) bool { return *o.(Gateway).Spec.Listeners[0].Hostname == "example.com" },
+		},
+		{
+			name:  "certificate namespace",
+			build: func() interface{} { return NewGateway("public", GatewayListener(listener)) },
+			mutate: func(o interface{}) {
+				*o.(Gateway).Spec.Listeners[0].TLS.CertificateRefs[0].Namespace = "other"
+			},
+			check: func(o interface{}) bool {
+				return *o.(Gateway).Spec.Listeners[0].TLS.CertificateRefs[0].Namespace == "certs"
+			},
+		},
+		{
+			name:   "route rule",
+			build:  func() interface{} { return NewHTTPRoute("web", HTTPRouteRule(rule)) },
+			mutate: func(o interface{}) { *o.(HTTPRoute).Spec.Rules[0].Matches[0].Path.Value = "/other" },
+			check:  func(o interface{}) bool { return *o.(HTTPRoute).Spec.Rules[0].Matches[0].Path.Value == "/api" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := tt.build()
+			second := tt.build()
+
+			tt.mutate(first)
+			if !tt.check(second) {
+				t.Error("changing one object changed another built from the same option")
+			}
+		})
+	}
+}
+
+func TestHTTPRuleFilters(t *testing.T) {
+	mirror := NewService("shadow")
+	rule := NewHTTPRule(
+		HTTPRuleSetRequestHeader("X-Env", "prod"),
+		HTTPRuleSetRequestHeader("X-Team", "web"),
+		HTTPRuleMirror(mirror, 80),
+		HTTPRuleMirror(NewService("audit"), 80),
+	)
+
+	if len(rule.Filters) != 3 {
+		t.Fatalf("filters = %d, want one header modifier and two mirrors", len(rule.Filters))
+	}
+	if got := len(rule.Filters[0].RequestHeaderModifier.Set); got != 2 {
+		t.Errorf("headers set = %d, want 2 in a single filter", got)
+	}
+	if rule.Filters[1].RequestMirror.BackendRef.Name != "shadow" || rule.Filters[2].RequestMirror.BackendRef.Name != "audit" {
+		t.Errorf("mirrors = %+v and %+v, want shadow and audit", rule.Filters[1].RequestMirror, rule.Filters[2].RequestMirror)
+	}
+}
+
+func TestGatewayValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		object Validator
+		fields []string
+	}{
+		{
+			name:   "gateway without class and listeners",
+			object: NewGateway("public"),
+			fields: []string{"spec.gatewayClassName", "spec.listeners"},
+		},
+		{
+			name: "duplicate listener",
+			object: NewGateway("public", GatewayClassName("envoy"),
+				GatewayListener(NewListener("http", 80, gatewayv1.HTTPProtocolType)),
+				GatewayListener(NewListener("http", 8080, gatewayv1.HTTPProtocolType))),
+			fields: []string{"spec.listeners[1].name"},
+		},
+		{
+			name:   "https listener without certificate",
+			object: NewListener("https", 443, gatewayv1.HTTPSProtocolType),
+			fields: []string{"tls"},
+		},
+		{
+			name:   "https passthrough",
+			object: NewListener("https", 443, gatewayv1.HTTPSProtocolType, ListenerTLSPassthrough()),
+			fields: []string{"tls.mode"},
+		},
+		{
+			name:   "tcp listener with hostname",
+			object: NewListener("db", 5432, gatewayv1.TCPProtocolType, ListenerHostname("db.example.com")),
+			fields: []string{"hostname"},
+		},
+		{
+			name:   "relative path",
+			object: NewHTTPRule(HTTPRuleMatch(HTTPMatchPath(gatewayv1.PathMatchPathPrefix, "api"))),
+			fields: []string{"matches[0].path.value"},
+		},
+		{
+			name:   "service backend without port",
+			object: NewHTTPRule(HTTPRuleRedirect("https", 307), HTTPRuleBackend(NewService("web"), 0)),
+			fields: []string{"backendRefs[0].port", "filters[0].requestRedirect.statusCode"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, tt.object.Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	gatewayv1 "github.com/CoverWhale/kopts/apis/gateway/v1"
+)
+
+// GRPCRoute holds a Gateway API gRPC route
+type GRPCRoute struct {
+	gatewayv1.GRPCRoute
+}
+
+type GRPCRouteOpt func(*GRPCRoute)
+
+// NewGRPCRoute returns a gRPC route with the given name and options
+func NewGRPCRoute(name string, opts ...GRPCRouteOpt) GRPCRoute {
+	r := GRPCRoute{
+		GRPCRoute: gatewayv1.GRPCRoute{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "GRPCRoute",
+				APIVersion: gatewayv1.APIVersion,
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&r)
+	}
+
+	return r
+}
+
+// GRPCRouteNamespace sets the namespace for the route
+func GRPCRouteNamespace(n string) GRPCRouteOpt {
+	return func(r *GRPCRoute) {
+		setNamespace(n, &r.ObjectMeta)
+	}
+}
+
+// GRPCRouteGateway attaches the route to all listeners of the gateway
+func GRPCRouteGateway(g Gateway) GRPCRouteOpt {
+	return GRPCRouteParentRef(gatewayParentRef(g, ""))
+}
+
+// GRPCRouteGatewayListener attaches the route to the named listener of the gateway
+func GRPCRouteGatewayListener(g Gateway, listener string) GRPCRouteOpt {
+	return GRPCRouteParentRef(gatewayParentRef(g, listener))
+}
+
+// GRPCRouteParentRef attaches the route to the parent
+func GRPCRouteParentRef(ref gatewayv1.ParentReference) GRPCRouteOpt {
+	return func(r *GRPCRoute) {
+		r.Spec.ParentRefs = append(r.Spec.ParentRefs, deepCopyJSON(ref))
+	}
+}
+
+// GRPCRouteHostnames adds hostnames matched against the authority header
+func GRPCRouteHostnames(hosts ...string) GRPCRouteOpt {
+	return func(r *GRPCRoute) {
+		r.Spec.Hostnames = append(r.Spec.Hostnames, hosts...)
+	}
+}
+
+// GRPCRouteRule adds a rule to the route
+func GRPCRouteRule(rule GRPCRule) GRPCRouteOpt {
+	return func(r *GRPCRoute) {
+		r.Spec.Rules = append(r.Spec.Rules, deepCopyJSON(rule.GRPCRouteRule))
+	}
+}
+
+// Apply applies the options to the route
+func (r *GRPCRoute) Apply(opts ...GRPCRouteOpt) {
+	for _, opt := range opts {
+		opt(r)
+	}
+}
+
+// With returns a copy of the route with the options applied
+func (r GRPCRoute) With(opts ...GRPCRouteOpt) GRPCRoute {
+	clone := r.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the route
+func (r GRPCRoute) Clone() GRPCRoute {
+	return GRPCRoute{GRPCRoute: deepCopyJSON(r.GRPCRoute)}
+}
+
+// Validate checks the metadata, the parent references, the hostnames and the rules
+func (r GRPCRoute) Validate() error {
+	errs := validateObjectMeta(&r.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateRouteSpec(r.Spec.CommonRouteSpec, r.Spec.Hostnames, path)...)
+	for i, rule := range r.Spec.Rules {
+		errs = append(errs, validateGRPCRouteRule(rule, path.Child("rules").Index(i))...)
+	}
+
+	return toAggregate(errs)
+}
+
+// GRPCRule holds a gRPC route rule
+type GRPCRule struct {
+	gatewayv1.GRPCRouteRule
+}
+
+type GRPCRuleOpt func(*GRPCRule)
+
+// GRPCMatchOpt sets a predicate of a single gRPC request match
+type GRPCMatchOpt func(*gatewayv1.GRPCRouteMatch)
+
+// NewGRPCRule returns a gRPC route rule with the given options
+func NewGRPCRule(opts ...GRPCRuleOpt) GRPCRule {
+	r := GRPCRule{}
+
+	for _, v := range opts {
+		v(&r)
+	}
+
+	return r
+}
+
+// GRPCRuleName sets the rule name
+func GRPCRuleName(name string) GRPCRuleOpt {
+	return func(r *GRPCRule) {
+		r.Name = ptrTo(name)
+	}
+}
+
+// GRPCRuleMatch adds a match built from the options. A request must satisfy all options of a match,
+// and the rule applies when any of its matches is satisfied
+func GRPCRuleMatch(opts ...GRPCMatchOpt) GRPCRuleOpt {
+	return func(r *GRPCRule) {
+		m := gatewayv1.GRPCRouteMatch{}
+		for _, v := range opts {
+			v(&m)
+		}
+		r.Matches = append(r.Matches, m)
+	}
+}
+
+// GRPCMatchMethod matches the gRPC service and method exactly. An empty method matches every method of the service
+func GRPCMatchMethod(service, method string) GRPCMatchOpt {
+	return func(m *gatewayv1.GRPCRouteMatch) {
+		m.Method = &gatewayv1.GRPCMethodMatch{
+			Type:    ptrTo(gatewayv1.GRPCMethodMatchExact),
+			Service: ptrTo(service),
+		}
+		if method != "" {
+			m.Method.Method = ptrTo(method)
+		}
+	}
+}
+
+// GRPCMatchHeader matches a request header exactly
+func GRPCMatchHeader(name, value string) GRPCMatchOpt {
+	return func(m *gatewayv1.GRPCRouteMatch) {
+		m.Headers = append(m.Headers, gatewayv1.GRPCHeaderMatch{
+			Name:  name,
+			Value: value,
+		})
+	}
+}
+
+// GRPCRuleBackend forwards requests to the service port
+func GRPCRuleBackend(s Service, port int) GRPCRuleOpt {
+	return func(r *GRPCRule) {
+		r.BackendRefs = append(r.BackendRefs, gatewayv1.GRPCBackendRef{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: serviceBackendRef(s, port),
+			},
+		})
+	}
+}
+
+// GRPCRuleWeightedBackend forwards a share of the requests to the service port.
+// The share is the weight divided by the sum of the weights of the rule backends
+func GRPCRuleWeightedBackend(s Service, port, weight int) GRPCRuleOpt {
+	return func(r *GRPCRule) {
+		r.BackendRefs = append(r.BackendRefs, gatewayv1.GRPCBackendRef{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: serviceBackendRef(s, port),
+				Weight:                 ptrTo(int32(weight)),
+			},
+		})
+	}
+}
+
+// GRPCRuleFilter adds a filter to the rule
+func GRPCRuleFilter(f gatewayv1.GRPCRouteFilter) GRPCRuleOpt {
+	return func(r *GRPCRule) {
+		r.Filters = append(r.Filters, deepCopyJSON(f))
+	}
+}
+
+// Apply applies the options to the rule
+func (r *GRPCRule) Apply(opts ...GRPCRuleOpt) {
+	for _, opt := range opts {
+		opt(r)
+	}
+}
+
+// With returns a copy of the rule with the options applied
+func (r GRPCRule) With(opts ...GRPCRuleOpt) GRPCRule {
+	clone := r.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the rule
+func (r GRPCRule) Clone() GRPCRule {
+	return GRPCRule{GRPCRouteRule: deepCopyJSON(r.GRPCRouteRule)}
+}
+
+// Validate checks the matches, the filters and that the backends name a service port
+func (r GRPCRule) Validate() error {
+	return toAggregate(validateGRPCRouteRule(r.GRPCRouteRule, nil))
+}
+
+func validateGRPCRouteRule(r gatewayv1.GRPCRouteRule, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	for i, m := range r.Matches {
+		matchPath := path.Child("matches").Index(i)
+		if m.Method != nil && ptrValue(m.Method.Service) == "" && ptrValue(m.Method.Method) == "" {
+			errs = append(errs, field.Required(matchPath.Child("method"), "one of service or method must be set"))
+		}
+		for j, h := range m.Headers {
+			if h.Name == "" {
+				errs = append(errs, field.Required(matchPath.Child("headers").Index(j).Child("name"), ""))
+			}
+		}
+	}
+
+	for i, f := range r.Filters {
+		errs = append(errs, validateGRPCRouteFilter(f, path.Child("filters").Index(i))...)
+	}
+
+	for i, b := range r.BackendRefs {
+		backendPath := path.Child("backendRefs").Index(i)
+		errs = append(errs, validateBackendRef(b.BackendRef, backendPath)...)
+		for j, f := range b.Filters {
+			errs = append(errs, validateGRPCRouteFilter(f, backendPath.Child("filters").Index(j))...)
+		}
+	}
+
+	return errs
+}
+
+// validateGRPCRouteFilter checks that the field matching the filter type is set
+func validateGRPCRouteFilter(f gatewayv1.GRPCRouteFilter, path *field.Path) field.ErrorList {
+	set := map[gatewayv1.GRPCRouteFilterType]bool{
+		gatewayv1.GRPCRouteFilterRequestHeaderModifier:  f.RequestHeaderModifier != nil,
+		gatewayv1.GRPCRouteFilterResponseHeaderModifier: f.ResponseHeaderModifier != nil,
+		gatewayv1.GRPCRouteFilterRequestMirror:          f.RequestMirror != nil,
+		gatewayv1.GRPCRouteFilterExtensionRef:           f.ExtensionRef != nil,
+	}
+
+	errs := validateRouteFilterType(f.Type, set, path)
+	if f.RequestMirror != nil {
+		errs = append(errs, validateBackendObjectReference(f.RequestMirror.BackendRef, path.Child("requestMirror", "backendRef"))...)
+	}
+
+	return errs
+}
@@ -0,0 +1,390 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"maps"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// HPA holds a Kubernetes horizontal pod autoscaler
+type HPA struct {
+	autoscalingv2.HorizontalPodAutoscaler
+}
+
+type HPAOpt func(*HPA)
+
+// ScalingPolicy holds a single scale up or scale down policy
+type ScalingPolicy struct {
+	Type          autoscalingv2.HPAScalingPolicyType
+	Value         int
+	PeriodSeconds int
+}
+
+// NewHorizontalPodAutoscaler returns a horizontal pod autoscaler with the given name and options
+func NewHorizontalPodAutoscaler(name string, opts ...HPAOpt) HPA {
+	h := HPA{
+		HorizontalPodAutoscaler: autoscalingv2.HorizontalPodAutoscaler{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "HorizontalPodAutoscaler",
+				APIVersion: "autoscaling/v2",
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&h)
+	}
+
+	return h
+}
+
+// HPANamespace sets the namespace for the autoscaler
+func HPANamespace(n string) HPAOpt {
+	return func(h *HPA) {
+		setNamespace(n, &h.ObjectMeta)
+	}
+}
+
+// HPADeployment sets the deployment as the scale target. The autoscaler is placed in the deployment namespace
+func HPADeployment(d *Deployment) HPAOpt {
+	return hpaScaleTarget(d.TypeMeta, d.ObjectMeta)
+}
+
+// HPAStatefulSet sets the stateful set as the scale target. The autoscaler is placed in the stateful set namespace
+func HPAStatefulSet(s *StatefulSet) HPAOpt {
+	return hpaScaleTarget(s.TypeMeta, s.ObjectMeta)
+}
+
+func hpaScaleTarget(t metav1.TypeMeta, m metav1.ObjectMeta) HPAOpt {
+	return func(h *HPA) {
+		if m.Namespace != "" {
+			setNamespace(m.Namespace, &h.ObjectMeta)
+		}
+		h.Spec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{
+			Kind:       t.Kind,
+			APIVersion: t.APIVersion,
+			Name:       m.Name,
+		}
+	}
+}
+
+// HPAMinReplicas sets the minimum replicas
+func HPAMinReplicas(i int) HPAOpt {
+	return func(h *HPA) {
+		replicas := int32(i)
+		h.Spec.MinReplicas = &replicas
+	}
+}
+
+// HPAMaxReplicas sets the maximum replicas
+func HPAMaxReplicas(i int) HPAOpt {
+	replicas := int32(i)
+	return func(h *HPA) {
+		h.Spec.MaxReplicas = replicas
+	}
+}
+
+// HPAMetric adds a metric
+func HPAMetric(m autoscalingv2.MetricSpec) HPAOpt {
+	return func(h *HPA) {
+		h.Spec.Metrics = append(h.Spec.Metrics, *m.DeepCopy())
+	}
+}
+
+// HPAResourceUtilization adds a resource metric with an average utilization target in percent
+func HPAResourceUtilization(r corev1.ResourceName, percent int) HPAOpt {
+	return func(h *HPA) {
+		utilization := int32(percent)
+		h.Spec.Metrics = append(h.Spec.Metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: r,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: &utilization,
+				},
+			},
+		})
+	}
+}
+
+// HPACPUUtilization adds a CPU average utilization target in percent
+func HPACPUUtilization(percent int) HPAOpt {
+	return HPAResourceUtilization(corev1.ResourceCPU, percent)
+}
+
+// HPAMemoryUtilization adds a memory average utilization target in percent
+func HPAMemoryUtilization(percent int) HPAOpt {
+	return HPAResourceUtilization(corev1.ResourceMemory, percent)
+}
+
+// HPAPodsMetric adds a custom metric averaged across the pods
+func HPAPodsMetric(name string, averageValue resource.Quantity) HPAOpt {
+	return func(h *HPA) {
+		value := averageValue.DeepCopy()
+		h.Spec.Metrics = append(h.Spec.Metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.PodsMetricSourceType,
+			Pods: &autoscalingv2.PodsMetricSource{
+				Metric: autoscalingv2.MetricIdentifier{
+					Name: name,
+				},
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: &value,
+				},
+			},
+		})
+	}
+}
+
+// HPAObjectMetric adds a custom metric describing a single object in the autoscaler namespace
+func HPAObjectMetric(name string, object autoscalingv2.CrossVersionObjectReference, value resource.Quantity) HPAOpt {
+	return func(h *HPA) {
+		target := value.DeepCopy()
+		h.Spec.Metrics = append(h.Spec.Metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ObjectMetricSourceType,
+			Object: &autoscalingv2.ObjectMetricSource{
+				DescribedObject: object,
+				Metric: autoscalingv2.MetricIdentifier{
+					Name: name,
+				},
+				Target: autoscalingv2.MetricTarget{
+					Type:  autoscalingv2.ValueMetricType,
+					Value: &target,
+				},
+			},
+		})
+	}
+}
+
+// HPAExternalMetric adds an external metric. The selector labels narrow down the metric series and can be nil
+func HPAExternalMetric(name string, selector map[string]string, averageValue resource.Quantity) HPAOpt {
+	return func(h *HPA) {
+		metric := autoscalingv2.MetricIdentifier{
+			Name: name,
+		}
+
+		if len(selector) > 0 {
+			metric.Selector = &metav1.LabelSelector{
+				MatchLabels: maps.Clone(selector),
+			}
+		}
+
+		value := averageValue.DeepCopy()
+		h.Spec.Metrics = append(h.Spec.Metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ExternalMetricSourceType,
+			External: &autoscalingv2.ExternalMetricSource{
+				Metric: metric,
+				Target: autoscalingv2.MetricTarget{
+					Type:         autoscalingv2.AverageValueMetricType,
+					AverageValue: &value,
+				},
+			},
+		})
+	}
+}
+
+// HPAScaleUp sets the scale up behavior
+func HPAScaleUp(stabilizationWindowSeconds int, policies ...ScalingPolicy) HPAOpt {
+	return func(h *HPA) {
+		if h.Spec.Behavior == nil {
+			h.Spec.Behavior = &autoscalingv2.HorizontalPodAutoscalerBehavior{}
+		}
+		h.Spec.Behavior.ScaleUp = hpaScalingRules(stabilizationWindowSeconds, policies)
+	}
+}
+
+// HPAScaleDown sets the scale down behavior
+func HPAScaleDown(stabilizationWindowSeconds int, policies ...ScalingPolicy) HPAOpt {
+	return func(h *HPA) {
+		if h.Spec.Behavior == nil {
+			h.Spec.Behavior = &autoscalingv2.HorizontalPodAutoscalerBehavior{}
+		}
+		h.Spec.Behavior.ScaleDown = hpaScalingRules(stabilizationWindowSeconds, policies)
+	}
+}
+
+func hpaScalingRules(stabilizationWindowSeconds int, policies []ScalingPolicy) *autoscalingv2.HPAScalingRules {
+	window := int32(stabilizationWindowSeconds)
+	rules := &autoscalingv2.HPAScalingRules{
+		StabilizationWindowSeconds: &window,
+	}
+
+	for _, v := range policies {
+		rules.Policies = append(rules.Policies, autoscalingv2.HPAScalingPolicy{
+			Type:          v.Type,
+			Value:         int32(v.Value),
+			PeriodSeconds: int32(v.PeriodSeconds),
+		})
+	}
+
+	return rules
+}
+
+// Apply applies the options to the horizontal pod autoscaler
+func (h *HPA) Apply(opts ...HPAOpt) {
+	for _, opt := range opts {
+		opt(h)
+	}
+}
+
+// With returns a copy of the horizontal pod autoscaler with the options applied
+func (h HPA) With(opts ...HPAOpt) HPA {
+	clone := h.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the horizontal pod autoscaler
+func (h HPA) Clone() HPA {
+	return HPA{HorizontalPodAutoscaler: *h.HorizontalPodAutoscaler.DeepCopy()}
+}
+
+// Validate checks the metadata, the scale target, the replica bounds, that every metric sets the source for its
+// type and the scaling behavior
+func (h HPA) Validate() error {
+	errs := validateObjectMeta(&h.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	if h.Spec.ScaleTargetRef.Kind == "" {
+		errs = append(errs, field.Required(path.Child("scaleTargetRef", "kind"), ""))
+	}
+	if h.Spec.ScaleTargetRef.Name == "" {
+		errs = append(errs, field.Required(path.Child("scaleTargetRef", "name"), ""))
+	}
+
+	if h.Spec.MaxReplicas < 1 {
+		errs = append(errs, field.Invalid(path.Child("maxReplicas"), h.Spec.MaxReplicas, "must be greater than or equal to 1"))
+	}
+	if min := h.Spec.MinReplicas; min != nil {
+		switch {
+		case *min < 1:
+			errs = append(errs, field.Invalid(path.Child("minReplicas"), *min, "must be greater than or equal to 1"))
+		case *min > h.Spec.MaxReplicas:
+			errs = append(errs, field.Invalid(path.Child("maxReplicas"), h.Spec.MaxReplicas, "must be greater than or equal to minReplicas"))
+		}
+	}
+
+	for i, m := range h.Spec.Metrics {
+		errs = append(errs, validateHPAMetric(m, path.Child("metrics").Index(i))...)
+	}
+
+	if h.Spec.Behavior != nil {
+		errs = append(errs, validateHPAScalingRules(h.Spec.Behavior.ScaleUp, path.Child("behavior", "scaleUp"))...)
+		errs = append(errs, validateHPAScalingRules(h.Spec.Behavior.ScaleDown, path.Child("behavior", "scaleDown"))...)
+	}
+
+	return toAggregate(errs)
+}
+
+func validateHPAMetric(m autoscalingv2.MetricSpec, path *field.Path) field.ErrorList {
+	var target *autoscalingv2.MetricTarget
+	var source string
+
+	switch m.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		source = "resource"
+		if m.Resource != nil {
+			target = &m.Resource.Target
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		source = "containerResource"
+		if m.ContainerResource != nil {
+			target = &m.ContainerResource.Target
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		source = "pods"
+		if m.Pods != nil {
+			target = &m.Pods.Target
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		source = "object"
+		if m.Object != nil {
+			target = &m.Object.Target
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		source = "external"
+		if m.External != nil {
+			target = &m.External.Target
+		}
+	case "":
+		return field.ErrorList{field.Required(path.Child("type"), "")}
+	default:
+		return field.ErrorList{field.NotSupported(path.Child("type"), m.Type, []string{"Resource", "ContainerResource", "Pods", "Object", "External"})}
+	}
+
+	if target == nil {
+		return field.ErrorList{field.Required(path.Child(source), fmt.Sprintf("must be set for metrics of type %s", m.Type))}
+	}
+
+	targetPath := path.Child(source, "target")
+	switch target.Type {
+	case autoscalingv2.UtilizationMetricType:
+		if target.AverageUtilization == nil || *target.AverageUtilization < 1 {
+			return field.ErrorList{field.Required(targetPath.Child("averageUtilization"), "must be greater than 0")}
+		}
+	case autoscalingv2.ValueMetricType:
+		if target.Value == nil || target.Value.Sign() <= 0 {
+			return field.ErrorList{field.Required(targetPath.Child("value"), "must be greater than 0")}
+		}
+	case autoscalingv2.AverageValueMetricType:
+		if target.AverageValue == nil || target.AverageValue.Sign() <= 0 {
+			return field.ErrorList{field.Required(targetPath.Child("averageValue"), "must be greater than 0")}
+		}
+	default:
+		return field.ErrorList{field.NotSupported(targetPath.Child("type"), target.Type, []string{"Utilization", "Value", "AverageValue"})}
+	}
+
+	return nil
+}
+
+func validateHPAScalingRules(r *autoscalingv2.HPAScalingRules, path *field.Path) field.ErrorList {
+	if r == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	if w := r.StabilizationWindowSeconds; w != nil && (*w < 0 || *w > 3600) {
+		errs = append(errs, field.Invalid(path.Child("stabilizationWindowSeconds"), *w, "must be between 0 and 3600"))
+	}
+	errs = append(errs, validateEnum(ptrValue(r.SelectPolicy), path.Child("selectPolicy"),
+		autoscalingv2.MaxChangePolicySelect, autoscalingv2.MinChangePolicySelect, autoscalingv2.DisabledPolicySelect)...)
+
+	for i, p := range r.Policies {
+		policyPath := path.Child("policies").Index(i)
+		if p.Type == "" {
+			errs = append(errs, field.Required(policyPath.Child("type"), ""))
+		}
+		errs = append(errs, validateEnum(p.Type, policyPath.Child("type"), autoscalingv2.PodsScalingPolicy, autoscalingv2.PercentScalingPolicy)...)
+		if p.Value < 1 {
+			errs = append(errs, field.Invalid(policyPath.Child("value"), p.Value, "must be greater than 0"))
+		}
+		if p.PeriodSeconds < 1 || p.PeriodSeconds > 1800 {
+			errs = append(errs, field.Invalid(policyPath.Child("periodSeconds"), p.PeriodSeconds, "must be between 1 and 1800"))
+		}
+	}
+
+	return errs
+}
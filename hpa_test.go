@@ -0,0 +1,168 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestHPAScaleTarget(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []HPAOpt
+		namespace string
+		target    autoscalingv2.CrossVersionObjectReference
+	}{
+		{
+			name:      "deployment namespace",
+			opts:      []HPAOpt{HPANamespace("apps"), HPADeployment(NewDeployment("web", DeploymentNamespace("prod")))},
+			namespace: "prod",
+			target:    autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", APIVersion: "apps/v1", Name: "web"},
+		},
+		{
+			name:      "deployment without namespace",
+			opts:      []HPAOpt{HPANamespace("apps"), HPADeployment(NewDeployment("web"))},
+			namespace: "apps",
+			target:    autoscalingv2.CrossVersionObjectReference{Kind: "Deployment", APIVersion: "apps/v1", Name: "web"},
+		},
+		{
+			name:      "stateful set",
+			opts:      []HPAOpt{HPAStatefulSet(NewStatefulSet("db", StatefulSetNamespace("data")))},
+			namespace: "data",
+			target:    autoscalingv2.CrossVersionObjectReference{Kind: "StatefulSet", APIVersion: "apps/v1", Name: "db"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHorizontalPodAutoscaler("web", tt.opts...)
+			if h.Namespace != tt.namespace {
+				t.Errorf("namespace = %q, want %q", h.Namespace, tt.namespace)
+			}
+			if h.Spec.ScaleTargetRef != tt.target {
+				t.Errorf("scaleTargetRef = %+v, want %+v", h.Spec.ScaleTargetRef, tt.target)
+			}
+		})
+	}
+}
+
+func TestHPASharedOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		opt    HPAOpt
+		mutate func(*HPA)
+		check  func(HPA) bool
+	}{
+		{
+			name:   "min replicas",
+			opt:    HPAMinReplicas(2),
+			mutate: func(h *HPA) { *h.Spec.MinReplicas = 5 },
+			check:  func(h HPA) bool { return *h.Spec.MinReplicas == 2 },
+		},
+		{
+			name:   "resource utilization",
+			opt:    HPAResourceUtilization(corev1.ResourceCPU, 80),
+			mutate: func(h *HPA) { *h.Spec.Metrics[0].Resource.Target.AverageUtilization = 10 },
+			check:  func(h HPA) bool { return *h.Spec.Metrics[0].Resource.Target.AverageUtilization == 80 },
+		},
+		{
+			name:   "pods metric",
+			opt:    HPAPodsMetric("requests", resource.MustParse("10")),
+			mutate: func(h *HPA) { h.Spec.Metrics[0].Pods.Target.AverageValue.Set(1) },
+			check:  func(h HPA) bool { return h.Spec.Metrics[0].Pods.Target.AverageValue.Value() == 10 },
+		},
+		{
+			name: "object metric",
+			opt: HPAObjectMetric("hits", autoscalingv2.CrossVersionObjectReference{Kind: "Ingress", APIVersion: "networking.k8s.io/v1", Name: "web"},
+				resource.MustParse("100")),
+			mutate: func(h *HPA) { h.Spec.Metrics[0].Object.Target.Value.Set(1) },
+			check:  func(h HPA) bool { return h.Spec.Metrics[0].Object.Target.Value.Value() == 100 },
+		},
+		{
+			name:   "external metric",
+			opt:    HPAExternalMetric("queue", map[string]string{"queue": "jobs"}, resource.MustParse("30")),
+			mutate: func(h *HPA) { h.Spec.Metrics[0].External.Target.AverageValue.Set(1) },
+			check:  func(h HPA) bool { return h.Spec.Metrics[0].External.Target.AverageValue.Value() == 30 },
+		},
+		{
+			name:   "scale up",
+			opt:    HPAScaleUp(60, ScalingPolicy{Type: autoscalingv2.PodsScalingPolicy, Value: 4, PeriodSeconds: 60}),
+			mutate: func(h *HPA) { *h.Spec.Behavior.ScaleUp.StabilizationWindowSeconds = 0 },
+			check:  func(h HPA) bool { return *h.Spec.Behavior.ScaleUp.StabilizationWindowSeconds == 60 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := NewHorizontalPodAutoscaler("first", tt.opt)
+			second := NewHorizontalPodAutoscaler("second", tt.opt)
+
+			tt.mutate(&first)
+			if !tt.check(second) {
+				t.Error("changing one autoscaler changed another built from the same option")
+			}
+		})
+	}
+}
+
+func TestHPAValidate(t *testing.T) {
+	d := NewDeployment("web", DeploymentNamespace("prod"))
+
+	tests := []struct {
+		name   string
+		opts   []HPAOpt
+		fields []string
+	}{
+		{
+			name: "valid",
+			opts: []HPAOpt{HPADeployment(d), HPAMinReplicas(2), HPAMaxReplicas(5), HPACPUUtilization(80)},
+		},
+		{
+			name:   "missing target",
+			opts:   []HPAOpt{HPAMaxReplicas(5)},
+			fields: []string{"spec.scaleTargetRef.kind", "spec.scaleTargetRef.name"},
+		},
+		{
+			name:   "min above max",
+			opts:   []HPAOpt{HPADeployment(d), HPAMinReplicas(6), HPAMaxReplicas(5)},
+			fields: []string{"spec.maxReplicas"},
+		},
+		{
+			name:   "zero utilization",
+			opts:   []HPAOpt{HPADeployment(d), HPAMaxReplicas(5), HPAMemoryUtilization(0)},
+			fields: []string{"spec.metrics[0].resource.target.averageUtilization"},
+		},
+		{
+			name: "scale down window too long",
+			opts: []HPAOpt{HPADeployment(d), HPAMaxReplicas(5),
+				HPAScaleDown(7200, ScalingPolicy{Type: autoscalingv2.PercentScalingPolicy, Value: 50, PeriodSeconds: 60})},
+			fields: []string{"spec.behavior.scaleDown.stabilizationWindowSeconds"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, NewHorizontalPodAutoscaler("web", tt.opts...).Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,476 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	gatewayv1 "github.com/CoverWhale/kopts/apis/gateway/v1"
+)
+
+// HTTPRoute holds a Gateway API HTTP route
+type HTTPRoute struct {
+	gatewayv1.HTTPRoute
+}
+
+type HTTPRouteOpt func(*HTTPRoute)
+
+// NewHTTPRoute returns a HTTP route with the given name and options
+func NewHTTPRoute(name string, opts ...HTTPRouteOpt) HTTPRoute {
+	r := HTTPRoute{
+		HTTPRoute: gatewayv1.HTTPRoute{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "HTTPRoute",
+				APIVersion: gatewayv1.APIVersion,
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&r)
+	}
+
+	return r
+}
+
+// HTTPRouteNamespace sets the namespace for the route
+func HTTPRouteNamespace(n string) HTTPRouteOpt {
+	return func(r *HTTPRoute) {
+		setNamespace(n, &r.ObjectMeta)
+	}
+}
+
+// HTTPRouteGateway attaches the route to all listeners of the gateway
+func HTTPRouteGateway(g Gateway) HTTPRouteOpt {
+	return HTTPRouteParentRef(gatewayParentRef(g, ""))
+}
+
+// HTTPRouteGatewayListener attaches the route to the named listener of the gateway
+func HTTPRouteGatewayListener(g Gateway, listener string) HTTPRouteOpt {
+	return HTTPRouteParentRef(gatewayParentRef(g, listener))
+}
+
+// HTTPRouteParentRef attaches the route to the parent
+func HTTPRouteParentRef(ref gatewayv1.ParentReference) HTTPRouteOpt {
+	return func(r *HTTPRoute) {
+		r.Spec.ParentRefs = append(r.Spec.ParentRefs, deepCopyJSON(ref))
+	}
+}
+
+// HTTPRouteHostnames adds hostnames matched against the Host header
+func HTTPRouteHostnames(hosts ...string) HTTPRouteOpt {
+	return func(r *HTTPRoute) {
+		r.Spec.Hostnames = append(r.Spec.Hostnames, hosts...)
+	}
+}
+
+// HTTPRouteRule adds a rule to the route
+func HTTPRouteRule(rule HTTPRule) HTTPRouteOpt {
+	return func(r *HTTPRoute) {
+		r.Spec.Rules = append(r.Spec.Rules, deepCopyJSON(rule.HTTPRouteRule))
+	}
+}
+
+// Apply applies the options to the route
+func (r *HTTPRoute) Apply(opts ...HTTPRouteOpt) {
+	for _, opt := range opts {
+		opt(r)
+	}
+}
+
+// With returns a copy of the route with the options applied
+func (r HTTPRoute) With(opts ...HTTPRouteOpt) HTTPRoute {
+	clone := r.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the route
+func (r HTTPRoute) Clone() HTTPRoute {
+	return HTTPRoute{HTTPRoute: deepCopyJSON(r.HTTPRoute)}
+}
+
+// Validate checks the metadata, the parent references, the hostnames and the rules
+func (r HTTPRoute) Validate() error {
+	errs := validateObjectMeta(&r.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateRouteSpec(r.Spec.CommonRouteSpec, r.Spec.Hostnames, path)...)
+	for i, rule := range r.Spec.Rules {
+		errs = append(errs, validateHTTPRouteRule(rule, path.Child("rules").Index(i))...)
+	}
+
+	return toAggregate(errs)
+}
+
+// HTTPRule holds a HTTP route rule
+type HTTPRule struct {
+	gatewayv1.HTTPRouteRule
+}
+
+type HTTPRuleOpt func(*HTTPRule)
+
+// HTTPMatchOpt sets a predicate of a single HTTP request match
+type HTTPMatchOpt func(*gatewayv1.HTTPRouteMatch)
+
+// NewHTTPRule returns a HTTP route rule with the given options
+func NewHTTPRule(opts ...HTTPRuleOpt) HTTPRule {
+	r := HTTPRule{}
+
+	for _, v := range opts {
+		v(&r)
+	}
+
+	return r
+}
+
+// HTTPRuleName sets the rule name
+func HTTPRuleName(name string) HTTPRuleOpt {
+	return func(r *HTTPRule) {
+		r.Name = ptrTo(name)
+	}
+}
+
+// HTTPRuleMatch adds a match built from the options. A request must satisfy all options of a match,
+// and the rule applies when any of its matches is satisfied
+func HTTPRuleMatch(opts ...HTTPMatchOpt) HTTPRuleOpt {
+	return func(r *HTTPRule) {
+		m := gatewayv1.HTTPRouteMatch{}
+		for _, v := range opts {
+			v(&m)
+		}
+		r.Matches = append(r.Matches, m)
+	}
+}
+
+// HTTPMatchPath matches the request path
+func HTTPMatchPath(t gatewayv1.PathMatchType, value string) HTTPMatchOpt {
+	return func(m *gatewayv1.HTTPRouteMatch) {
+		m.Path = &gatewayv1.HTTPPathMatch{
+			Type:  ptrTo(t),
+			Value: ptrTo(value),
+		}
+	}
+}
+
+// HTTPMatchHeader matches a request header exactly
+func HTTPMatchHeader(name, value string) HTTPMatchOpt {
+	return func(m *gatewayv1.HTTPRouteMatch) {
+		m.Headers = append(m.Headers, gatewayv1.HTTPHeaderMatch{
+			Name:  name,
+			Value: value,
+		})
+	}
+}
+
+// HTTPMatchQueryParam matches a request query parameter exactly
+func HTTPMatchQueryParam(name, value string) HTTPMatchOpt {
+	return func(m *gatewayv1.HTTPRouteMatch) {
+		m.QueryParams = append(m.QueryParams, gatewayv1.HTTPQueryParamMatch{
+			Name:  name,
+			Value: value,
+		})
+	}
+}
+
+// HTTPMatchMethod matches the request method
+func HTTPMatchMethod(method gatewayv1.HTTPMethod) HTTPMatchOpt {
+	return func(m *gatewayv1.HTTPRouteMatch) {
+		m.Method = ptrTo(method)
+	}
+}
+
+// HTTPRuleBackend forwards requests to the service port
+func HTTPRuleBackend(s Service, port int) HTTPRuleOpt {
+	return func(r *HTTPRule) {
+		r.BackendRefs = append(r.BackendRefs, gatewayv1.HTTPBackendRef{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: serviceBackendRef(s, port),
+			},
+		})
+	}
+}
+
+// HTTPRuleWeightedBackend forwards a share of the requests to the service port.
+// The share is the weight divided by the sum of the weights of the rule backends
+func HTTPRuleWeightedBackend(s Service, port, weight int) HTTPRuleOpt {
+	return func(r *HTTPRule) {
+		r.BackendRefs = append(r.BackendRefs, gatewayv1.HTTPBackendRef{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: serviceBackendRef(s, port),
+				Weight:                 ptrTo(int32(weight)),
+			},
+		})
+	}
+}
+
+// HTTPRuleFilter adds a filter to the rule
+func HTTPRuleFilter(f gatewayv1.HTTPRouteFilter) HTTPRuleOpt {
+	return func(r *HTTPRule) {
+		r.Filters = append(r.Filters, deepCopyJSON(f))
+	}
+}
+
+// HTTPRuleSetRequestHeader sets a request header before it is forwarded
+func HTTPRuleSetRequestHeader(name, value string) HTTPRuleOpt {
+	return func(r *HTTPRule) {
+		f := r.filter(gatewayv1.HTTPRouteFilterRequestHeaderModifier)
+		if f.RequestHeaderModifier == nil {
+			f.RequestHeaderModifier = &gatewayv1.HTTPHeaderFilter{}
+		}
+		f.RequestHeaderModifier.Set = append(f.RequestHeaderModifier.Set, gatewayv1.HTTPHeader{
+			Name:  name,
+			Value: value,
+		})
+	}
+}
+
+// HTTPRuleSetResponseHeader sets a response header before it is returned
+func HTTPRuleSetResponseHeader(name, value string) HTTPRuleOpt {
+	return func(r *HTTPRule) {
+		f := r.filter(gatewayv1.HTTPRouteFilterResponseHeaderModifier)
+		if f.ResponseHeaderModifier == nil {
+			f.ResponseHeaderModifier = &gatewayv1.HTTPHeaderFilter{}
+		}
+		f.ResponseHeaderModifier.Set = append(f.ResponseHeaderModifier.Set, gatewayv1.HTTPHeader{
+			Name:  name,
+			Value: value,
+		})
+	}
+}
+
+// HTTPRuleRedirect responds with a redirect to the scheme, such as https, and status code
+func HTTPRuleRedirect(scheme string, statusCode int) HTTPRuleOpt {
+	return func(r *HTTPRule) {
+		f := r.filter(gatewayv1.HTTPRouteFilterRequestRedirect)
+		f.RequestRedirect = &gatewayv1.HTTPRequestRedirectFilter{
+			Scheme:     ptrTo(scheme),
+			StatusCode: ptrTo(statusCode),
+		}
+	}
+}
+
+// HTTPRuleRewritePrefix replaces the matched path prefix before the request is forwarded
+func HTTPRuleRewritePrefix(prefix string) HTTPRuleOpt {
+	return func(r *HTTPRule) {
+		f := r.filter(gatewayv1.HTTPRouteFilterURLRewrite)
+		f.URLRewrite = &gatewayv1.HTTPURLRewriteFilter{
+			Path: &gatewayv1.HTTPPathModifier{
+				Type:               gatewayv1.PrefixMatchHTTPPathModifier,
+				ReplacePrefixMatch: ptrTo(prefix),
+			},
+		}
+	}
+}
+
+// HTTPRuleMirror mirrors requests to the service port. The responses are ignored. Each call adds a mirror, so
+// requests can be mirrored to several services
+func HTTPRuleMirror(s Service, port int) HTTPRuleOpt {
+	return func(r *HTTPRule) {
+		r.Filters = append(r.Filters, gatewayv1.HTTPRouteFilter{
+			Type: gatewayv1.HTTPRouteFilterRequestMirror,
+			RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{
+				BackendRef: serviceBackendRef(s, port),
+			},
+		})
+	}
+}
+
+// HTTPRuleTimeout sets the request timeout as a duration such as "10s"
+func HTTPRuleTimeout(d string) HTTPRuleOpt {
+	return func(r *HTTPRule) {
+		if r.Timeouts == nil {
+			r.Timeouts = &gatewayv1.HTTPRouteTimeouts{}
+		}
+		r.Timeouts.Request = ptrTo(d)
+	}
+}
+
+// filter returns the filter of the type, adding it if the rule doesn't have one yet
+func (r *HTTPRule) filter(t gatewayv1.HTTPRouteFilterType) *gatewayv1.HTTPRouteFilter {
+	for i := range r.Filters {
+		if r.Filters[i].Type == t {
+			return &r.Filters[i]
+		}
+	}
+
+	r.Filters = append(r.Filters, gatewayv1.HTTPRouteFilter{Type: t})
+	return &r.Filters[len(r.Filters)-1]
+}
+
+// Apply applies the options to the rule
+func (r *HTTPRule) Apply(opts ...HTTPRuleOpt) {
+	for _, opt := range opts {
+		opt(r)
+	}
+}
+
+// With returns a copy of the rule with the options applied
+func (r HTTPRule) With(opts ...HTTPRuleOpt) HTTPRule {
+	clone := r.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the rule
+func (r HTTPRule) Clone() HTTPRule {
+	return HTTPRule{HTTPRouteRule: deepCopyJSON(r.HTTPRouteRule)}
+}
+
+// Validate checks the matches, the filters and that the backends name a service port
+func (r HTTPRule) Validate() error {
+	return toAggregate(validateHTTPRouteRule(r.HTTPRouteRule, nil))
+}
+
+func validateHTTPRouteRule(r gatewayv1.HTTPRouteRule, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	for i, m := range r.Matches {
+		matchPath := path.Child("matches").Index(i)
+		if m.Path != nil && m.Path.Value != nil && ptrValue(m.Path.Type) != gatewayv1.PathMatchRegularExpression && !strings.HasPrefix(*m.Path.Value, "/") {
+			errs = append(errs, field.Invalid(matchPath.Child("path", "value"), *m.Path.Value, "must be an absolute path"))
+		}
+		for j, h := range m.Headers {
+			if h.Name == "" {
+				errs = append(errs, field.Required(matchPath.Child("headers").Index(j).Child("name"), ""))
+			}
+		}
+		for j, q := range m.QueryParams {
+			if q.Name == "" {
+				errs = append(errs, field.Required(matchPath.Child("queryParams").Index(j).Child("name"), ""))
+			}
+		}
+	}
+
+	for i, f := range r.Filters {
+		errs = append(errs, validateHTTPRouteFilter(f, path.Child("filters").Index(i))...)
+	}
+
+	for i, b := range r.BackendRefs {
+		backendPath := path.Child("backendRefs").Index(i)
+		errs = append(errs, validateBackendRef(b.BackendRef, backendPath)...)
+		for j, f := range b.Filters {
+			errs = append(errs, validateHTTPRouteFilter(f, backendPath.Child("filters").Index(j))...)
+		}
+	}
+
+	return errs
+}
+
+// validateHTTPRouteFilter checks that the field matching the filter type is set
+func validateHTTPRouteFilter(f gatewayv1.HTTPRouteFilter, path *field.Path) field.ErrorList {
+	set := map[gatewayv1.HTTPRouteFilterType]bool{
+		gatewayv1.HTTPRouteFilterRequestHeaderModifier:  f.RequestHeaderModifier != nil,
+		gatewayv1.HTTPRouteFilterResponseHeaderModifier: f.ResponseHeaderModifier != nil,
+		gatewayv1.HTTPRouteFilterRequestRedirect:        f.RequestRedirect != nil,
+		gatewayv1.HTTPRouteFilterURLRewrite:             f.URLRewrite != nil,
+		gatewayv1.HTTPRouteFilterRequestMirror:          f.RequestMirror != nil,
+		gatewayv1.HTTPRouteFilterExtensionRef:           f.ExtensionRef != nil,
+	}
+
+	errs := validateRouteFilterType(f.Type, set, path)
+	if f.RequestMirror != nil {
+		errs = append(errs, validateBackendObjectReference(f.RequestMirror.BackendRef, path.Child("requestMirror", "backendRef"))...)
+	}
+	if f.RequestRedirect != nil && f.RequestRedirect.StatusCode != nil {
+		if code := *f.RequestRedirect.StatusCode; code != 301 && code != 302 {
+			errs = append(errs, field.NotSupported(path.Child("requestRedirect", "statusCode"), code, []string{"301", "302"}))
+		}
+	}
+
+	return errs
+}
+
+// validateRouteFilterType checks that the filter type is set and that only the field matching it is set
+func validateRouteFilterType[T ~string](t T, set map[T]bool, path *field.Path) field.ErrorList {
+	types := make([]T, 0, len(set))
+	for k := range set {
+		types = append(types, k)
+	}
+	slices.Sort(types)
+
+	if _, ok := set[t]; !ok {
+		values := make([]string, len(types))
+		for i, k := range types {
+			values[i] = string(k)
+		}
+		return field.ErrorList{field.NotSupported(path.Child("type"), t, values)}
+	}
+
+	var errs field.ErrorList
+	for _, k := range types {
+		switch {
+		case k == t && !set[k]:
+			errs = append(errs, field.Required(path, fmt.Sprintf("the field for filter type %s must be set", t)))
+		case k != t && set[k]:
+			errs = append(errs, field.Forbidden(path, fmt.Sprintf("only the field for filter type %s can be set", t)))
+		}
+	}
+
+	return errs
+}
+
+// validateRouteSpec checks that the parent references are named and that the hostnames are valid
+func validateRouteSpec(spec gatewayv1.CommonRouteSpec, hostnames []string, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	for i, p := range spec.ParentRefs {
+		parentPath := path.Child("parentRefs").Index(i)
+		errs = append(errs, validateDNSSubdomain(p.Name, parentPath.Child("name"))...)
+		if p.Port != nil {
+			errs = append(errs, validatePort(*p.Port, parentPath.Child("port"))...)
+		}
+	}
+
+	for i, h := range hostnames {
+		errs = append(errs, validateHostname(h, path.Child("hostnames").Index(i))...)
+	}
+
+	return errs
+}
+
+// validateBackendRef checks the backend reference and that the weight is between 0 and 1000000
+func validateBackendRef(b gatewayv1.BackendRef, path *field.Path) field.ErrorList {
+	errs := validateBackendObjectReference(b.BackendObjectReference, path)
+	if b.Weight != nil && (*b.Weight < 0 || *b.Weight > 1000000) {
+		errs = append(errs, field.Invalid(path.Child("weight"), *b.Weight, "must be between 0 and 1000000"))
+	}
+
+	return errs
+}
+
+// validateBackendObjectReference checks that the backend is named and that service backends have a port
+func validateBackendObjectReference(b gatewayv1.BackendObjectReference, path *field.Path) field.ErrorList {
+	errs := validateDNSSubdomain(b.Name, path.Child("name"))
+
+	service := ptrValue(b.Group) == "" && (b.Kind == nil || *b.Kind == "Service")
+	switch {
+	case b.Port != nil:
+		errs = append(errs, validatePort(*b.Port, path.Child("port"))...)
+	case service:
+		errs = append(errs, field.Required(path.Child("port"), "required for service backends"))
+	}
+
+	return errs
+}
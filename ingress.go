@@ -16,9 +16,17 @@ package kopts
 
 import (
 	"fmt"
+	"strings"
 
 	networkingv1 "k8s.io/api/networking/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	issuerAnnotation        = "cert-manager.io/issuer"
+	clusterIssuerAnnotation = "cert-manager.io/cluster-issuer"
 )
 
 // Ingress holds a Kubernetes ingress
@@ -61,7 +69,26 @@ func IngressClass(c string) IngressOpt {
 	}
 }
 
-// Rule holds an ingress rule
+// IngressIssuer sets the cert-manager issuer for the ingress certificates. The issuer must be in the ingress namespace
+func IngressIssuer(is Issuer) IngressOpt {
+	name := is.Name
+	return func(i *Ingress) {
+		delete(i.Annotations, clusterIssuerAnnotation)
+		addAnnotation(issuerAnnotation, name, &i.ObjectMeta)
+	}
+}
+
+// IngressClusterIssuer sets the cert-manager cluster issuer for the ingress certificates
+func IngressClusterIssuer(ci ClusterIssuer) IngressOpt {
+	name := ci.Name
+	return func(i *Ingress) {
+		delete(i.Annotations, issuerAnnotation)
+		addAnnotation(clusterIssuerAnnotation, name, &i.ObjectMeta)
+	}
+}
+
+// Rule holds an ingress rule. LetsEncrypt adds TLS for the host using the letsencrypt-prod cluster issuer
+// unless another issuer is set with IngressIssuer or IngressClusterIssuer
 type Rule struct {
 	Host        string
 	Paths       []Path
@@ -81,9 +108,10 @@ type Path struct {
 func IngressRule(r Rule) IngressOpt {
 	var paths []networkingv1.HTTPIngressPath
 	for _, v := range r.Paths {
+		pathType := v.Type
 		paths = append(paths, networkingv1.HTTPIngressPath{
 			Path:     v.Name,
-			PathType: &v.Type,
+			PathType: &pathType,
 			Backend: networkingv1.IngressBackend{
 				Service: &networkingv1.IngressServiceBackend{
 					Name: v.Service,
@@ -97,7 +125,11 @@ func IngressRule(r Rule) IngressOpt {
 
 	return func(i *Ingress) {
 		if r.LetsEncrypt {
-			addAnnotation("cert-manager.io/cluster-issuer", "letsencrypt-prod", &i.ObjectMeta)
+			_, issuer := i.Annotations[issuerAnnotation]
+			_, clusterIssuer := i.Annotations[clusterIssuerAnnotation]
+			if !issuer && !clusterIssuer {
+				addAnnotation(clusterIssuerAnnotation, "letsencrypt-prod", &i.ObjectMeta)
+			}
 			i.Spec.TLS = append(i.Spec.TLS, networkingv1.IngressTLS{
 				Hosts:      []string{r.Host},
 				SecretName: fmt.Sprintf("%s-tls", i.Ingress.ObjectMeta.Name),
@@ -113,3 +145,89 @@ func IngressRule(r Rule) IngressOpt {
 		})
 	}
 }
+
+// Apply applies the options to the ingress
+func (i *Ingress) Apply(opts ...IngressOpt) {
+	for _, opt := range opts {
+		opt(i)
+	}
+}
+
+// With returns a copy of the ingress with the options applied
+func (i Ingress) With(opts ...IngressOpt) Ingress {
+	clone := i.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the ingress
+func (i Ingress) Clone() Ingress {
+	return Ingress{Ingress: *i.Ingress.DeepCopy()}
+}
+
+// Validate checks the metadata, the rule and TLS hosts, the path types and that every backend names a service and port
+func (i Ingress) Validate() error {
+	errs := validateObjectMeta(&i.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	if i.Spec.DefaultBackend != nil {
+		errs = append(errs, validateIngressBackend(*i.Spec.DefaultBackend, path.Child("defaultBackend"))...)
+	}
+
+	for j, tls := range i.Spec.TLS {
+		for k, h := range tls.Hosts {
+			errs = append(errs, validateHostname(h, path.Child("tls").Index(j).Child("hosts").Index(k))...)
+		}
+	}
+
+	for j, r := range i.Spec.Rules {
+		rulePath := path.Child("rules").Index(j)
+		if r.Host != "" {
+			errs = append(errs, validateHostname(r.Host, rulePath.Child("host"))...)
+		}
+		if r.HTTP == nil {
+			continue
+		}
+
+		for k, p := range r.HTTP.Paths {
+			pathPath := rulePath.Child("http", "paths").Index(k)
+			if p.PathType == nil {
+				errs = append(errs, field.Required(pathPath.Child("pathType"), "pathType must be specified"))
+			} else {
+				errs = append(errs, validateEnum(*p.PathType, pathPath.Child("pathType"),
+					networkingv1.PathTypeExact, networkingv1.PathTypePrefix, networkingv1.PathTypeImplementationSpecific)...)
+				if *p.PathType != networkingv1.PathTypeImplementationSpecific && !strings.HasPrefix(p.Path, "/") {
+					errs = append(errs, field.Invalid(pathPath.Child("path"), p.Path, "must be an absolute path"))
+				}
+			}
+			errs = append(errs, validateIngressBackend(p.Backend, pathPath.Child("backend"))...)
+		}
+	}
+
+	return toAggregate(errs)
+}
+
+func validateIngressBackend(b networkingv1.IngressBackend, path *field.Path) field.ErrorList {
+	switch {
+	case b.Service != nil && b.Resource != nil:
+		return field.ErrorList{field.Invalid(path, "", "cannot set both resource and service backends")}
+	case b.Resource != nil:
+		return nil
+	case b.Service == nil:
+		return field.ErrorList{field.Required(path, "one of service or resource must be set")}
+	}
+
+	errs := validateDNSLabel(b.Service.Name, path.Child("service", "name"))
+	port := b.Service.Port
+	switch {
+	case port.Name != "" && port.Number != 0:
+		errs = append(errs, field.Invalid(path.Child("service", "port"), port, "cannot set both port name and number"))
+	case port.Name != "":
+		errs = append(errs, validatePortName(port.Name, path.Child("service", "port", "name"))...)
+	default:
+		errs = append(errs, validatePort(port.Number, path.Child("service", "port", "number"))...)
+	}
+
+	return errs
+}
@@ -0,0 +1,268 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	gatewayv1 "github.com/CoverWhale/kopts/apis/gateway/v1"
+)
+
+// IngressConversion holds the Gateway API resources equivalent to an ingress
+type IngressConversion struct {
+	// HTTPRoutes holds a route for each host of the ingress rules
+	HTTPRoutes []HTTPRoute
+	// Listeners holds a HTTPS listener for each TLS host. They must be added to the gateway
+	Listeners []Listener
+	// GatewayAnnotations holds annotations that must be set on the gateway, such as the cert-manager issuer
+	GatewayAnnotations map[string]string
+	// ReferenceGrants allow the gateway to use TLS secrets in the ingress namespace
+	ReferenceGrants []ReferenceGrant
+	// UntranslatedAnnotations holds the ingress annotations without a Gateway API equivalent
+	UntranslatedAnnotations []string
+	// Warnings describes parts of the ingress that were translated with a different behavior
+	Warnings []string
+}
+
+// maxHTTPRouteRules is the maximum number of rules of a HTTP route
+const maxHTTPRouteRules = 16
+
+// ingress annotations that are handled by the conversion. The ingress class is replaced by the gateway
+var translatedIngressAnnotations = map[string]bool{
+	"kubernetes.io/ingress.class":    true,
+	"cert-manager.io/cluster-issuer": true,
+	"cert-manager.io/issuer":         true,
+	"cert-manager.io/issuer-kind":    true,
+	"cert-manager.io/issuer-group":   true,
+}
+
+// ConvertIngress converts the ingress into HTTP routes attached to the gateway and the TLS listeners the gateway needs.
+// The cert-manager issuer annotations are moved to the gateway. When the ingress names an issuer, cert-manager
+// manages the secret of every TLS entry, so those listener certificates refer to secrets in the gateway namespace
+// where cert-manager creates them. TLS entries without a secret are dropped
+func ConvertIngress(i Ingress, g Gateway) IngressConversion {
+	c := IngressConversion{
+		GatewayAnnotations: make(map[string]string),
+	}
+
+	for k, v := range i.Annotations {
+		switch {
+		case strings.HasPrefix(k, "cert-manager.io/") && translatedIngressAnnotations[k]:
+			c.GatewayAnnotations[k] = v
+		case translatedIngressAnnotations[k]:
+		default:
+			c.UntranslatedAnnotations = append(c.UntranslatedAnnotations, k)
+		}
+	}
+	sort.Strings(c.UntranslatedAnnotations)
+
+	class := i.Annotations["kubernetes.io/ingress.class"]
+	if i.Spec.IngressClassName != nil {
+		class = *i.Spec.IngressClassName
+	}
+	if class != "" {
+		c.Warnings = append(c.Warnings, fmt.Sprintf("the ingress class %s was replaced by the gateway %s, which selects the controller through its gateway class", class, g.Name))
+	}
+
+	c.convertRules(i, g)
+	c.convertTLS(i, g)
+
+	return c
+}
+
+// GatewayOpt returns an option adding the converted listeners and annotations to a gateway
+func (c IngressConversion) GatewayOpt() GatewayOpt {
+	return func(g *Gateway) {
+		for _, v := range c.Listeners {
+			GatewayListener(v)(g)
+		}
+		for k, v := range c.GatewayAnnotations {
+			addAnnotation(k, v, &g.ObjectMeta)
+		}
+	}
+}
+
+func (c *IngressConversion) convertRules(i Ingress, g Gateway) {
+	var hosts []string
+	seen := sets.New[string]()
+	rules := make(map[string][]HTTPRuleOpt)
+	for _, r := range i.Spec.Rules {
+		if !seen.Has(r.Host) {
+			seen.Insert(r.Host)
+			hosts = append(hosts, r.Host)
+		}
+		if r.HTTP == nil {
+			continue
+		}
+		for _, p := range r.HTTP.Paths {
+			rules[r.Host] = append(rules[r.Host], c.convertPath(p))
+		}
+	}
+
+	if i.Spec.DefaultBackend != nil {
+		c.Warnings = append(c.Warnings, "the default backend was converted to a route without hostnames")
+		if !seen.Has("") {
+			seen.Insert("")
+			hosts = append(hosts, "")
+		}
+		backend, ok := c.convertBackend(*i.Spec.DefaultBackend)
+		if ok {
+			rules[""] = append(rules[""], backend)
+		}
+	}
+
+	for n, host := range hosts {
+		name := i.Name
+		if len(hosts) > 1 {
+			name = fmt.Sprintf("%s-%d", i.Name, n)
+		}
+
+		opts := []HTTPRouteOpt{
+			HTTPRouteNamespace(i.Namespace),
+			HTTPRouteGateway(g),
+		}
+		if host != "" {
+			opts = append(opts, HTTPRouteHostnames(host))
+		}
+		if len(rules[host]) > maxHTTPRouteRules {
+			c.Warnings = append(c.Warnings, fmt.Sprintf("route %s has %d rules, more than the %d allowed by the Gateway API", name, len(rules[host]), maxHTTPRouteRules))
+		}
+		for _, v := range rules[host] {
+			opts = append(opts, HTTPRouteRule(NewHTTPRule(v)))
+		}
+
+		c.HTTPRoutes = append(c.HTTPRoutes, NewHTTPRoute(name, opts...))
+	}
+}
+
+// convertPath returns a rule option holding the match and backend of the path
+func (c *IngressConversion) convertPath(p networkingv1.HTTPIngressPath) HTTPRuleOpt {
+	var opts []HTTPRuleOpt
+
+	path := p.Path
+	if path == "" {
+		path = "/"
+	}
+
+	matchType := gatewayv1.PathMatchPathPrefix
+	if p.PathType != nil {
+		switch *p.PathType {
+		case networkingv1.PathTypeExact:
+			matchType = gatewayv1.PathMatchExact
+		case networkingv1.PathTypeImplementationSpecific:
+			c.Warnings = append(c.Warnings, fmt.Sprintf("path %s of type ImplementationSpecific was converted to PathPrefix", path))
+		}
+	}
+	opts = append(opts, HTTPRuleMatch(HTTPMatchPath(matchType, path)))
+
+	if backend, ok := c.convertBackend(p.Backend); ok {
+		opts = append(opts, backend)
+	}
+
+	return func(r *HTTPRule) {
+		for _, v := range opts {
+			v(r)
+		}
+	}
+}
+
+func (c *IngressConversion) convertBackend(b networkingv1.IngressBackend) (HTTPRuleOpt, bool) {
+	if b.Service == nil {
+		c.Warnings = append(c.Warnings, "resource backends can't be converted and were dropped")
+		return nil, false
+	}
+
+	if b.Service.Port.Name != "" {
+		c.Warnings = append(c.Warnings, fmt.Sprintf("backend %s refers to port %s by name, set the port number on the route", b.Service.Name, b.Service.Port.Name))
+	}
+
+	return func(r *HTTPRule) {
+		ref := gatewayv1.HTTPBackendRef{}
+		ref.Name = b.Service.Name
+		if b.Service.Port.Number != 0 {
+			ref.Port = ptrTo(b.Service.Port.Number)
+		}
+		r.BackendRefs = append(r.BackendRefs, ref)
+	}, true
+}
+
+func (c *IngressConversion) convertTLS(i Ingress, g Gateway) {
+	seen := sets.New[string]()
+	granted := sets.New[string]()
+	var grantOpts []ReferenceGrantOpt
+	for _, t := range i.Spec.TLS {
+		if len(t.Hosts) == 0 {
+			c.Warnings = append(c.Warnings, fmt.Sprintf("TLS secret %s without hosts was dropped", t.SecretName))
+			continue
+		}
+		if t.SecretName == "" {
+			c.Warnings = append(c.Warnings, fmt.Sprintf("TLS hosts %s without a secret were dropped", strings.Join(t.Hosts, ", ")))
+			continue
+		}
+
+		namespace := i.Namespace
+		if certManaged(i, t) || namespace == g.Namespace {
+			namespace = ""
+		}
+
+		for _, host := range t.Hosts {
+			if seen.Has(host) {
+				continue
+			}
+			seen.Insert(host)
+
+			if namespace != "" && !granted.Has(t.SecretName) {
+				granted.Insert(t.SecretName)
+				grantOpts = append(grantOpts, ReferenceGrantToSecret(NewSecret(t.SecretName, SecretNamespace(namespace))))
+			}
+
+			c.Listeners = append(c.Listeners, NewListener(listenerName(host), 443, gatewayv1.HTTPSProtocolType,
+				ListenerHostname(host),
+				ListenerCertificateRef(t.SecretName, namespace),
+			))
+		}
+	}
+
+	if len(grantOpts) > 0 {
+		grantOpts = append(grantOpts, ReferenceGrantFromGateway(g))
+		c.ReferenceGrants = append(c.ReferenceGrants, NewReferenceGrant(fmt.Sprintf("%s-tls", i.Name), grantOpts...))
+	}
+}
+
+// certManaged reports whether cert-manager issues the secret of the TLS entry. cert-manager creates a certificate
+// for each TLS entry with a secret when the ingress names an issuer
+func certManaged(i Ingress, t networkingv1.IngressTLS) bool {
+	_, issuer := i.Annotations[issuerAnnotation]
+	_, clusterIssuer := i.Annotations[clusterIssuerAnnotation]
+
+	return (issuer || clusterIssuer) && t.SecretName != ""
+}
+
+// listenerName returns the name of the HTTPS listener of the host. Wildcards are replaced so the name is a valid
+// DNS subdomain
+func listenerName(host string) string {
+	name := fmt.Sprintf("https-%s", strings.ReplaceAll(host, "*", "wildcard"))
+	if len(name) > validation.DNS1123SubdomainMaxLength {
+		name = name[:validation.DNS1123SubdomainMaxLength]
+	}
+
+	return strings.TrimRight(name, "-.")
+}
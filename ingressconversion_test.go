@@ -0,0 +1,354 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func ingressRule(host string, paths ...string) networkingv1.IngressRule {
+	r := networkingv1.IngressRule{Host: host}
+	if len(paths) == 0 {
+		return r
+	}
+
+	r.HTTP = &networkingv1.HTTPIngressRuleValue{}
+	for _, p := range paths {
+		r.HTTP.Paths = append(r.HTTP.Paths, networkingv1.HTTPIngressPath{
+			Path:     p,
+			PathType: ptrTo(networkingv1.PathTypePrefix),
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: "web",
+					Port: networkingv1.ServiceBackendPort{Number: 8080},
+				},
+			},
+		})
+	}
+
+	return r
+}
+
+func TestConvertIngressHosts(t *testing.T) {
+	defaultBackend := &networkingv1.IngressBackend{
+		Service: &networkingv1.IngressServiceBackend{
+			Name: "fallback",
+			Port: networkingv1.ServiceBackendPort{Number: 80},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		rules          []networkingv1.IngressRule
+		defaultBackend *networkingv1.IngressBackend
+		// routes maps the route names to their hostname and number of rules
+		routes map[string]string
+	}{
+		{
+			name:   "single host",
+			rules:  []networkingv1.IngressRule{ingressRule("a.example.com", "/", "/api")},
+			routes: map[string]string{"web": "a.example.com:2"},
+		},
+		{
+			name: "rules of a host are grouped",
+			rules: []networkingv1.IngressRule{
+				ingressRule("a.example.com", "/"),
+				ingressRule("b.example.com", "/"),
+				ingressRule("a.example.com", "/api"),
+			},
+			routes: map[string]string{"web-0": "a.example.com:2", "web-1": "b.example.com:1"},
+		},
+		{
+			name: "host without paths",
+			rules: []networkingv1.IngressRule{
+				ingressRule("a.example.com"),
+				ingressRule("a.example.com", "/"),
+			},
+			routes: map[string]string{"web": "a.example.com:1"},
+		},
+		{
+			name: "default backend",
+			rules: []networkingv1.IngressRule{
+				ingressRule(""),
+				ingressRule("a.example.com", "/"),
+			},
+			defaultBackend: defaultBackend,
+			routes:         map[string]string{"web-0": ":1", "web-1": "a.example.com:1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := NewIngress("web", IngressNamespace("apps"))
+			i.Spec.Rules = tt.rules
+			i.Spec.DefaultBackend = tt.defaultBackend
+
+			c := ConvertIngress(i, NewGateway("public", GatewayNamespace("gateways")))
+
+			routes := make(map[string]string)
+			for _, r := range c.HTTPRoutes {
+				host := ""
+				if len(r.Spec.Hostnames) > 0 {
+					host = string(r.Spec.Hostnames[0])
+				}
+				routes[r.Name] = fmt.Sprintf("%s:%d", host, len(r.Spec.Rules))
+				if r.Namespace != "apps" {
+					t.Errorf("route %s namespace = %q, want apps", r.Name, r.Namespace)
+				}
+			}
+			if !reflect.DeepEqual(routes, tt.routes) {
+				t.Errorf("routes = %v, want %v", routes, tt.routes)
+			}
+		})
+	}
+}
+
+func TestConvertIngressTLS(t *testing.T) {
+	tests := []struct {
+		name             string
+		opts             []IngressOpt
+		gatewayNamespace string
+		// listeners maps the listener names to their hostname and certificate namespace
+		listeners   map[string]string
+		grants      int
+		annotations map[string]string
+	}{
+		{
+			name: "secret in another namespace",
+			opts: []IngressOpt{
+				IngressRule(Rule{Host: "a.example.com", TLS: true, Paths: []Path{{Name: "/", Service: "web", Port: 80}}}),
+				func(i *Ingress) {
+					i.Spec.TLS = append(i.Spec.TLS, networkingv1.IngressTLS{Hosts: []string{"a.example.com"}, SecretName: "a-tls"})
+				},
+			},
+			gatewayNamespace: "gateways",
+			listeners:        map[string]string{"https-a.example.com": "a.example.com:apps"},
+			grants:           1,
+			annotations:      map[string]string{},
+		},
+		{
+			name: "secret in the gateway namespace",
+			opts: []IngressOpt{
+				func(i *Ingress) {
+					i.Spec.TLS = append(i.Spec.TLS, networkingv1.IngressTLS{Hosts: []string{"*.example.com", "*.example.com"}, SecretName: "wildcard-tls"})
+				},
+			},
+			gatewayNamespace: "apps",
+			listeners:        map[string]string{"https-wildcard.example.com": "*.example.com:"},
+			annotations:      map[string]string{},
+		},
+		{
+			name: "cert-manager",
+			opts: []IngressOpt{
+				IngressRule(Rule{Host: "a.example.com", LetsEncrypt: true, Paths: []Path{{Name: "/", Service: "web", Port: 80}}}),
+			},
+			gatewayNamespace: "gateways",
+			listeners:        map[string]string{"https-a.example.com": "a.example.com:"},
+			annotations:      map[string]string{clusterIssuerAnnotation: "letsencrypt-prod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := NewIngress("web", append([]IngressOpt{IngressNamespace("apps")}, tt.opts...)...)
+			c := ConvertIngress(i, NewGateway("public", GatewayNamespace(tt.gatewayNamespace)))
+
+			listeners := make(map[string]string)
+			for _, l := range c.Listeners {
+				namespace := ""
+				if ref := l.TLS.CertificateRefs[0]; ref.Namespace != nil {
+					namespace = string(*ref.Namespace)
+				}
+				listeners[string(l.Name)] = fmt.Sprintf("%s:%s", *l.Hostname, namespace)
+			}
+			if !reflect.DeepEqual(listeners, tt.listeners) {
+				t.Errorf("listeners = %v, want %v", listeners, tt.listeners)
+			}
+			if len(c.ReferenceGrants) != tt.grants {
+				t.Errorf("reference grants = %d, want %d", len(c.ReferenceGrants), tt.grants)
+			}
+			if !reflect.DeepEqual(c.GatewayAnnotations, tt.annotations) {
+				t.Errorf("gateway annotations = %v, want %v", c.GatewayAnnotations, tt.annotations)
+			}
+		})
+	}
+}
+
+func TestConvertIngressWarnings(t *testing.T) {
+	var paths []Path
+	for n := 0; n <= maxHTTPRouteRules; n++ {
+		paths = append(paths, Path{Name: fmt.Sprintf("/%d", n), Service: "web", Port: 80, Type: networkingv1.PathTypePrefix})
+	}
+
+	tests := []struct {
+		name         string
+		opts         []IngressOpt
+		warning      string
+		untranslated []string
+	}{
+		{
+			name:    "ingress class",
+			opts:    []IngressOpt{IngressClass("nginx")},
+			warning: "the ingress class nginx was replaced by the gateway public",
+		},
+		{
+			name: "ingress class annotation",
+			opts: []IngressOpt{func(i *Ingress) {
+				addAnnotation("kubernetes.io/ingress.class", "nginx", &i.ObjectMeta)
+			}},
+			warning: "the ingress class nginx was replaced by the gateway public",
+		},
+		{
+			name:    "too many rules",
+			opts:    []IngressOpt{IngressRule(Rule{Host: "a.example.com", Paths: paths})},
+			warning: "route web has 17 rules",
+		},
+		{
+			name:    "implementation specific path",
+			opts:    []IngressOpt{IngressRule(Rule{Host: "a.example.com", Paths: []Path{{Name: "/", Service: "web", Port: 80, Type: networkingv1.PathTypeImplementationSpecific}}})},
+			warning: "path / of type ImplementationSpecific was converted to PathPrefix",
+		},
+		{
+			name: "untranslated annotations",
+			opts: []IngressOpt{func(i *Ingress) {
+				addAnnotation("nginx.ingress.kubernetes.io/rewrite-target", "/", &i.ObjectMeta)
+				addAnnotation("nginx.ingress.kubernetes.io/affinity", "cookie", &i.ObjectMeta)
+			}},
+			untranslated: []string{"nginx.ingress.kubernetes.io/affinity", "nginx.ingress.kubernetes.io/rewrite-target"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := ConvertIngress(NewIngress("web", tt.opts...), NewGateway("public"))
+
+			if tt.warning != "" {
+				found := false
+				for _, w := range c.Warnings {
+					found = found || strings.Contains(w, tt.warning)
+				}
+				if !found {
+					t.Errorf("warnings = %q, want %q", c.Warnings, tt.warning)
+				}
+			}
+			if !reflect.DeepEqual(c.UntranslatedAnnotations, tt.untranslated) {
+				t.Errorf("untranslated annotations = %v, want %v", c.UntranslatedAnnotations, tt.untranslated)
+			}
+		})
+	}
+}
+
+func TestConvertIngressGatewayValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		tls  []networkingv1.IngressTLS
+		// listeners holds the expected listener names
+		listeners []string
+		// grants holds the expected number of secrets of the reference grant
+		grants int
+	}{
+		{
+			name:      "dotted hosts",
+			tls:       []networkingv1.IngressTLS{{Hosts: []string{"a.example.com", "*.b.example.com"}, SecretName: "web-tls"}},
+			listeners: []string{"https-a.example.com", "https-wildcard.b.example.com"},
+			grants:    1,
+		},
+		{
+			name: "secret shared by entries",
+			tls: []networkingv1.IngressTLS{
+				{Hosts: []string{"a.example.com"}, SecretName: "web-tls"},
+				{Hosts: []string{"b.example.com"}, SecretName: "web-tls"},
+				{Hosts: []string{"c.example.com"}, SecretName: "c-tls"},
+			},
+			listeners: []string{"https-a.example.com", "https-b.example.com", "https-c.example.com"},
+			grants:    2,
+		},
+		{
+			name: "entry without a secret",
+			tls: []networkingv1.IngressTLS{
+				{Hosts: []string{"a.example.com"}},
+				{Hosts: []string{"b.example.com"}, SecretName: "b-tls"},
+			},
+			listeners: []string{"https-b.example.com"},
+			grants:    1,
+		},
+		{
+			name:      "long host",
+			tls:       []networkingv1.IngressTLS{{Hosts: []string{strings.Repeat("a.", 120) + "example.com"}, SecretName: "web-tls"}},
+			listeners: []string{"https-" + strings.Repeat("a.", 120) + "example"},
+			grants:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := NewIngress("web", IngressNamespace("apps"))
+			i.Spec.TLS = tt.tls
+
+			c := ConvertIngress(i, NewGateway("public", GatewayNamespace("gateways")))
+			g := NewGateway("public", GatewayNamespace("gateways"), GatewayClassName("istio"), c.GatewayOpt())
+			if err := g.Validate(); err != nil {
+				t.Fatalf("Validate() = %v", err)
+			}
+
+			var listeners []string
+			for _, l := range g.Spec.Listeners {
+				listeners = append(listeners, string(l.Name))
+			}
+			if !reflect.DeepEqual(listeners, tt.listeners) {
+				t.Errorf("listeners = %v, want %v", listeners, tt.listeners)
+			}
+
+			if len(c.ReferenceGrants) != 1 {
+				t.Fatalf("reference grants = %d, want 1", len(c.ReferenceGrants))
+			}
+			rg := c.ReferenceGrants[0]
+			if len(rg.Spec.To) != tt.grants {
+				t.Errorf("reference grant secrets = %d, want %d", len(rg.Spec.To), tt.grants)
+			}
+			if err := rg.Validate(); err != nil {
+				t.Errorf("reference grant Validate() = %v", err)
+			}
+		})
+	}
+}
+
+func TestConvertIngressCertManagedEntries(t *testing.T) {
+	i := NewIngress("web", IngressNamespace("apps"), IngressClusterIssuer(NewClusterIssuer("letsencrypt-prod")))
+	i.Spec.TLS = []networkingv1.IngressTLS{
+		{Hosts: []string{"a.example.com"}, SecretName: "a-tls"},
+		{Hosts: []string{"b.example.com"}},
+	}
+
+	c := ConvertIngress(i, NewGateway("public", GatewayNamespace("gateways")))
+
+	if len(c.Listeners) != 1 {
+		t.Fatalf("listeners = %d, want 1", len(c.Listeners))
+	}
+	if ref := c.Listeners[0].TLS.CertificateRefs[0]; ref.Namespace != nil {
+		t.Errorf("certificate namespace = %q, want the gateway namespace", *ref.Namespace)
+	}
+	if len(c.ReferenceGrants) != 0 {
+		t.Errorf("reference grants = %d, want 0", len(c.ReferenceGrants))
+	}
+	if len(c.Warnings) != 1 || !strings.Contains(c.Warnings[0], "b.example.com") {
+		t.Errorf("warnings = %q, want the dropped entry", c.Warnings)
+	}
+}
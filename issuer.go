@@ -0,0 +1,374 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"net/url"
+	"slices"
+
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	certmanagerv1 "github.com/CoverWhale/kopts/apis/certmanager/v1"
+	gatewayv1 "github.com/CoverWhale/kopts/apis/gateway/v1"
+)
+
+// Let's Encrypt ACME directory URLs. The staging server has much higher rate limits but issues untrusted certificates
+const (
+	LetsEncryptProduction = "https://acme-v02.api.letsencrypt.org/directory"
+	LetsEncryptStaging    = "https://acme-staging-v02.api.letsencrypt.org/directory"
+)
+
+// Issuer holds a cert-manager issuer
+type Issuer struct {
+	certmanagerv1.Issuer
+}
+
+type IssuerOpt func(*Issuer)
+
+// IssuerSpecOpt sets a field of an issuer spec. The same options can be used for an Issuer
+// with IssuerSpec and for a ClusterIssuer with ClusterIssuerSpec
+type IssuerSpecOpt func(*certmanagerv1.IssuerSpec)
+
+// NewIssuer returns an issuer with the given name and options
+func NewIssuer(name string, opts ...IssuerOpt) Issuer {
+	i := Issuer{
+		Issuer: certmanagerv1.Issuer{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Issuer",
+				APIVersion: certmanagerv1.APIVersion,
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&i)
+	}
+
+	return i
+}
+
+// IssuerNamespace sets the namespace for the issuer
+func IssuerNamespace(n string) IssuerOpt {
+	return func(i *Issuer) {
+		setNamespace(n, &i.ObjectMeta)
+	}
+}
+
+// IssuerSpec applies the issuer spec options to the issuer
+func IssuerSpec(opts ...IssuerSpecOpt) IssuerOpt {
+	return func(i *Issuer) {
+		for _, v := range opts {
+			v(&i.Spec)
+		}
+	}
+}
+
+// Apply applies the options to the issuer
+func (i *Issuer) Apply(opts ...IssuerOpt) {
+	for _, opt := range opts {
+		opt(i)
+	}
+}
+
+// With returns a copy of the issuer with the options applied
+func (i Issuer) With(opts ...IssuerOpt) Issuer {
+	clone := i.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the issuer
+func (i Issuer) Clone() Issuer {
+	return Issuer{Issuer: deepCopyJSON(i.Issuer)}
+}
+
+// Validate checks the metadata and that exactly one issuer type is configured with its required fields
+func (i Issuer) Validate() error {
+	errs := validateObjectMeta(&i.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	return toAggregate(append(errs, validateIssuerSpec(i.Spec, field.NewPath("spec"))...))
+}
+
+// ClusterIssuer holds a cert-manager cluster issuer
+type ClusterIssuer struct {
+	certmanagerv1.ClusterIssuer
+}
+
+type ClusterIssuerOpt func(*ClusterIssuer)
+
+// NewClusterIssuer returns a cluster issuer with the given name and options
+func NewClusterIssuer(name string, opts ...ClusterIssuerOpt) ClusterIssuer {
+	ci := ClusterIssuer{
+		ClusterIssuer: certmanagerv1.ClusterIssuer{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ClusterIssuer",
+				APIVersion: certmanagerv1.APIVersion,
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&ci)
+	}
+
+	return ci
+}
+
+// ClusterIssuerSpec applies the issuer spec options to the cluster issuer
+func ClusterIssuerSpec(opts ...IssuerSpecOpt) ClusterIssuerOpt {
+	return func(ci *ClusterIssuer) {
+		for _, v := range opts {
+			v(&ci.Spec)
+		}
+	}
+}
+
+// IssuerSpecACME makes the issuer an ACME issuer for the server. The ACME account key is stored in the
+// privateKeySecret secret, which is created on first use. It clears any other issuer type
+func IssuerSpecACME(server, email, privateKeySecret string) IssuerSpecOpt {
+	return func(s *certmanagerv1.IssuerSpec) {
+		acme := s.ACME
+		if acme == nil {
+			acme = &certmanagerv1.ACMEIssuer{}
+		}
+
+		acme.Email = email
+		acme.Server = server
+		acme.PrivateKey = certmanagerv1.SecretKeySelector{
+			Name: privateKeySecret,
+		}
+		*s = certmanagerv1.IssuerSpec{
+			ACME: acme,
+		}
+	}
+}
+
+// IssuerSpecLetsEncrypt makes the issuer an ACME issuer for the Let's Encrypt production server
+func IssuerSpecLetsEncrypt(email, privateKeySecret string) IssuerSpecOpt {
+	return IssuerSpecACME(LetsEncryptProduction, email, privateKeySecret)
+}
+
+// IssuerSpecLetsEncryptStaging makes the issuer an ACME issuer for the Let's Encrypt staging server
+func IssuerSpecLetsEncryptStaging(email, privateKeySecret string) IssuerSpecOpt {
+	return IssuerSpecACME(LetsEncryptStaging, email, privateKeySecret)
+}
+
+// IssuerSpecPreferredChain sets the preferred ACME certificate chain by the common name of its root
+func IssuerSpecPreferredChain(chain string) IssuerSpecOpt {
+	return func(s *certmanagerv1.IssuerSpec) {
+		if s.ACME == nil {
+			s.ACME = &certmanagerv1.ACMEIssuer{}
+		}
+		s.ACME.PreferredChain = chain
+	}
+}
+
+// IssuerSpecSolver adds an ACME challenge solver. When DNS zones are given the solver is only used for
+// names in those zones, otherwise it is used for all names
+func IssuerSpecSolver(solver certmanagerv1.ACMEChallengeSolver, dnsZones ...string) IssuerSpecOpt {
+	solver = deepCopyJSON(solver)
+	dnsZones = slices.Clone(dnsZones)
+	return func(s *certmanagerv1.IssuerSpec) {
+		solver := deepCopyJSON(solver)
+		if len(dnsZones) > 0 {
+			solver.Selector = &certmanagerv1.CertificateDNSNameSelector{
+				DNSZones: slices.Clone(dnsZones),
+			}
+		}
+
+		if s.ACME == nil {
+			s.ACME = &certmanagerv1.ACMEIssuer{}
+		}
+		s.ACME.Solvers = append(s.ACME.Solvers, solver)
+	}
+}
+
+// IssuerSpecCA makes the issuer a CA issuer signing with the key pair in the secret. It clears any other issuer type
+func IssuerSpecCA(secretName string) IssuerSpecOpt {
+	return func(s *certmanagerv1.IssuerSpec) {
+		*s = certmanagerv1.IssuerSpec{
+			CA: &certmanagerv1.CAIssuer{
+				SecretName: secretName,
+			},
+		}
+	}
+}
+
+// IssuerSpecSelfSigned makes the issuer a self-signed issuer. It clears any other issuer type
+func IssuerSpecSelfSigned() IssuerSpecOpt {
+	return func(s *certmanagerv1.IssuerSpec) {
+		*s = certmanagerv1.IssuerSpec{
+			SelfSigned: &certmanagerv1.SelfSignedIssuer{},
+		}
+	}
+}
+
+// ACMESolverHTTP01Ingress returns a solver answering HTTP01 challenges through an ingress of the ingress class
+func ACMESolverHTTP01Ingress(class string) certmanagerv1.ACMEChallengeSolver {
+	return certmanagerv1.ACMEChallengeSolver{
+		HTTP01: &certmanagerv1.ACMEChallengeSolverHTTP01{
+			Ingress: &certmanagerv1.ACMEChallengeSolverHTTP01Ingress{
+				IngressClassName: &class,
+			},
+		},
+	}
+}
+
+// ACMESolverHTTP01Gateway returns a solver answering HTTP01 challenges through an HTTP route attached to the
+// gateway listener. An empty listener attaches the route to all listeners
+func ACMESolverHTTP01Gateway(g Gateway, listener string) certmanagerv1.ACMEChallengeSolver {
+	return certmanagerv1.ACMEChallengeSolver{
+		HTTP01: &certmanagerv1.ACMEChallengeSolverHTTP01{
+			GatewayHTTPRoute: &certmanagerv1.ACMEChallengeSolverHTTP01GatewayHTTPRoute{
+				ParentRefs: []gatewayv1.ParentReference{gatewayParentRef(g, listener)},
+			},
+		},
+	}
+}
+
+// ACMESolverDNS01Cloudflare returns a solver answering DNS01 challenges with the Cloudflare API token in the secret key
+func ACMESolverDNS01Cloudflare(tokenSecret, key string) certmanagerv1.ACMEChallengeSolver {
+	return certmanagerv1.ACMEChallengeSolver{
+		DNS01: &certmanagerv1.ACMEChallengeSolverDNS01{
+			Cloudflare: &certmanagerv1.ACMEIssuerDNS01ProviderCloudflare{
+				APIToken: &certmanagerv1.SecretKeySelector{
+					Name: tokenSecret,
+					Key:  key,
+				},
+			},
+		},
+	}
+}
+
+// ACMESolverDNS01Route53 returns a solver answering DNS01 challenges with AWS Route53 using the ambient credentials.
+// The hosted zone ID can be empty to look it up from the name
+func ACMESolverDNS01Route53(region, hostedZoneID string) certmanagerv1.ACMEChallengeSolver {
+	return certmanagerv1.ACMEChallengeSolver{
+		DNS01: &certmanagerv1.ACMEChallengeSolverDNS01{
+			Route53: &certmanagerv1.ACMEIssuerDNS01ProviderRoute53{
+				Region:       region,
+				HostedZoneID: hostedZoneID,
+			},
+		},
+	}
+}
+
+// ACMESolverDNS01CloudDNS returns a solver answering DNS01 challenges with Google Cloud DNS in the project
+// using the ambient credentials
+func ACMESolverDNS01CloudDNS(project string) certmanagerv1.ACMEChallengeSolver {
+	return certmanagerv1.ACMEChallengeSolver{
+		DNS01: &certmanagerv1.ACMEChallengeSolverDNS01{
+			CloudDNS: &certmanagerv1.ACMEIssuerDNS01ProviderCloudDNS{
+				Project: project,
+			},
+		},
+	}
+}
+
+// ACMESolverDNS01DigitalOcean returns a solver answering DNS01 challenges with the DigitalOcean token in the secret key
+func ACMESolverDNS01DigitalOcean(tokenSecret, key string) certmanagerv1.ACMEChallengeSolver {
+	return certmanagerv1.ACMEChallengeSolver{
+		DNS01: &certmanagerv1.ACMEChallengeSolverDNS01{
+			DigitalOcean: &certmanagerv1.ACMEIssuerDNS01ProviderDigitalOcean{
+				Token: certmanagerv1.SecretKeySelector{
+					Name: tokenSecret,
+					Key:  key,
+				},
+			},
+		},
+	}
+}
+
+// Apply applies the options to the cluster issuer
+func (ci *ClusterIssuer) Apply(opts ...ClusterIssuerOpt) {
+	for _, opt := range opts {
+		opt(ci)
+	}
+}
+
+// With returns a copy of the cluster issuer with the options applied
+func (ci ClusterIssuer) With(opts ...ClusterIssuerOpt) ClusterIssuer {
+	clone := ci.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the cluster issuer
+func (ci ClusterIssuer) Clone() ClusterIssuer {
+	return ClusterIssuer{ClusterIssuer: deepCopyJSON(ci.ClusterIssuer)}
+}
+
+// Validate checks the metadata and that exactly one issuer type is configured with its required fields
+func (ci ClusterIssuer) Validate() error {
+	errs := validateObjectMeta(&ci.ObjectMeta, false, apimachineryvalidation.NameIsDNSSubdomain)
+	return toAggregate(append(errs, validateIssuerSpec(ci.Spec, field.NewPath("spec"))...))
+}
+
+func validateIssuerSpec(s certmanagerv1.IssuerSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, validateOneOf(path, map[string]bool{
+		"acme":       s.ACME != nil,
+		"ca":         s.CA != nil,
+		"selfSigned": s.SelfSigned != nil,
+	})...)
+
+	if s.CA != nil {
+		errs = append(errs, validateDNSSubdomain(s.CA.SecretName, path.Child("ca", "secretName"))...)
+	}
+
+	if s.ACME == nil {
+		return errs
+	}
+
+	acmePath := path.Child("acme")
+	if u, err := url.Parse(s.ACME.Server); s.ACME.Server == "" || err != nil || u.Scheme != "https" {
+		errs = append(errs, field.Invalid(acmePath.Child("server"), s.ACME.Server, "must be a https URL"))
+	}
+	errs = append(errs, validateDNSSubdomain(s.ACME.PrivateKey.Name, acmePath.Child("privateKeySecretRef", "name"))...)
+
+	for i, solver := range s.ACME.Solvers {
+		solverPath := acmePath.Child("solvers").Index(i)
+		errs = append(errs, validateOneOf(solverPath, map[string]bool{
+			"http01": solver.HTTP01 != nil,
+			"dns01":  solver.DNS01 != nil,
+		})...)
+
+		if solver.HTTP01 != nil {
+			errs = append(errs, validateOneOf(solverPath.Child("http01"), map[string]bool{
+				"ingress":          solver.HTTP01.Ingress != nil,
+				"gatewayHTTPRoute": solver.HTTP01.GatewayHTTPRoute != nil,
+			})...)
+		}
+
+		if dns := solver.DNS01; dns != nil {
+			errs = append(errs, validateOneOf(solverPath.Child("dns01"), map[string]bool{
+				"cloudDNS":     dns.CloudDNS != nil,
+				"cloudflare":   dns.Cloudflare != nil,
+				"route53":      dns.Route53 != nil,
+				"digitalocean": dns.DigitalOcean != nil,
+			})...)
+			errs = append(errs, validateEnum(dns.CNAMEStrategy, solverPath.Child("dns01", "cnameStrategy"),
+				certmanagerv1.NoneStrategy, certmanagerv1.FollowStrategy)...)
+		}
+	}
+
+	return errs
+}
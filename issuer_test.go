@@ -0,0 +1,178 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	certmanagerv1 "github.com/CoverWhale/kopts/apis/certmanager/v1"
+)
+
+func TestIssuerSpecACME(t *testing.T) {
+	zones := []string{"example.com"}
+	opt := IssuerSpecSolver(ACMESolverDNS01Cloudflare("cloudflare", "api-token"), zones...)
+	staging := NewClusterIssuer("letsencrypt-staging", ClusterIssuerSpec(
+		IssuerSpecSelfSigned(),
+		IssuerSpecLetsEncryptStaging("ops@example.com", "letsencrypt-staging-account"),
+		opt,
+		IssuerSpecSolver(ACMESolverHTTP01Ingress("nginx")),
+	))
+	zones[0] = "example.org"
+
+	if staging.Spec.SelfSigned != nil {
+		t.Errorf("selfSigned = %+v, want it cleared by the ACME issuer", staging.Spec.SelfSigned)
+	}
+	acme := staging.Spec.ACME
+	if acme.Server != LetsEncryptStaging {
+		t.Errorf("server = %q, want %q", acme.Server, LetsEncryptStaging)
+	}
+	if len(acme.Solvers) != 2 {
+		t.Fatalf("solvers = %d, want 2", len(acme.Solvers))
+	}
+	if got := acme.Solvers[0].Selector.DNSZones; !reflect.DeepEqual(got, []string{"example.com"}) {
+		t.Errorf("DNS zones = %v after changing the caller's slice, want [example.com]", got)
+	}
+	if acme.Solvers[1].Selector != nil {
+		t.Errorf("selector = %+v, want the HTTP01 solver used for all names", acme.Solvers[1].Selector)
+	}
+	if err := staging.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	acme.Solvers[0].DNS01.Cloudflare.APIToken.Name = "changed"
+	acme.Solvers[0].Selector.DNSZones[0] = "changed"
+	prod := NewIssuer("letsencrypt", IssuerNamespace("apps"), IssuerSpec(IssuerSpecLetsEncrypt("ops@example.com", "letsencrypt-account"), opt))
+	solver := prod.Spec.ACME.Solvers[0]
+	if solver.DNS01.Cloudflare.APIToken.Name != "cloudflare" || solver.Selector.DNSZones[0] != "example.com" {
+		t.Errorf("solver = %+v for a second issuer, want the original token secret and zone", solver)
+	}
+	if prod.Spec.ACME.Server != LetsEncryptProduction {
+		t.Errorf("server = %q, want %q", prod.Spec.ACME.Server, LetsEncryptProduction)
+	}
+}
+
+func TestIssuerSpecTypes(t *testing.T) {
+	ca := NewIssuer("internal-ca", IssuerNamespace("pki"), IssuerSpec(IssuerSpecLetsEncrypt("ops@example.com", "account"), IssuerSpecCA("ca-key-pair")))
+	if ca.Spec.ACME != nil || ca.Spec.CA == nil || ca.Spec.CA.SecretName != "ca-key-pair" {
+		t.Errorf("spec = %+v, want only the CA issuer", ca.Spec)
+	}
+	if err := ca.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	selfSigned := NewClusterIssuer("self-signed", ClusterIssuerSpec(IssuerSpecCA("ca-key-pair"), IssuerSpecSelfSigned()))
+	if selfSigned.Spec.CA != nil || selfSigned.Spec.SelfSigned == nil {
+		t.Errorf("spec = %+v, want only the self-signed issuer", selfSigned.Spec)
+	}
+	if err := selfSigned.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestIssuerValidate(t *testing.T) {
+	twoProviders := ACMESolverDNS01Route53("us-east-1", "")
+	twoProviders.DNS01.CloudDNS = &certmanagerv1.ACMEIssuerDNS01ProviderCloudDNS{Project: "dns"}
+
+	tests := []struct {
+		name   string
+		object Validator
+		fields []string
+	}{
+		{
+			name:   "without type",
+			object: NewIssuer("empty", IssuerNamespace("apps")),
+			fields: []string{"spec"},
+		},
+		{
+			name:   "cluster issuer with namespace",
+			object: NewClusterIssuer("internal-ca", ClusterIssuerSpec(IssuerSpecCA("ca-key-pair")), func(ci *ClusterIssuer) { ci.Namespace = "pki" }),
+			fields: []string{"metadata.namespace"},
+		},
+		{
+			name:   "http ACME server",
+			object: NewClusterIssuer("acme", ClusterIssuerSpec(IssuerSpecACME("http://acme.example.com/directory", "ops@example.com", "account"))),
+			fields: []string{"spec.acme.server"},
+		},
+		{
+			name:   "ACME without account secret",
+			object: NewClusterIssuer("acme", ClusterIssuerSpec(IssuerSpecLetsEncrypt("ops@example.com", ""))),
+			fields: []string{"spec.acme.privateKeySecretRef.name"},
+		},
+		{
+			name: "solver with two DNS providers",
+			object: NewClusterIssuer("acme", ClusterIssuerSpec(
+				IssuerSpecLetsEncrypt("ops@example.com", "account"),
+				IssuerSpecSolver(twoProviders),
+			)),
+			fields: []string{"spec.acme.solvers[0].dns01"},
+		},
+		{
+			name: "solver without challenge",
+			object: NewClusterIssuer("acme", ClusterIssuerSpec(
+				IssuerSpecLetsEncrypt("ops@example.com", "account"),
+				IssuerSpecSolver(certmanagerv1.ACMEChallengeSolver{}),
+			)),
+			fields: []string{"spec.acme.solvers[0]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, tt.object.Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestIngressIssuer(t *testing.T) {
+	rule := IngressRule(Rule{Host: "a.example.com", LetsEncrypt: true, Paths: []Path{{Name: "/", Service: "web", Port: 80}}})
+
+	tests := []struct {
+		name        string
+		opts        []IngressOpt
+		annotations map[string]string
+	}{
+		{
+			name:        "default issuer",
+			opts:        []IngressOpt{rule},
+			annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt-prod"},
+		},
+		{
+			name:        "staging cluster issuer",
+			opts:        []IngressOpt{IngressClusterIssuer(NewClusterIssuer("letsencrypt-staging")), rule},
+			annotations: map[string]string{clusterIssuerAnnotation: "letsencrypt-staging"},
+		},
+		{
+			name:        "namespaced issuer replaces the cluster issuer",
+			opts:        []IngressOpt{IngressClusterIssuer(NewClusterIssuer("letsencrypt-prod")), IngressIssuer(NewIssuer("internal-ca")), rule},
+			annotations: map[string]string{issuerAnnotation: "internal-ca"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := NewIngress("web", tt.opts...)
+			if !reflect.DeepEqual(i.Annotations, tt.annotations) {
+				t.Errorf("annotations = %v, want %v", i.Annotations, tt.annotations)
+			}
+			if len(i.Spec.TLS) != 1 || i.Spec.TLS[0].SecretName != "web-tls" {
+				t.Errorf("tls = %+v, want the web-tls secret", i.Spec.TLS)
+			}
+		})
+	}
+}
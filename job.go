@@ -0,0 +1,261 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Job is a Kubernetes job
+type Job struct {
+	batchv1.Job
+}
+
+type JobOpt func(*Job)
+
+// JobSpecOpt sets a field of a job spec. The same options can be used for a Job
+// with JobSpec and for the job template of a CronJob with CronJobJobSpec
+type JobSpecOpt func(*batchv1.JobSpec)
+
+// NewJob returns a job with the given name and options
+func NewJob(name string, opts ...JobOpt) *Job {
+	j := &Job{
+		Job: batchv1.Job{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Job",
+				APIVersion: "batch/v1",
+			},
+			ObjectMeta: newObjectMeta(name),
+			Spec:       batchv1.JobSpec{},
+		},
+	}
+
+	for _, v := range opts {
+		v(j)
+	}
+
+	return j
+}
+
+// JobNamespace sets the namespace for the job
+func JobNamespace(n string) JobOpt {
+	return func(j *Job) {
+		setNamespace(n, &j.ObjectMeta)
+	}
+}
+
+// JobSpec applies the job spec options to the job
+func JobSpec(opts ...JobSpecOpt) JobOpt {
+	return func(j *Job) {
+		for _, v := range opts {
+			v(&j.Spec)
+		}
+	}
+}
+
+// JobSpecPodSpec sets the pod template for the job
+func JobSpecPodSpec(p PodSpec) JobSpecOpt {
+	return func(js *batchv1.JobSpec) {
+		js.Template = p.template(js.Template)
+	}
+}
+
+// JobSpecRestartPolicy sets the restart policy for the job pods
+func JobSpecRestartPolicy(r corev1.RestartPolicy) JobSpecOpt {
+	return func(js *batchv1.JobSpec) {
+		js.Template.Spec.RestartPolicy = r
+	}
+}
+
+// JobSpecParallelism sets the parallelism for the job
+func JobSpecParallelism(i int) JobSpecOpt {
+	return func(js *batchv1.JobSpec) {
+		parallel := int32(i)
+		js.Parallelism = &parallel
+	}
+}
+
+// JobSpecCompletions sets the completions for the job
+func JobSpecCompletions(i int) JobSpecOpt {
+	return func(js *batchv1.JobSpec) {
+		completions := int32(i)
+		js.Completions = &completions
+	}
+}
+
+// JobSpecActiveDeadlineSeconds sets the active deadline seconds for the job
+func JobSpecActiveDeadlineSeconds(i int) JobSpecOpt {
+	return func(js *batchv1.JobSpec) {
+		seconds := int64(i)
+		js.ActiveDeadlineSeconds = &seconds
+	}
+}
+
+// JobSpecBackoffLimit sets the backoff limit for the job
+func JobSpecBackoffLimit(i int) JobSpecOpt {
+	return func(js *batchv1.JobSpec) {
+		limit := int32(i)
+		js.BackoffLimit = &limit
+	}
+}
+
+// JobSpecTTLSecondsAfterFinished sets how long a finished job is kept before it is deleted
+func JobSpecTTLSecondsAfterFinished(i int) JobSpecOpt {
+	return func(js *batchv1.JobSpec) {
+		seconds := int32(i)
+		js.TTLSecondsAfterFinished = &seconds
+	}
+}
+
+// JobSpecCompletionMode sets the completion mode for the job
+func JobSpecCompletionMode(m batchv1.CompletionMode) JobSpecOpt {
+	return func(js *batchv1.JobSpec) {
+		js.CompletionMode = ptrTo(m)
+	}
+}
+
+// JobSpecIndexed sets the indexed completion mode for the job
+func JobSpecIndexed() JobSpecOpt {
+	return JobSpecCompletionMode(batchv1.IndexedCompletion)
+}
+
+// JobSpecBackoffLimitPerIndex sets the backoff limit for each index. It requires the indexed completion mode
+func JobSpecBackoffLimitPerIndex(i int) JobSpecOpt {
+	return func(js *batchv1.JobSpec) {
+		limit := int32(i)
+		js.BackoffLimitPerIndex = &limit
+	}
+}
+
+// JobSpecMaxFailedIndexes sets the number of failed indexes after which the job is failed.
+// It requires the backoff limit per index to be set
+func JobSpecMaxFailedIndexes(i int) JobSpecOpt {
+	return func(js *batchv1.JobSpec) {
+		indexes := int32(i)
+		js.MaxFailedIndexes = &indexes
+	}
+}
+
+// JobSpecSuspend sets whether the job is suspended
+func JobSpecSuspend(b bool) JobSpecOpt {
+	return func(js *batchv1.JobSpec) {
+		js.Suspend = ptrTo(b)
+	}
+}
+
+// JobSpecPodFailurePolicyRule adds a pod failure policy rule. Pod failure policies require the restart policy to be Never
+func JobSpecPodFailurePolicyRule(r batchv1.PodFailurePolicyRule) JobSpecOpt {
+	return func(js *batchv1.JobSpec) {
+		if js.PodFailurePolicy == nil {
+			js.PodFailurePolicy = &batchv1.PodFailurePolicy{}
+		}
+		js.PodFailurePolicy.Rules = append(js.PodFailurePolicy.Rules, *r.DeepCopy())
+	}
+}
+
+// JobSpecOnExitCodes adds a pod failure policy rule that takes the action when a container exits with
+// one of the codes. An empty container name matches all containers
+func JobSpecOnExitCodes(action batchv1.PodFailurePolicyAction, container string, operator batchv1.PodFailurePolicyOnExitCodesOperator, codes ...int) JobSpecOpt {
+	var values []int32
+	for _, v := range codes {
+		values = append(values, int32(v))
+	}
+
+	r := batchv1.PodFailurePolicyRule{
+		Action: action,
+		OnExitCodes: &batchv1.PodFailurePolicyOnExitCodesRequirement{
+			Operator: operator,
+			Values:   values,
+		},
+	}
+
+	if container != "" {
+		r.OnExitCodes.ContainerName = &container
+	}
+
+	return JobSpecPodFailurePolicyRule(r)
+}
+
+// JobSpecOnPodCondition adds a pod failure policy rule that takes the action when a failed pod has the condition.
+// Use corev1.DisruptionTarget to ignore failures caused by disruptions
+func JobSpecOnPodCondition(action batchv1.PodFailurePolicyAction, condition corev1.PodConditionType) JobSpecOpt {
+	return JobSpecPodFailurePolicyRule(batchv1.PodFailurePolicyRule{
+		Action: action,
+		OnPodConditions: []batchv1.PodFailurePolicyOnPodConditionsPattern{
+			{
+				Type:   condition,
+				Status: corev1.ConditionTrue,
+			},
+		},
+	})
+}
+
+// Apply applies the options to the job
+func (j *Job) Apply(opts ...JobOpt) {
+	for _, opt := range opts {
+		opt(j)
+	}
+}
+
+// With returns a copy of the job with the options applied
+func (j *Job) With(opts ...JobOpt) *Job {
+	clone := j.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the job
+func (j *Job) Clone() *Job {
+	return &Job{Job: *j.Job.DeepCopy()}
+}
+
+// Validate checks the metadata and the job spec. The pod template must have a restart policy of OnFailure or Never,
+// and Never when a pod failure policy is set
+func (j Job) Validate() error {
+	errs := validateObjectMeta(&j.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	return toAggregate(append(errs, validateJobSpec(j.Spec, field.NewPath("spec"))...))
+}
+
+func validateJobSpec(js batchv1.JobSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, validateNonNegative(js.Parallelism, path.Child("parallelism"))...)
+	errs = append(errs, validateNonNegative(js.Completions, path.Child("completions"))...)
+	errs = append(errs, validateNonNegative(js.BackoffLimit, path.Child("backoffLimit"))...)
+	errs = append(errs, validateNonNegative(js.TTLSecondsAfterFinished, path.Child("ttlSecondsAfterFinished"))...)
+	errs = append(errs, validateEnum(ptrValue(js.CompletionMode), path.Child("completionMode"),
+		batchv1.NonIndexedCompletion, batchv1.IndexedCompletion)...)
+
+	if js.BackoffLimitPerIndex != nil && ptrValue(js.CompletionMode) != batchv1.IndexedCompletion {
+		errs = append(errs, field.Invalid(path.Child("backoffLimitPerIndex"), *js.BackoffLimitPerIndex, "requires the Indexed completion mode"))
+	}
+	if js.MaxFailedIndexes != nil && js.BackoffLimitPerIndex == nil {
+		errs = append(errs, field.Invalid(path.Child("maxFailedIndexes"), *js.MaxFailedIndexes, "requires backoffLimitPerIndex"))
+	}
+	if r := js.Template.Spec.RestartPolicy; js.PodFailurePolicy != nil && r != "" && r != corev1.RestartPolicyNever {
+		errs = append(errs, field.Invalid(path.Child("template", "spec", "restartPolicy"), r, "must be Never when a pod failure policy is set"))
+	}
+
+	if js.Template.Spec.RestartPolicy == "" {
+		errs = append(errs, field.Required(path.Child("template", "spec", "restartPolicy"), "must be OnFailure or Never"))
+	}
+
+	return append(errs, validatePodTemplate(js.Template, path.Child("template"), corev1.RestartPolicyOnFailure, corev1.RestartPolicyNever)...)
+}
@@ -0,0 +1,156 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestJobSpecSharedWithCronJob(t *testing.T) {
+	pod := NewPodSpec("migrate", PodContainer(NewContainer("migrate", ContainerImage("migrate"))))
+	opts := []JobSpecOpt{
+		JobSpecPodSpec(pod),
+		JobSpecRestartPolicy(corev1.RestartPolicyNever),
+		JobSpecIndexed(),
+		JobSpecCompletions(4),
+		JobSpecParallelism(2),
+		JobSpecBackoffLimitPerIndex(1),
+		JobSpecMaxFailedIndexes(2),
+		JobSpecTTLSecondsAfterFinished(600),
+		JobSpecSuspend(true),
+		JobSpecOnExitCodes(batchv1.PodFailurePolicyActionFailJob, "migrate", batchv1.PodFailurePolicyOnExitCodesOpIn, 42),
+		JobSpecOnPodCondition(batchv1.PodFailurePolicyActionIgnore, corev1.DisruptionTarget),
+	}
+
+	j := NewJob("migrate", JobSpec(opts...))
+	c := NewCronJob("migrate", CronJobSchedule("0 * * * *"), CronJobJobSpec(opts...))
+
+	if !reflect.DeepEqual(j.Spec, c.Spec.JobTemplate.Spec) {
+		t.Errorf("job spec = %+v, want the cron job template %+v", j.Spec, c.Spec.JobTemplate.Spec)
+	}
+	if err := j.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	rules := j.Spec.PodFailurePolicy.Rules
+	if len(rules) != 2 || *rules[0].OnExitCodes.ContainerName != "migrate" || rules[1].OnPodConditions[0].Type != corev1.DisruptionTarget {
+		t.Errorf("pod failure policy rules = %+v", rules)
+	}
+}
+
+func TestJobSpecSharedOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		opt    JobSpecOpt
+		mutate func(*batchv1.JobSpec)
+		check  func(batchv1.JobSpec) bool
+	}{
+		{
+			name:   "parallelism",
+			opt:    JobSpecParallelism(2),
+			mutate: func(js *batchv1.JobSpec) { *js.Parallelism = 5 },
+			check:  func(js batchv1.JobSpec) bool { return *js.Parallelism == 2 },
+		},
+		{
+			name:   "suspend",
+			opt:    JobSpecSuspend(true),
+			mutate: func(js *batchv1.JobSpec) { *js.Suspend = false },
+			check:  func(js batchv1.JobSpec) bool { return *js.Suspend },
+		},
+		{
+			name:   "completion mode",
+			opt:    JobSpecIndexed(),
+			mutate: func(js *batchv1.JobSpec) { *js.CompletionMode = batchv1.NonIndexedCompletion },
+			check:  func(js batchv1.JobSpec) bool { return *js.CompletionMode == batchv1.IndexedCompletion },
+		},
+		{
+			name:   "exit codes",
+			opt:    JobSpecOnExitCodes(batchv1.PodFailurePolicyActionFailJob, "", batchv1.PodFailurePolicyOnExitCodesOpIn, 1),
+			mutate: func(js *batchv1.JobSpec) { js.PodFailurePolicy.Rules[0].OnExitCodes.Values[0] = 2 },
+			check:  func(js batchv1.JobSpec) bool { return js.PodFailurePolicy.Rules[0].OnExitCodes.Values[0] == 1 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := NewJob("first", JobSpec(tt.opt))
+			second := NewJob("second", JobSpec(tt.opt))
+
+			tt.mutate(&first.Spec)
+			if !tt.check(second.Spec) {
+				t.Error("changing one job changed another built from the same option")
+			}
+		})
+	}
+}
+
+func TestJobValidate(t *testing.T) {
+	pod := NewPodSpec("migrate", PodContainer(NewContainer("migrate", ContainerImage("migrate"))))
+
+	tests := []struct {
+		name   string
+		opts   []JobSpecOpt
+		fields []string
+	}{
+		{
+			name: "valid",
+			opts: []JobSpecOpt{JobSpecPodSpec(pod), JobSpecRestartPolicy(corev1.RestartPolicyOnFailure), JobSpecBackoffLimit(3)},
+		},
+		{
+			name:   "no restart policy",
+			opts:   []JobSpecOpt{JobSpecPodSpec(pod)},
+			fields: []string{"spec.template.spec.restartPolicy"},
+		},
+		{
+			name:   "restart always",
+			opts:   []JobSpecOpt{JobSpecPodSpec(pod), JobSpecRestartPolicy(corev1.RestartPolicyAlways)},
+			fields: []string{"spec.template.spec.restartPolicy"},
+		},
+		{
+			name: "pod failure policy with on failure",
+			opts: []JobSpecOpt{JobSpecPodSpec(pod), JobSpecRestartPolicy(corev1.RestartPolicyOnFailure),
+				JobSpecOnPodCondition(batchv1.PodFailurePolicyActionIgnore, corev1.DisruptionTarget)},
+			fields: []string{"spec.template.spec.restartPolicy"},
+		},
+		{
+			name:   "backoff limit per index without indexed completion",
+			opts:   []JobSpecOpt{JobSpecPodSpec(pod), JobSpecRestartPolicy(corev1.RestartPolicyNever), JobSpecBackoffLimitPerIndex(1)},
+			fields: []string{"spec.backoffLimitPerIndex"},
+		},
+		{
+			name:   "max failed indexes without backoff limit per index",
+			opts:   []JobSpecOpt{JobSpecPodSpec(pod), JobSpecRestartPolicy(corev1.RestartPolicyNever), JobSpecIndexed(), JobSpecMaxFailedIndexes(1)},
+			fields: []string{"spec.maxFailedIndexes"},
+		},
+		{
+			name:   "negative parallelism",
+			opts:   []JobSpecOpt{JobSpecPodSpec(pod), JobSpecRestartPolicy(corev1.RestartPolicyNever), JobSpecParallelism(-1)},
+			fields: []string{"spec.parallelism"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, NewJob("migrate", JobSpec(tt.opts...)).Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -15,9 +15,11 @@
 package kopts
 
 import (
+	"encoding/json"
 	"fmt"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
 	"sigs.k8s.io/yaml"
 )
 
@@ -31,15 +33,29 @@ func MarshalYaml(i interface{}) (string, error) {
 	return fmt.Sprintf("---\n%s\n", o), nil
 }
 
-func addAnnotation(key, value string, m *metav1.ObjectMeta) {
-	if m.Annotations == nil {
-		m.Annotations = make(map[string]string)
+// MarshalYamlStrict validates the object before marshalling it to YAML. Objects that do not implement Validator
+// are marshalled as with MarshalYaml
+func MarshalYamlStrict(i interface{}) (string, error) {
+	if v, ok := i.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return "", err
+		}
 	}
-	m.Annotations[key] = value
+
+	return MarshalYaml(i)
+}
+
+func addAnnotation(key, value string, m metav1.Object) {
+	annotations := m.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[key] = value
+	m.SetAnnotations(annotations)
 }
 
-func setNamespace(n string, m *metav1.ObjectMeta) {
-	m.Namespace = n
+func setNamespace(n string, m metav1.Object) {
+	m.SetNamespace(n)
 }
 
 func newObjectMeta(name string) metav1.ObjectMeta {
@@ -50,10 +66,59 @@ func newObjectMeta(name string) metav1.ObjectMeta {
 	}
 }
 
-func addLabel(key, value string, m *metav1.ObjectMeta) {
-	if m.Labels == nil {
-		m.Labels = make(map[string]string)
+func addLabel(key, value string, m metav1.Object) {
+	labels := m.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+
+	labels[key] = value
+	m.SetLabels(labels)
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
+
+// ptrValue returns the value the pointer points to or the zero value for nil
+func ptrValue[T any](p *T) T {
+	var v T
+	if p != nil {
+		v = *p
+	}
+
+	return v
+}
+
+// jsonValue converts the value to its JSON representation of maps, slices and scalars, keeping integers as int64
+func jsonValue(o interface{}) (interface{}, error) {
+	if o == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	if err := utiljson.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// deepCopyJSON returns a deep copy of an API type without generated deep copy functions through a JSON round trip
+func deepCopyJSON[T any](in T) T {
+	var out T
+	b, err := json.Marshal(in)
+	if err != nil {
+		panic(fmt.Sprintf("kopts: copying %T: %v", in, err))
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		panic(fmt.Sprintf("kopts: copying %T: %v", in, err))
 	}
 
-	m.Labels[key] = value
+	return out
 }
@@ -0,0 +1,157 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// LimitRange holds a Kubernetes limit range
+type LimitRange struct {
+	corev1.LimitRange
+}
+
+type LimitRangeOpt func(*LimitRange)
+
+// Limits holds the constraints of a limit range item. Default and DefaultRequest are only supported for containers
+type Limits struct {
+	Default              corev1.ResourceList
+	DefaultRequest       corev1.ResourceList
+	Min                  corev1.ResourceList
+	Max                  corev1.ResourceList
+	MaxLimitRequestRatio corev1.ResourceList
+}
+
+// NewLimitRange returns a limit range with the given name and options
+func NewLimitRange(name string, opts ...LimitRangeOpt) LimitRange {
+	lr := LimitRange{
+		LimitRange: corev1.LimitRange{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "LimitRange",
+				APIVersion: "v1",
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&lr)
+	}
+
+	return lr
+}
+
+// LimitRangeNamespace sets the namespace for the limit range
+func LimitRangeNamespace(n string) LimitRangeOpt {
+	return func(lr *LimitRange) {
+		setNamespace(n, &lr.ObjectMeta)
+	}
+}
+
+// LimitRangeContainer adds the limits for each container
+func LimitRangeContainer(l Limits) LimitRangeOpt {
+	return limitRangeItem(corev1.LimitTypeContainer, l)
+}
+
+// LimitRangePod adds the limits for the sum of the containers in a pod
+func LimitRangePod(l Limits) LimitRangeOpt {
+	return limitRangeItem(corev1.LimitTypePod, l)
+}
+
+// LimitRangePersistentVolumeClaim adds the limits for the storage requested by each claim
+func LimitRangePersistentVolumeClaim(l Limits) LimitRangeOpt {
+	return limitRangeItem(corev1.LimitTypePersistentVolumeClaim, l)
+}
+
+func limitRangeItem(t corev1.LimitType, l Limits) LimitRangeOpt {
+	return func(lr *LimitRange) {
+		lr.Spec.Limits = append(lr.Spec.Limits, corev1.LimitRangeItem{
+			Type:                 t,
+			Default:              l.Default.DeepCopy(),
+			DefaultRequest:       l.DefaultRequest.DeepCopy(),
+			Min:                  l.Min.DeepCopy(),
+			Max:                  l.Max.DeepCopy(),
+			MaxLimitRequestRatio: l.MaxLimitRequestRatio.DeepCopy(),
+		})
+	}
+}
+
+// Apply applies the options to the limit range
+func (lr *LimitRange) Apply(opts ...LimitRangeOpt) {
+	for _, opt := range opts {
+		opt(lr)
+	}
+}
+
+// With returns a copy of the limit range with the options applied
+func (lr LimitRange) With(opts ...LimitRangeOpt) LimitRange {
+	clone := lr.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the limit range
+func (lr LimitRange) Clone() LimitRange {
+	return LimitRange{LimitRange: *lr.LimitRange.DeepCopy()}
+}
+
+// Validate checks the metadata, the limit types, that only container limits set defaults and that the defaults are
+// within the min and max
+func (lr LimitRange) Validate() error {
+	errs := validateObjectMeta(&lr.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec", "limits")
+
+	for i, l := range lr.Spec.Limits {
+		itemPath := path.Index(i)
+		if l.Type == "" {
+			errs = append(errs, field.Required(itemPath.Child("type"), ""))
+		}
+		errs = append(errs, validateEnum(l.Type, itemPath.Child("type"),
+			corev1.LimitTypePod, corev1.LimitTypeContainer, corev1.LimitTypePersistentVolumeClaim)...)
+		if l.Type != corev1.LimitTypeContainer {
+			if len(l.Default) > 0 {
+				errs = append(errs, field.Forbidden(itemPath.Child("default"), "may only be set for the Container type"))
+			}
+			if len(l.DefaultRequest) > 0 {
+				errs = append(errs, field.Forbidden(itemPath.Child("defaultRequest"), "may only be set for the Container type"))
+			}
+		}
+
+		for _, list := range []struct {
+			name   string
+			values corev1.ResourceList
+		}{{"default", l.Default}, {"defaultRequest", l.DefaultRequest}, {"min", l.Min}, {"max", l.Max}} {
+			errs = append(errs, validateResourceList(list.values, itemPath.Child(list.name))...)
+			for r, q := range list.values {
+				if min, ok := l.Min[r]; ok && q.Cmp(min) < 0 {
+					errs = append(errs, field.Invalid(itemPath.Child(list.name).Key(string(r)), q.String(),
+						fmt.Sprintf("must be greater than or equal to the min of %s", min.String())))
+				}
+				if max, ok := l.Max[r]; ok && q.Cmp(max) > 0 {
+					errs = append(errs, field.Invalid(itemPath.Child(list.name).Key(string(r)), q.String(),
+						fmt.Sprintf("must be less than or equal to the max of %s", max.String())))
+				}
+			}
+		}
+	}
+
+	return toAggregate(errs)
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// MetaOpt sets object metadata on any Kubernetes object
+type MetaOpt func(metav1.Object)
+
+// Owner is an object that can be referenced as the owner of another object. Pointers to every kopts type satisfy it
+type Owner interface {
+	metav1.Object
+	GetObjectKind() schema.ObjectKind
+}
+
+// Meta adapts metadata options to the options of any kopts type, e.g. Meta[*Secret](MetaLabel("app", "api")) is a SecretOpt
+func Meta[T metav1.Object](opts ...MetaOpt) func(T) {
+	return func(o T) {
+		ApplyMeta(o, opts...)
+	}
+}
+
+// ApplyMeta applies metadata options to an existing object
+func ApplyMeta(o metav1.Object, opts ...MetaOpt) {
+	for _, v := range opts {
+		v(o)
+	}
+}
+
+// MetaLabel sets a single label
+func MetaLabel(key, value string) MetaOpt {
+	return func(o metav1.Object) {
+		addLabel(key, value, o)
+	}
+}
+
+// MetaLabels sets multiple labels
+func MetaLabels(labels map[string]string) MetaOpt {
+	labels = maps.Clone(labels)
+	return func(o metav1.Object) {
+		for k, v := range labels {
+			addLabel(k, v, o)
+		}
+	}
+}
+
+// MetaAnnotation sets a single annotation
+func MetaAnnotation(key, value string) MetaOpt {
+	return func(o metav1.Object) {
+		addAnnotation(key, value, o)
+	}
+}
+
+// MetaAnnotations sets multiple annotations
+func MetaAnnotations(annotations map[string]string) MetaOpt {
+	annotations = maps.Clone(annotations)
+	return func(o metav1.Object) {
+		for k, v := range annotations {
+			addAnnotation(k, v, o)
+		}
+	}
+}
+
+// MetaNamespace sets the namespace
+func MetaNamespace(n string) MetaOpt {
+	return func(o metav1.Object) {
+		setNamespace(n, o)
+	}
+}
+
+// MetaFinalizers adds finalizers, skipping any that are already set
+func MetaFinalizers(finalizers ...string) MetaOpt {
+	finalizers = slices.Clone(finalizers)
+	return func(o metav1.Object) {
+		existing := slices.Clone(o.GetFinalizers())
+		for _, f := range finalizers {
+			if !slices.Contains(existing, f) {
+				existing = append(existing, f)
+			}
+		}
+		o.SetFinalizers(existing)
+	}
+}
+
+// MetaGenerateName sets the prefix the API server uses to generate a unique name. The name is cleared so the prefix is used
+func MetaGenerateName(prefix string) MetaOpt {
+	return func(o metav1.Object) {
+		o.SetName("")
+		o.SetGenerateName(prefix)
+	}
+}
+
+// MetaOwnerReference adds an owner reference, replacing any existing reference to the same owner
+func MetaOwnerReference(ref metav1.OwnerReference) MetaOpt {
+	ref = *ref.DeepCopy()
+	return func(o metav1.Object) {
+		ref := *ref.DeepCopy()
+		refs := slices.Clone(o.GetOwnerReferences())
+		for i, v := range refs {
+			if v.APIVersion == ref.APIVersion && v.Kind == ref.Kind && v.Name == ref.Name {
+				refs[i] = ref
+				o.SetOwnerReferences(refs)
+				return
+			}
+		}
+		o.SetOwnerReferences(append(refs, ref))
+	}
+}
+
+// MetaOwner adds an owner reference built from the owner's kind, apiVersion, name and UID. When controller is true the owner is marked as the managing controller
+// and blocks deletion until this object is removed. The UID is only known for objects read from the cluster, so this is meant for controllers rather than static manifests.
+// An error is returned when the owner has no UID
+func MetaOwner(owner Owner, controller bool) (MetaOpt, error) {
+	if owner.GetUID() == "" {
+		return nil, fmt.Errorf("owner %s has no UID", owner.GetName())
+	}
+
+	gvk := owner.GetObjectKind().GroupVersionKind()
+	ref := metav1.OwnerReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       owner.GetName(),
+		UID:        owner.GetUID(),
+	}
+	if controller {
+		ref.Controller = ptrTo(true)
+		ref.BlockOwnerDeletion = ptrTo(true)
+	}
+
+	return MetaOwnerReference(ref), nil
+}
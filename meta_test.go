@@ -0,0 +1,140 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestMetaKinds(t *testing.T) {
+	opts := []MetaOpt{
+		MetaNamespace("apps"),
+		MetaLabel("app", "api"),
+		MetaAnnotation("owner", "platform"),
+	}
+
+	tests := []struct {
+		name   string
+		object metav1.Object
+	}{
+		{name: "secret", object: ptrTo(NewSecret("api", Meta[*Secret](opts...)))},
+		{name: "service", object: ptrTo(NewService("api", Meta[*Service](opts...)))},
+		{name: "role", object: ptrTo(NewRole("api", Meta[*Role](opts...)))},
+		{name: "role binding", object: ptrTo(NewRoleBinding("api", Meta[*RoleBinding](opts...)))},
+		{name: "service account", object: ptrTo(NewServiceAccount("api", Meta[*ServiceAccount](opts...)))},
+		{name: "cron job", object: NewCronJob("api", Meta[*CronJob](opts...))},
+		{name: "deployment", object: NewDeployment("api", Meta[*Deployment](opts...))},
+		{name: "object", object: ptrTo(NewObject("example.com/v1", "Widget", "api", Meta[*Object](opts...)))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := tt.object
+			if o.GetNamespace() != "apps" {
+				t.Errorf("namespace = %q, want apps", o.GetNamespace())
+			}
+			if got := o.GetLabels()["app"]; got != "api" {
+				t.Errorf("app label = %q, want api", got)
+			}
+			if got := o.GetAnnotations()["owner"]; got != "platform" {
+				t.Errorf("owner annotation = %q, want platform", got)
+			}
+		})
+	}
+}
+
+func TestMetaNamespaceLabels(t *testing.T) {
+	ns := NewNamespace("apps", Meta[*Namespace](MetaLabel("istio-injection", "enabled")))
+	ApplyMeta(&ns, MetaAnnotation("owner", "platform"))
+
+	if got := ns.Labels["istio-injection"]; got != "enabled" {
+		t.Errorf("istio-injection label = %q, want enabled", got)
+	}
+	if got := ns.Annotations["owner"]; got != "platform" {
+		t.Errorf("owner annotation = %q, want platform", got)
+	}
+}
+
+func TestMetaMaps(t *testing.T) {
+	labels := map[string]string{"app": "api"}
+	annotations := map[string]string{"owner": "platform"}
+	opt := Meta[*Secret](MetaLabels(labels), MetaAnnotations(annotations))
+	labels["app"] = "changed"
+	annotations["owner"] = "changed"
+
+	s := NewSecret("api", opt)
+	if s.Labels["app"] != "api" || s.Annotations["owner"] != "platform" {
+		t.Errorf("metadata = %v %v after changing the caller's maps, want the original values", s.Labels, s.Annotations)
+	}
+}
+
+func TestMetaFinalizers(t *testing.T) {
+	opt := MetaFinalizers("example.com/cleanup", "example.com/backup")
+	s := NewSecret("api", Meta[*Secret](MetaFinalizers("example.com/cleanup"), opt))
+
+	if want := []string{"example.com/cleanup", "example.com/backup"}; !reflect.DeepEqual(s.Finalizers, want) {
+		t.Errorf("finalizers = %v, want %v", s.Finalizers, want)
+	}
+}
+
+func TestMetaGenerateName(t *testing.T) {
+	j := NewJob("migrate", Meta[*Job](MetaGenerateName("migrate-")))
+
+	if j.Name != "" || j.GenerateName != "migrate-" {
+		t.Errorf("name = %q generateName = %q, want an empty name and the migrate- prefix", j.Name, j.GenerateName)
+	}
+}
+
+func TestMetaOwner(t *testing.T) {
+	d := NewDeployment("api", DeploymentNamespace("apps"))
+	if _, err := MetaOwner(d, true); err == nil {
+		t.Error("MetaOwner() without a UID = nil, want an error")
+	}
+
+	d.UID = types.UID("6f0c1ea4-9d3b-4a1e-8d2c-1f2e3d4c5b6a")
+	opt, err := MetaOwner(d, true)
+	if err != nil {
+		t.Fatalf("MetaOwner() = %v", err)
+	}
+	s := NewSecret("api", Meta[*Secret](opt, opt))
+
+	if len(s.OwnerReferences) != 1 {
+		t.Fatalf("owner references = %d, want the same owner once", len(s.OwnerReferences))
+	}
+	ref := s.OwnerReferences[0]
+	if ref.APIVersion != "apps/v1" || ref.Kind != "Deployment" || ref.Name != "api" || ref.UID != d.UID {
+		t.Errorf("owner reference = %+v, want the api deployment", ref)
+	}
+	if !ptrValue(ref.Controller) || !ptrValue(ref.BlockOwnerDeletion) {
+		t.Errorf("owner reference = %+v, want a controller blocking deletion", ref)
+	}
+
+	*ref.Controller = false
+	if got := NewSecret("second", Meta[*Secret](opt)).OwnerReferences[0]; !ptrValue(got.Controller) {
+		t.Errorf("controller = false for a second secret, want true")
+	}
+
+	notController, err := MetaOwner(d, false)
+	if err != nil {
+		t.Fatalf("MetaOwner() = %v", err)
+	}
+	if got := NewSecret("api", Meta[*Secret](notController)).OwnerReferences[0]; got.Controller != nil {
+		t.Errorf("controller = %v, want unset", *got.Controller)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// MutatingWebhookConfiguration holds a Kubernetes mutating admission webhook configuration
+type MutatingWebhookConfiguration struct {
+	admissionregistrationv1.MutatingWebhookConfiguration
+}
+
+type MutatingWebhookConfigurationOpt func(*MutatingWebhookConfiguration)
+
+// NewMutatingWebhookConfiguration returns a mutating webhook configuration with the given name and options
+func NewMutatingWebhookConfiguration(name string, opts ...MutatingWebhookConfigurationOpt) MutatingWebhookConfiguration {
+	wc := MutatingWebhookConfiguration{
+		MutatingWebhookConfiguration: admissionregistrationv1.MutatingWebhookConfiguration{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "MutatingWebhookConfiguration",
+				APIVersion: "admissionregistration.k8s.io/v1",
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&wc)
+	}
+
+	return wc
+}
+
+// MutatingWebhookConfigurationWebhook adds a webhook
+func MutatingWebhookConfigurationWebhook(w Webhook) MutatingWebhookConfigurationOpt {
+	webhook := w.mutatingWebhook()
+	return func(wc *MutatingWebhookConfiguration) {
+		wc.Webhooks = append(wc.Webhooks, *webhook.DeepCopy())
+	}
+}
+
+// MutatingWebhookConfigurationInjectCAFrom lets the cert-manager CA injector fill the CA bundle of every webhook
+// from the certificate of the webhook server
+func MutatingWebhookConfigurationInjectCAFrom(c Certificate) MutatingWebhookConfigurationOpt {
+	value := injectCAFromAnnotation(c)
+	return func(wc *MutatingWebhookConfiguration) {
+		addAnnotation(injectCAFromKey, value, &wc.ObjectMeta)
+	}
+}
+
+// Apply applies the options to the webhook configuration
+func (wc *MutatingWebhookConfiguration) Apply(opts ...MutatingWebhookConfigurationOpt) {
+	for _, opt := range opts {
+		opt(wc)
+	}
+}
+
+// With returns a copy of the webhook configuration with the options applied
+func (wc MutatingWebhookConfiguration) With(opts ...MutatingWebhookConfigurationOpt) MutatingWebhookConfiguration {
+	clone := wc.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the webhook configuration
+func (wc MutatingWebhookConfiguration) Clone() MutatingWebhookConfiguration {
+	return MutatingWebhookConfiguration{MutatingWebhookConfiguration: *wc.MutatingWebhookConfiguration.DeepCopy()}
+}
+
+// Validate checks the metadata and that the webhooks are valid and uniquely named
+func (wc MutatingWebhookConfiguration) Validate() error {
+	errs := validateObjectMeta(&wc.ObjectMeta, false, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("webhooks")
+
+	names := make([]string, len(wc.Webhooks))
+	for i, w := range wc.Webhooks {
+		names[i] = w.Name
+		errs = append(errs, validateWebhook(admissionregistrationv1.ValidatingWebhook{
+			Name:                    w.Name,
+			ClientConfig:            w.ClientConfig,
+			Rules:                   w.Rules,
+			FailurePolicy:           w.FailurePolicy,
+			MatchPolicy:             w.MatchPolicy,
+			NamespaceSelector:       w.NamespaceSelector,
+			ObjectSelector:          w.ObjectSelector,
+			SideEffects:             w.SideEffects,
+			TimeoutSeconds:          w.TimeoutSeconds,
+			AdmissionReviewVersions: w.AdmissionReviewVersions,
+			MatchConditions:         w.MatchConditions,
+		}, path.Index(i))...)
+		errs = append(errs, validateEnum(ptrValue(w.ReinvocationPolicy), path.Index(i).Child("reinvocationPolicy"),
+			admissionregistrationv1.NeverReinvocationPolicy, admissionregistrationv1.IfNeededReinvocationPolicy)...)
+	}
+
+	return toAggregate(append(errs, validateWebhookNames(names, path)...))
+}
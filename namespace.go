@@ -16,6 +16,7 @@ package kopts
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -60,3 +61,40 @@ func NamespaceAnnotations(annotations map[string]string) NamespaceOpt {
 		}
 	}
 }
+
+// NewResourceQuota returns a resource quota in the namespace. The namespace is set after the options
+// so it can't be overridden
+func (n *Namespace) NewResourceQuota(name string, opts ...ResourceQuotaOpt) ResourceQuota {
+	return NewResourceQuota(name, append(append([]ResourceQuotaOpt{}, opts...), ResourceQuotaNamespace(n.Name))...)
+}
+
+// NewLimitRange returns a limit range in the namespace. The namespace is set after the options
+// so it can't be overridden
+func (n *Namespace) NewLimitRange(name string, opts ...LimitRangeOpt) LimitRange {
+	return NewLimitRange(name, append(append([]LimitRangeOpt{}, opts...), LimitRangeNamespace(n.Name))...)
+}
+
+// Apply applies the options to the namespace
+func (n *Namespace) Apply(opts ...NamespaceOpt) {
+	for _, opt := range opts {
+		opt(n)
+	}
+}
+
+// With returns a copy of the namespace with the options applied
+func (n Namespace) With(opts ...NamespaceOpt) Namespace {
+	clone := n.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the namespace
+func (n Namespace) Clone() Namespace {
+	return Namespace{Namespace: *n.Namespace.DeepCopy()}
+}
+
+// Validate checks the metadata. Namespace names must be DNS-1123 labels
+func (n Namespace) Validate() error {
+	return toAggregate(validateObjectMeta(&n.ObjectMeta, false, apimachineryvalidation.NameIsDNSLabel))
+}
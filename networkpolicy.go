@@ -0,0 +1,363 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// NetworkPolicy holds a Kubernetes network policy
+type NetworkPolicy struct {
+	networkingv1.NetworkPolicy
+}
+
+type NetworkPolicyOpt func(*NetworkPolicy)
+
+// NetworkPolicyRule holds an ingress or egress rule. Empty peers match all sources or destinations
+// and empty ports match all ports
+type NetworkPolicyRule struct {
+	Peers []networkingv1.NetworkPolicyPeer
+	Ports []networkingv1.NetworkPolicyPort
+}
+
+// NewNetworkPolicy returns a network policy with the given name and options.
+// Without a pod selector option the policy applies to every pod in the namespace
+func NewNetworkPolicy(name string, opts ...NetworkPolicyOpt) NetworkPolicy {
+	np := NetworkPolicy{
+		NetworkPolicy: networkingv1.NetworkPolicy{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "NetworkPolicy",
+				APIVersion: "networking.k8s.io/v1",
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&np)
+	}
+
+	return np
+}
+
+// NetworkPolicyNamespace sets the namespace for the network policy
+func NetworkPolicyNamespace(n string) NetworkPolicyOpt {
+	return func(np *NetworkPolicy) {
+		setNamespace(n, &np.ObjectMeta)
+	}
+}
+
+// NetworkPolicyPodSelector adds a label to the pod selector
+func NetworkPolicyPodSelector(key, value string) NetworkPolicyOpt {
+	return func(np *NetworkPolicy) {
+		metav1.AddLabelToSelector(&np.Spec.PodSelector, key, value)
+	}
+}
+
+// NetworkPolicyDeployment selects the deployment pods by copying the namespace and selector of the deployment.
+// Deployments without a selector return an error, as an empty pod selector would select every pod in the namespace
+func NetworkPolicyDeployment(d *Deployment) (NetworkPolicyOpt, error) {
+	selector, err := d.podSelector()
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := d.Namespace
+	return func(np *NetworkPolicy) {
+		setNamespace(namespace, &np.ObjectMeta)
+		np.Spec.PodSelector = *selector.DeepCopy()
+	}, nil
+}
+
+// NetworkPolicyTypes sets the policy types
+func NetworkPolicyTypes(types ...networkingv1.PolicyType) NetworkPolicyOpt {
+	return func(np *NetworkPolicy) {
+		np.Spec.PolicyTypes = types
+	}
+}
+
+// NetworkPolicyIngress adds an ingress rule and the Ingress policy type
+func NetworkPolicyIngress(r NetworkPolicyRule) NetworkPolicyOpt {
+	return func(np *NetworkPolicy) {
+		np.addPolicyType(networkingv1.PolicyTypeIngress)
+		np.Spec.Ingress = append(np.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			From:  r.Peers,
+			Ports: r.Ports,
+		})
+	}
+}
+
+// NetworkPolicyEgress adds an egress rule and the Egress policy type
+func NetworkPolicyEgress(r NetworkPolicyRule) NetworkPolicyOpt {
+	return func(np *NetworkPolicy) {
+		np.addPolicyType(networkingv1.PolicyTypeEgress)
+		np.Spec.Egress = append(np.Spec.Egress, networkingv1.NetworkPolicyEgressRule{
+			To:    r.Peers,
+			Ports: r.Ports,
+		})
+	}
+}
+
+// NetworkPolicyDefaultDenyAll denies all ingress and egress traffic for the selected pods
+func NetworkPolicyDefaultDenyAll() NetworkPolicyOpt {
+	return func(np *NetworkPolicy) {
+		np.addPolicyType(networkingv1.PolicyTypeIngress)
+		np.addPolicyType(networkingv1.PolicyTypeEgress)
+	}
+}
+
+// NetworkPolicyAllowSameNamespace allows ingress from every pod in the same namespace
+func NetworkPolicyAllowSameNamespace() NetworkPolicyOpt {
+	return NetworkPolicyIngress(NetworkPolicyRule{
+		Peers: []networkingv1.NetworkPolicyPeer{
+			{
+				PodSelector: &metav1.LabelSelector{},
+			},
+		},
+	})
+}
+
+// NetworkPolicyAllowDNSEgress allows egress to the cluster DNS pods in kube-system on port 53
+func NetworkPolicyAllowDNSEgress() NetworkPolicyOpt {
+	return NetworkPolicyEgress(NetworkPolicyRule{
+		Peers: []networkingv1.NetworkPolicyPeer{
+			NetworkPolicyPeerPodInNamespace(
+				map[string]string{"k8s-app": "kube-dns"},
+				map[string]string{"kubernetes.io/metadata.name": "kube-system"},
+			),
+		},
+		Ports: []networkingv1.NetworkPolicyPort{
+			NetworkPolicyPortNumber(53, corev1.ProtocolUDP),
+			NetworkPolicyPortNumber(53, corev1.ProtocolTCP),
+		},
+	})
+}
+
+func (np *NetworkPolicy) addPolicyType(t networkingv1.PolicyType) {
+	for _, v := range np.Spec.PolicyTypes {
+		if v == t {
+			return
+		}
+	}
+	np.Spec.PolicyTypes = append(np.Spec.PolicyTypes, t)
+}
+
+// NetworkPolicyPeerPods returns a peer matching pods with the labels in the policy namespace
+func NetworkPolicyPeerPods(labels map[string]string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: maps.Clone(labels),
+		},
+	}
+}
+
+// NetworkPolicyPeerNamespaces returns a peer matching all pods in namespaces with the labels
+func NetworkPolicyPeerNamespaces(labels map[string]string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: maps.Clone(labels),
+		},
+	}
+}
+
+// NetworkPolicyPeerPodInNamespace returns a peer matching pods with the pod labels in namespaces with the namespace labels
+func NetworkPolicyPeerPodInNamespace(podLabels, namespaceLabels map[string]string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: maps.Clone(podLabels),
+		},
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: maps.Clone(namespaceLabels),
+		},
+	}
+}
+
+// NetworkPolicyPeerDeployment returns a peer matching the pods of the deployment. Deployments without a selector
+// return an error, as an empty pod selector would match every pod in the namespace
+func NetworkPolicyPeerDeployment(d *Deployment) (networkingv1.NetworkPolicyPeer, error) {
+	selector, err := d.podSelector()
+	if err != nil {
+		return networkingv1.NetworkPolicyPeer{}, err
+	}
+
+	return networkingv1.NetworkPolicyPeer{
+		PodSelector: selector,
+	}, nil
+}
+
+// NetworkPolicyPeerIPBlock returns a peer matching the CIDR without the excepted CIDRs
+func NetworkPolicyPeerIPBlock(cidr string, except ...string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		IPBlock: &networkingv1.IPBlock{
+			CIDR:   cidr,
+			Except: slices.Clone(except),
+		},
+	}
+}
+
+// NetworkPolicyPortNumber returns a port matching the port number and protocol
+func NetworkPolicyPortNumber(port int, protocol corev1.Protocol) networkingv1.NetworkPolicyPort {
+	p := intstr.FromInt(port)
+	return networkingv1.NetworkPolicyPort{
+		Protocol: &protocol,
+		Port:     &p,
+	}
+}
+
+// NetworkPolicyPortName returns a port matching the named container port and protocol
+func NetworkPolicyPortName(name string, protocol corev1.Protocol) networkingv1.NetworkPolicyPort {
+	p := intstr.FromString(name)
+	return networkingv1.NetworkPolicyPort{
+		Protocol: &protocol,
+		Port:     &p,
+	}
+}
+
+// NetworkPolicyPortRange returns a port matching the range from port to endPort inclusive
+func NetworkPolicyPortRange(port, endPort int, protocol corev1.Protocol) networkingv1.NetworkPolicyPort {
+	p := intstr.FromInt(port)
+	end := int32(endPort)
+	return networkingv1.NetworkPolicyPort{
+		Protocol: &protocol,
+		Port:     &p,
+		EndPort:  &end,
+	}
+}
+
+// Apply applies the options to the network policy
+func (np *NetworkPolicy) Apply(opts ...NetworkPolicyOpt) {
+	for _, opt := range opts {
+		opt(np)
+	}
+}
+
+// With returns a copy of the network policy with the options applied
+func (np NetworkPolicy) With(opts ...NetworkPolicyOpt) NetworkPolicy {
+	clone := np.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the network policy
+func (np NetworkPolicy) Clone() NetworkPolicy {
+	return NetworkPolicy{NetworkPolicy: *np.NetworkPolicy.DeepCopy()}
+}
+
+// Validate checks the metadata, the selectors, the policy types and the rule peers and ports
+func (np NetworkPolicy) Validate() error {
+	errs := validateObjectMeta(&np.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	errs = append(errs, metav1validation.ValidateLabelSelector(&np.Spec.PodSelector, metav1validation.LabelSelectorValidationOptions{}, path.Child("podSelector"))...)
+	for i, t := range np.Spec.PolicyTypes {
+		errs = append(errs, validateEnum(t, path.Child("policyTypes").Index(i), networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress)...)
+	}
+
+	for i, r := range np.Spec.Ingress {
+		rulePath := path.Child("ingress").Index(i)
+		errs = append(errs, validateNetworkPolicyPeers(r.From, rulePath.Child("from"))...)
+		errs = append(errs, validateNetworkPolicyPorts(r.Ports, rulePath.Child("ports"))...)
+	}
+	for i, r := range np.Spec.Egress {
+		rulePath := path.Child("egress").Index(i)
+		errs = append(errs, validateNetworkPolicyPeers(r.To, rulePath.Child("to"))...)
+		errs = append(errs, validateNetworkPolicyPorts(r.Ports, rulePath.Child("ports"))...)
+	}
+
+	return toAggregate(errs)
+}
+
+func validateNetworkPolicyPeers(peers []networkingv1.NetworkPolicyPeer, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	opts := metav1validation.LabelSelectorValidationOptions{}
+
+	for i, p := range peers {
+		peerPath := path.Index(i)
+		switch {
+		case p.IPBlock != nil && (p.PodSelector != nil || p.NamespaceSelector != nil):
+			errs = append(errs, field.Forbidden(peerPath, "ipBlock cannot be combined with pod or namespace selectors"))
+		case p.IPBlock == nil && p.PodSelector == nil && p.NamespaceSelector == nil:
+			errs = append(errs, field.Required(peerPath, "one of podSelector, namespaceSelector or ipBlock must be set"))
+		}
+
+		errs = append(errs, metav1validation.ValidateLabelSelector(p.PodSelector, opts, peerPath.Child("podSelector"))...)
+		errs = append(errs, metav1validation.ValidateLabelSelector(p.NamespaceSelector, opts, peerPath.Child("namespaceSelector"))...)
+		if p.IPBlock == nil {
+			continue
+		}
+
+		_, cidr, err := net.ParseCIDR(p.IPBlock.CIDR)
+		if err != nil {
+			errs = append(errs, field.Invalid(peerPath.Child("ipBlock", "cidr"), p.IPBlock.CIDR, err.Error()))
+			continue
+		}
+		for j, e := range p.IPBlock.Except {
+			exceptPath := peerPath.Child("ipBlock", "except").Index(j)
+			ip, except, err := net.ParseCIDR(e)
+			switch {
+			case err != nil:
+				errs = append(errs, field.Invalid(exceptPath, e, err.Error()))
+			case !cidr.Contains(ip) || except.String() == cidr.String():
+				errs = append(errs, field.Invalid(exceptPath, e, fmt.Sprintf("must be a strict subset of %s", p.IPBlock.CIDR)))
+			}
+		}
+	}
+
+	return errs
+}
+
+func validateNetworkPolicyPorts(ports []networkingv1.NetworkPolicyPort, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	for i, p := range ports {
+		portPath := path.Index(i)
+		errs = append(errs, validateEnum(ptrValue(p.Protocol), portPath.Child("protocol"),
+			corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP)...)
+		if p.Port == nil {
+			if p.EndPort != nil {
+				errs = append(errs, field.Required(portPath.Child("port"), "required when endPort is set"))
+			}
+			continue
+		}
+
+		errs = append(errs, validateIntOrStringPort(*p.Port, portPath.Child("port"))...)
+		if p.EndPort == nil {
+			continue
+		}
+
+		switch {
+		case p.Port.Type == intstr.String:
+			errs = append(errs, field.Forbidden(portPath.Child("endPort"), "may not be used with a named port"))
+		case *p.EndPort < p.Port.IntVal:
+			errs = append(errs, field.Invalid(portPath.Child("endPort"), *p.EndPort, "must be greater than or equal to port"))
+		default:
+			errs = append(errs, validatePort(*p.EndPort, portPath.Child("endPort"))...)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNetworkPolicyDeployment(t *testing.T) {
+	tests := []struct {
+		name       string
+		deployment *Deployment
+		selector   metav1.LabelSelector
+		err        bool
+	}{
+		{
+			name:       "deployment selector",
+			deployment: NewDeployment("web", DeploymentNamespace("prod"), DeploymentSelector("app", "web")),
+			selector:   metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+		{
+			name:       "empty deployment selector",
+			deployment: NewDeployment("web", DeploymentNamespace("prod")),
+			err:        true,
+		},
+		{
+			name:       "nil deployment selector",
+			deployment: NewDeployment("web", func(d *Deployment) { d.Spec.Selector = nil }),
+			err:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt, err := NetworkPolicyDeployment(tt.deployment)
+			if (err != nil) != tt.err {
+				t.Fatalf("NetworkPolicyDeployment() error = %v, want error %t", err, tt.err)
+			}
+
+			peer, err := NetworkPolicyPeerDeployment(tt.deployment)
+			if (err != nil) != tt.err {
+				t.Fatalf("NetworkPolicyPeerDeployment() error = %v, want error %t", err, tt.err)
+			}
+			if tt.err {
+				return
+			}
+
+			np := NewNetworkPolicy("web", opt)
+			if np.Namespace != "prod" {
+				t.Errorf("namespace = %q, want prod", np.Namespace)
+			}
+			if !reflect.DeepEqual(np.Spec.PodSelector, tt.selector) {
+				t.Errorf("podSelector = %v, want %v", np.Spec.PodSelector, tt.selector)
+			}
+			if !reflect.DeepEqual(*peer.PodSelector, tt.selector) {
+				t.Errorf("peer podSelector = %v, want %v", *peer.PodSelector, tt.selector)
+			}
+
+			np.Spec.PodSelector.MatchLabels["app"] = "api"
+			peer.PodSelector.MatchLabels["app"] = "api"
+			if got := tt.deployment.Spec.Selector.MatchLabels["app"]; got != "web" {
+				t.Errorf("deployment selector app = %q after changing the policy, want web", got)
+			}
+		})
+	}
+}
+
+func TestNetworkPolicyPeersCopyLabels(t *testing.T) {
+	labels := map[string]string{"app": "web"}
+	peers := []networkingv1.NetworkPolicyPeer{
+		NetworkPolicyPeerPods(labels),
+		NetworkPolicyPeerNamespaces(labels),
+		NetworkPolicyPeerPodInNamespace(labels, labels),
+	}
+
+	labels["app"] = "api"
+	for i, p := range peers {
+		for _, s := range []*metav1.LabelSelector{p.PodSelector, p.NamespaceSelector} {
+			if s != nil && s.MatchLabels["app"] != "web" {
+				t.Errorf("peer %d selector app = %q after changing the labels, want web", i, s.MatchLabels["app"])
+			}
+		}
+	}
+}
+
+func TestNetworkPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []NetworkPolicyOpt
+		fields []string
+	}{
+		{
+			name: "deny all with dns",
+			opts: []NetworkPolicyOpt{NetworkPolicyPodSelector("app", "web"), NetworkPolicyDefaultDenyAll(), NetworkPolicyAllowDNSEgress()},
+		},
+		{
+			name: "ingress from a CIDR",
+			opts: []NetworkPolicyOpt{NetworkPolicyIngress(NetworkPolicyRule{
+				Peers: []networkingv1.NetworkPolicyPeer{NetworkPolicyPeerIPBlock("10.0.0.0/8", "10.1.0.0/16")},
+				Ports: []networkingv1.NetworkPolicyPort{NetworkPolicyPortName("http", corev1.ProtocolTCP)},
+			})},
+		},
+		{
+			name: "invalid CIDR",
+			opts: []NetworkPolicyOpt{NetworkPolicyEgress(NetworkPolicyRule{
+				Peers: []networkingv1.NetworkPolicyPeer{NetworkPolicyPeerIPBlock("10.0.0.0")},
+			})},
+			fields: []string{"spec.egress[0].to[0].ipBlock.cidr"},
+		},
+		{
+			name: "empty peer",
+			opts: []NetworkPolicyOpt{NetworkPolicyIngress(NetworkPolicyRule{
+				Peers: []networkingv1.NetworkPolicyPeer{{}},
+			})},
+			fields: []string{"spec.ingress[0].from[0]"},
+		},
+		{
+			name:   "unknown policy type",
+			opts:   []NetworkPolicyOpt{NetworkPolicyTypes("Sideways")},
+			fields: []string{"spec.policyTypes[0]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, NewNetworkPolicy("web", tt.opts...).Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Object holds an arbitrary Kubernetes object, such as a custom resource kopts has no builder for
+type Object struct {
+	unstructured.Unstructured
+
+	// err holds the first error of the options. It is returned when the object is marshalled
+	err error
+}
+
+type ObjectOpt func(*Object)
+
+// NewObject returns an object of the API version and kind with the given name and options
+func NewObject(apiVersion, kind, name string, opts ...ObjectOpt) Object {
+	o := Object{
+		Unstructured: unstructured.Unstructured{
+			Object: make(map[string]interface{}),
+		},
+	}
+	o.SetAPIVersion(apiVersion)
+	o.SetKind(kind)
+	o.SetName(name)
+
+	for _, v := range opts {
+		v(&o)
+	}
+
+	return o
+}
+
+// ObjectNamespace sets the namespace for the object
+func ObjectNamespace(n string) ObjectOpt {
+	return func(o *Object) {
+		setNamespace(n, o)
+	}
+}
+
+// ObjectLabel adds a label to the object
+func ObjectLabel(key, value string) ObjectOpt {
+	return func(o *Object) {
+		addLabel(key, value, o)
+	}
+}
+
+// ObjectLabels adds the labels to the object
+func ObjectLabels(labels map[string]string) ObjectOpt {
+	labels = maps.Clone(labels)
+	return func(o *Object) {
+		for k, v := range labels {
+			addLabel(k, v, o)
+		}
+	}
+}
+
+// ObjectAnnotation adds an annotation to the object
+func ObjectAnnotation(key, value string) ObjectOpt {
+	return func(o *Object) {
+		addAnnotation(key, value, o)
+	}
+}
+
+// ObjectField sets the nested field at the path of field names to the value, creating the intermediate maps.
+// The value can be any Go value and is stored as its JSON representation
+func ObjectField(value interface{}, fields ...string) ObjectOpt {
+	v, err := jsonValue(value)
+	return func(o *Object) {
+		if len(fields) == 0 {
+			o.setErr(errors.New("field path is required"))
+			return
+		}
+		if err != nil {
+			o.setErr(fmt.Errorf("%s: %w", strings.Join(fields, "."), err))
+			return
+		}
+		if err := unstructured.SetNestedField(o.Object, v, fields...); err != nil {
+			o.setErr(err)
+		}
+	}
+}
+
+// ObjectSpec sets the spec of the object to the JSON representation of the value, such as a struct or a map
+func ObjectSpec(spec interface{}) ObjectOpt {
+	return ObjectField(spec, "spec")
+}
+
+// Apply applies the options to the object
+func (o *Object) Apply(opts ...ObjectOpt) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// With returns a copy of the object with the options applied
+func (o Object) With(opts ...ObjectOpt) Object {
+	clone := o.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the object
+func (o Object) Clone() Object {
+	return Object{Unstructured: *o.Unstructured.DeepCopy(), err: o.err}
+}
+
+// Validate returns the first error of the options, and checks that the API version and kind are set and the metadata
+func (o Object) Validate() error {
+	if o.err != nil {
+		return o.err
+	}
+
+	errs := validateObjectMeta(&o.Unstructured, true, apimachineryvalidation.NameIsDNSSubdomain)
+	if o.GetAPIVersion() == "" {
+		errs = append(errs, field.Required(field.NewPath("apiVersion"), ""))
+	}
+	if o.GetKind() == "" {
+		errs = append(errs, field.Required(field.NewPath("kind"), ""))
+	}
+
+	return toAggregate(errs)
+}
+
+func (o *Object) setErr(err error) {
+	if o.err == nil {
+		o.err = err
+	}
+}
+
+// MarshalJSON returns the JSON of the object content so objects marshal like the typed wrappers,
+// or the first error of the options
+func (o Object) MarshalJSON() ([]byte, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+
+	return json.Marshal(o.Object)
+}
@@ -0,0 +1,174 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestObjectMarshalYaml(t *testing.T) {
+	type destination struct {
+		Host string `json:"host"`
+		Port int    `json:"port,omitempty"`
+	}
+
+	o := NewObject("networking.istio.io/v1", "VirtualService", "web",
+		ObjectNamespace("apps"),
+		ObjectLabel("app", "web"),
+		ObjectAnnotation("owner", "platform"),
+		ObjectSpec(map[string]interface{}{"hosts": []string{"web.example.com"}}),
+		ObjectField([]destination{{Host: "web", Port: 8080}}, "spec", "http", "route"),
+	)
+
+	got, err := MarshalYaml(o)
+	if err != nil {
+		t.Fatalf("MarshalYaml() = %v", err)
+	}
+	want := `---
+apiVersion: networking.istio.io/v1
+kind: VirtualService
+metadata:
+  annotations:
+    owner: platform
+  labels:
+    app: web
+  name: web
+  namespace: apps
+spec:
+  hosts:
+  - web.example.com
+  http:
+    route:
+    - host: web
+      port: 8080
+
+`
+	if got != want {
+		t.Errorf("MarshalYaml() = %s, want %s", got, want)
+	}
+	if err := o.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestObjectField(t *testing.T) {
+	replicas := map[string]interface{}{"min": 1, "max": 3}
+	opt := ObjectField(replicas, "spec", "replicas")
+	labels := map[string]string{"app": "widget"}
+	labelsOpt := ObjectLabels(labels)
+	replicas["max"] = 10
+	labels["app"] = "changed"
+	o := NewObject("example.com/v1", "Widget", "widget", opt, labelsOpt)
+
+	got, _, err := unstructured.NestedMap(o.Object, "spec", "replicas")
+	if err != nil {
+		t.Fatalf("NestedMap() = %v", err)
+	}
+	if want := map[string]interface{}{"min": int64(1), "max": int64(3)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("spec.replicas = %v after changing the caller's map, want %v", got, want)
+	}
+	if got := o.GetLabels()["app"]; got != "widget" {
+		t.Errorf("app label = %q after changing the caller's map, want widget", got)
+	}
+
+	o.Object["spec"].(map[string]interface{})["replicas"].(map[string]interface{})["min"] = int64(0)
+	second := NewObject("example.com/v1", "Widget", "second", opt)
+	if got, _, _ := unstructured.NestedInt64(second.Object, "spec", "replicas", "min"); got != 1 {
+		t.Errorf("spec.replicas.min = %d for a second object, want 1", got)
+	}
+}
+
+func TestObjectErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ObjectOpt
+		err  string
+	}{
+		{
+			name: "without field path",
+			opts: []ObjectOpt{ObjectField("value")},
+			err:  "field path is required",
+		},
+		{
+			name: "value without JSON representation",
+			opts: []ObjectOpt{ObjectField(math.Inf(1), "spec", "ratio")},
+			err:  "spec.ratio",
+		},
+		{
+			name: "field under a value",
+			opts: []ObjectOpt{ObjectField("value", "spec"), ObjectField("nested", "spec", "field")},
+			err:  "spec",
+		},
+		{
+			name: "first error is kept",
+			opts: []ObjectOpt{ObjectField("value"), ObjectField(math.Inf(1), "spec")},
+			err:  "field path is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewObject("example.com/v1", "Widget", "widget", tt.opts...)
+
+			if err := o.Validate(); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.err)
+			}
+			if _, err := MarshalYaml(o); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("MarshalYaml() = %v, want an error containing %q", err, tt.err)
+			}
+			if err := o.Clone().Validate(); err == nil {
+				t.Error("Clone().Validate() = nil, want the error of the original object")
+			}
+		})
+	}
+}
+
+func TestObjectValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		object Object
+		fields []string
+	}{
+		{
+			name:   "valid",
+			object: NewObject("example.com/v1", "Widget", "widget", ObjectNamespace("apps")),
+			fields: nil,
+		},
+		{
+			name:   "without API version and kind",
+			object: NewObject("", "", "widget"),
+			fields: []string{"apiVersion", "kind"},
+		},
+		{
+			name:   "invalid name and label",
+			object: NewObject("example.com/v1", "Widget", "Widget", ObjectLabel("app", "web app")),
+			fields: []string{"metadata.labels", "metadata.name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, tt.object.Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -17,7 +17,9 @@ package kopts
 import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // PersistentVolume holds a Kubernetes persistent volume
@@ -77,3 +79,48 @@ func PersistentVolumeLocal(path string, fsType string) PersistentVolumeOpt {
 		}
 	}
 }
+
+// Set the volume storage class
+func PersistentVolumeStorageClass(sc StorageClass) PersistentVolumeOpt {
+	return func(pv *PersistentVolume) {
+		pv.Spec.StorageClassName = sc.Name
+	}
+}
+
+// Apply applies the options to the persistent volume
+func (pv *PersistentVolume) Apply(opts ...PersistentVolumeOpt) {
+	for _, opt := range opts {
+		opt(pv)
+	}
+}
+
+// With returns a copy of the persistent volume with the options applied
+func (pv PersistentVolume) With(opts ...PersistentVolumeOpt) PersistentVolume {
+	clone := pv.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the persistent volume
+func (pv PersistentVolume) Clone() PersistentVolume {
+	return PersistentVolume{PersistentVolume: *pv.PersistentVolume.DeepCopy()}
+}
+
+// Validate checks the metadata, the capacity, the access modes and the reclaim policy and volume mode
+func (pv PersistentVolume) Validate() error {
+	errs := validateObjectMeta(&pv.ObjectMeta, false, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	if _, ok := pv.Spec.Capacity[corev1.ResourceStorage]; !ok {
+		errs = append(errs, field.Required(path.Child("capacity").Key(string(corev1.ResourceStorage)), ""))
+	}
+	errs = append(errs, validateResourceList(pv.Spec.Capacity, path.Child("capacity"))...)
+	errs = append(errs, validateAccessModes(pv.Spec.AccessModes, path.Child("accessModes"))...)
+	errs = append(errs, validateEnum(pv.Spec.PersistentVolumeReclaimPolicy, path.Child("persistentVolumeReclaimPolicy"),
+		corev1.PersistentVolumeReclaimRetain, corev1.PersistentVolumeReclaimDelete, corev1.PersistentVolumeReclaimRecycle)...)
+	errs = append(errs, validateEnum(ptrValue(pv.Spec.VolumeMode), path.Child("volumeMode"),
+		corev1.PersistentVolumeBlock, corev1.PersistentVolumeFilesystem)...)
+
+	return toAggregate(errs)
+}
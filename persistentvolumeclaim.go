@@ -0,0 +1,217 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	snapshotv1 "github.com/CoverWhale/kopts/apis/snapshot/v1"
+)
+
+// PersistentVolumeClaim holds a Kubernetes persistent volume claim
+type PersistentVolumeClaim struct {
+	corev1.PersistentVolumeClaim
+}
+
+type PersistentVolumeClaimOpt func(*PersistentVolumeClaim)
+
+// NewPersistentVolumeClaim returns a persistent volume claim with the given name and options
+func NewPersistentVolumeClaim(name string, opts ...PersistentVolumeClaimOpt) PersistentVolumeClaim {
+	pvc := PersistentVolumeClaim{
+		PersistentVolumeClaim: corev1.PersistentVolumeClaim{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "PersistentVolumeClaim",
+				APIVersion: "v1",
+			},
+			ObjectMeta: newObjectMeta(name),
+			Spec:       corev1.PersistentVolumeClaimSpec{},
+		},
+	}
+
+	for _, v := range opts {
+		v(&pvc)
+	}
+
+	return pvc
+}
+
+// PersistentVolumeClaimNamespace sets the namespace for the claim
+func PersistentVolumeClaimNamespace(n string) PersistentVolumeClaimOpt {
+	return func(pvc *PersistentVolumeClaim) {
+		setNamespace(n, &pvc.ObjectMeta)
+	}
+}
+
+// PersistentVolumeClaimAccessModes sets the access modes for the claim
+func PersistentVolumeClaimAccessModes(modes ...corev1.PersistentVolumeAccessMode) PersistentVolumeClaimOpt {
+	return func(pvc *PersistentVolumeClaim) {
+		pvc.Spec.AccessModes = slices.Clone(modes)
+	}
+}
+
+// PersistentVolumeClaimStorage sets the requested storage size for the claim
+func PersistentVolumeClaimStorage(size resource.Quantity) PersistentVolumeClaimOpt {
+	return func(pvc *PersistentVolumeClaim) {
+		if pvc.Spec.Resources.Requests == nil {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = size
+	}
+}
+
+// PersistentVolumeClaimStorageClassName sets the storage class name for the claim
+func PersistentVolumeClaimStorageClassName(name string) PersistentVolumeClaimOpt {
+	return func(pvc *PersistentVolumeClaim) {
+		pvc.Spec.StorageClassName = ptrTo(name)
+	}
+}
+
+// PersistentVolumeClaimStorageClass sets the storage class for the claim
+func PersistentVolumeClaimStorageClass(sc StorageClass) PersistentVolumeClaimOpt {
+	return PersistentVolumeClaimStorageClassName(sc.Name)
+}
+
+// PersistentVolumeClaimVolumeMode sets whether the claim is a filesystem or a raw block volume
+func PersistentVolumeClaimVolumeMode(m corev1.PersistentVolumeMode) PersistentVolumeClaimOpt {
+	return func(pvc *PersistentVolumeClaim) {
+		pvc.Spec.VolumeMode = ptrTo(m)
+	}
+}
+
+// PersistentVolumeClaimSelector adds a label to the selector of volumes the claim can bind to
+func PersistentVolumeClaimSelector(key, value string) PersistentVolumeClaimOpt {
+	return func(pvc *PersistentVolumeClaim) {
+		if pvc.Spec.Selector == nil {
+			pvc.Spec.Selector = &metav1.LabelSelector{}
+		}
+		metav1.AddLabelToSelector(pvc.Spec.Selector, key, value)
+	}
+}
+
+// PersistentVolumeClaimVolume binds the claim to the persistent volume. The storage class is copied
+// from the volume since a claim only binds to a volume of the same class
+func PersistentVolumeClaimVolume(pv PersistentVolume) PersistentVolumeClaimOpt {
+	name := pv.Name
+	class := pv.Spec.StorageClassName
+	return func(pvc *PersistentVolumeClaim) {
+		pvc.Spec.VolumeName = name
+		pvc.Spec.StorageClassName = ptrTo(class)
+	}
+}
+
+// PersistentVolumeClaimDataSource sets the data source the claim is populated from
+func PersistentVolumeClaimDataSource(ref corev1.TypedLocalObjectReference) PersistentVolumeClaimOpt {
+	return func(pvc *PersistentVolumeClaim) {
+		pvc.Spec.DataSource = ref.DeepCopy()
+	}
+}
+
+// PersistentVolumeClaimDataSourceRef sets the data source the claim is populated from.
+// Unlike the data source it can refer to any populator and to objects in other namespaces
+func PersistentVolumeClaimDataSourceRef(ref corev1.TypedObjectReference) PersistentVolumeClaimOpt {
+	return func(pvc *PersistentVolumeClaim) {
+		pvc.Spec.DataSourceRef = ref.DeepCopy()
+	}
+}
+
+// PersistentVolumeClaimFromSnapshot populates the claim from the volume snapshot with the given name
+func PersistentVolumeClaimFromSnapshot(name string) PersistentVolumeClaimOpt {
+	group := snapshotv1.GroupName
+	return PersistentVolumeClaimDataSource(corev1.TypedLocalObjectReference{
+		APIGroup: &group,
+		Kind:     "VolumeSnapshot",
+		Name:     name,
+	})
+}
+
+// PersistentVolumeClaimFromVolumeSnapshot populates the claim from the volume snapshot
+func PersistentVolumeClaimFromVolumeSnapshot(vs VolumeSnapshot) PersistentVolumeClaimOpt {
+	return PersistentVolumeClaimFromSnapshot(vs.Name)
+}
+
+// PersistentVolumeClaimFromClaim clones the existing claim into the new claim
+func PersistentVolumeClaimFromClaim(source PersistentVolumeClaim) PersistentVolumeClaimOpt {
+	return PersistentVolumeClaimDataSource(corev1.TypedLocalObjectReference{
+		Kind: "PersistentVolumeClaim",
+		Name: source.Name,
+	})
+}
+
+// Returns the claim as a volume source
+func (pvc PersistentVolumeClaim) AsVolumeSource() corev1.VolumeSource {
+	return corev1.VolumeSource{
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: pvc.Name,
+		},
+	}
+}
+
+// volumeName returns the name pods use for the claim volume. Claim names are DNS subdomains while volume names
+// must be DNS labels, so dots are replaced with dashes and the name is cut to 63 characters
+func (pvc PersistentVolumeClaim) volumeName() string {
+	name := strings.ReplaceAll(pvc.Name, ".", "-")
+	if len(name) > validation.DNS1123LabelMaxLength {
+		name = name[:validation.DNS1123LabelMaxLength]
+	}
+
+	return strings.TrimRight(name, "-")
+}
+
+// Apply applies the options to the persistent volume claim
+func (pvc *PersistentVolumeClaim) Apply(opts ...PersistentVolumeClaimOpt) {
+	for _, opt := range opts {
+		opt(pvc)
+	}
+}
+
+// With returns a copy of the persistent volume claim with the options applied
+func (pvc PersistentVolumeClaim) With(opts ...PersistentVolumeClaimOpt) PersistentVolumeClaim {
+	clone := pvc.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the persistent volume claim
+func (pvc PersistentVolumeClaim) Clone() PersistentVolumeClaim {
+	return PersistentVolumeClaim{PersistentVolumeClaim: *pvc.PersistentVolumeClaim.DeepCopy()}
+}
+
+// Validate checks the metadata, the access modes, the storage request, the selector and the volume mode
+func (pvc PersistentVolumeClaim) Validate() error {
+	errs := validateObjectMeta(&pvc.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateAccessModes(pvc.Spec.AccessModes, path.Child("accessModes"))...)
+	requests := path.Child("resources", "requests")
+	if _, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; !ok {
+		errs = append(errs, field.Required(requests.Key(string(corev1.ResourceStorage)), ""))
+	}
+	errs = append(errs, validateResourceList(pvc.Spec.Resources.Requests, requests)...)
+	errs = append(errs, metav1validation.ValidateLabelSelector(pvc.Spec.Selector, metav1validation.LabelSelectorValidationOptions{}, path.Child("selector"))...)
+	errs = append(errs, validateEnum(ptrValue(pvc.Spec.VolumeMode), path.Child("volumeMode"),
+		corev1.PersistentVolumeBlock, corev1.PersistentVolumeFilesystem)...)
+
+	return toAggregate(errs)
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPodVolumeClaim(t *testing.T) {
+	tests := []struct {
+		name   string
+		claim  string
+		volume string
+	}{
+		{name: "DNS label", claim: "data", volume: "data"},
+		{name: "DNS subdomain", claim: "data.web.example", volume: "data-web-example"},
+		{name: "long name", claim: strings.Repeat("a", 62) + ".b", volume: strings.Repeat("a", 62)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pvc := NewPersistentVolumeClaim(tt.claim,
+				PersistentVolumeClaimAccessModes(corev1.ReadWriteOnce),
+				PersistentVolumeClaimStorage(resource.MustParse("1Gi")),
+			)
+			c := NewContainer("web", ContainerImage("nginx"), ContainerVolumeClaim("/data", pvc))
+			p := NewPodSpec("web", PodLabel("app", "web"), PodContainer(c), PodVolumeClaim(pvc))
+
+			want := []corev1.Volume{{
+				Name: tt.volume,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: tt.claim},
+				},
+			}}
+			if !reflect.DeepEqual(p.Spec.Spec.Volumes, want) {
+				t.Errorf("volumes = %+v, want %+v", p.Spec.Spec.Volumes, want)
+			}
+			if got := p.Spec.Spec.Containers[0].VolumeMounts[0].Name; got != tt.volume {
+				t.Errorf("volume mount name = %q, want %q", got, tt.volume)
+			}
+			if err := p.Validate(); err != nil {
+				t.Errorf("Validate() = %v", err)
+			}
+			if err := pvc.Validate(); err != nil {
+				t.Errorf("claim Validate() = %v", err)
+			}
+		})
+	}
+}
+
+func TestPersistentVolumeClaimSources(t *testing.T) {
+	vs := NewVolumeSnapshot("nightly")
+	source := NewPersistentVolumeClaim("data")
+
+	tests := []struct {
+		name   string
+		opt    PersistentVolumeClaimOpt
+		source corev1.TypedLocalObjectReference
+	}{
+		{
+			name:   "volume snapshot",
+			opt:    PersistentVolumeClaimFromVolumeSnapshot(vs),
+			source: corev1.TypedLocalObjectReference{APIGroup: ptrTo("snapshot.storage.k8s.io"), Kind: "VolumeSnapshot", Name: "nightly"},
+		},
+		{
+			name:   "claim",
+			opt:    PersistentVolumeClaimFromClaim(source),
+			source: corev1.TypedLocalObjectReference{Kind: "PersistentVolumeClaim", Name: "data"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pvc := NewPersistentVolumeClaim("restore", tt.opt)
+			if !reflect.DeepEqual(*pvc.Spec.DataSource, tt.source) {
+				t.Errorf("dataSource = %+v, want %+v", *pvc.Spec.DataSource, tt.source)
+			}
+
+			pvc.Spec.DataSource.Name = "other"
+			if got := NewPersistentVolumeClaim("second", tt.opt).Spec.DataSource.Name; got != tt.source.Name {
+				t.Errorf("dataSource name = %q for a second claim, want %q", got, tt.source.Name)
+			}
+		})
+	}
+}
+
+func TestPersistentVolumeClaimVolume(t *testing.T) {
+	pv := NewPersistentVolume("nfs", func(pv *PersistentVolume) { pv.Spec.StorageClassName = "nfs" })
+	pvc := NewPersistentVolumeClaim("data", PersistentVolumeClaimVolume(pv))
+
+	if pvc.Spec.VolumeName != "nfs" || ptrValue(pvc.Spec.StorageClassName) != "nfs" {
+		t.Errorf("volumeName = %q and storageClassName = %q, want nfs for both", pvc.Spec.VolumeName, ptrValue(pvc.Spec.StorageClassName))
+	}
+}
+
+func TestPersistentVolumeClaimValidate(t *testing.T) {
+	storage := PersistentVolumeClaimStorage(resource.MustParse("1Gi"))
+
+	tests := []struct {
+		name   string
+		opts   []PersistentVolumeClaimOpt
+		fields []string
+	}{
+		{
+			name: "valid",
+			opts: []PersistentVolumeClaimOpt{PersistentVolumeClaimAccessModes(corev1.ReadWriteOnce), storage},
+		},
+		{
+			name:   "no storage request",
+			opts:   []PersistentVolumeClaimOpt{PersistentVolumeClaimAccessModes(corev1.ReadWriteOnce)},
+			fields: []string{"spec.resources.requests[storage]"},
+		},
+		{
+			name:   "no access modes",
+			opts:   []PersistentVolumeClaimOpt{storage},
+			fields: []string{"spec.accessModes"},
+		},
+		{
+			name:   "unknown volume mode",
+			opts:   []PersistentVolumeClaimOpt{PersistentVolumeClaimAccessModes(corev1.ReadWriteOnce), storage, PersistentVolumeClaimVolumeMode("Tape")},
+			fields: []string{"spec.volumeMode"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, NewPersistentVolumeClaim("data", tt.opts...).Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
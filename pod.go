@@ -17,6 +17,7 @@ package kopts
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 type PodOpt func(*PodSpec)
@@ -61,14 +62,14 @@ func PodLabel(key, value string) PodOpt {
 // Add a pod container
 func PodContainer(c Container) PodOpt {
 	return func(p *PodSpec) {
-		p.Spec.Spec.Containers = append(p.Spec.Spec.Containers, c.Container)
+		p.Spec.Spec.Containers = append(p.Spec.Spec.Containers, *c.Container.DeepCopy())
 	}
 }
 
 // Add a pod init container
 func PodInitContainer(c Container) PodOpt {
 	return func(p *PodSpec) {
-		p.Spec.Spec.InitContainers = append(p.Spec.Spec.InitContainers, c.Container)
+		p.Spec.Spec.InitContainers = append(p.Spec.Spec.InitContainers, *c.Container.DeepCopy())
 	}
 }
 
@@ -80,6 +81,65 @@ func PodConfigmapAsVolume(name string, c ConfigMap) PodOpt {
 	}
 
 	return func(p *PodSpec) {
-		p.Spec.Spec.Volumes = append(p.Spec.Spec.Volumes, volume)
+		p.Spec.Spec.Volumes = append(p.Spec.Spec.Volumes, *volume.DeepCopy())
 	}
 }
+
+// Add a persistent volume claim to the volumes. The volume is named after the claim, with dots replaced by dashes,
+// so containers can mount it with ContainerVolumeClaim
+func PodVolumeClaim(pvc PersistentVolumeClaim) PodOpt {
+	volume := corev1.Volume{
+		Name:         pvc.volumeName(),
+		VolumeSource: pvc.AsVolumeSource(),
+	}
+
+	return func(p *PodSpec) {
+		p.Spec.Spec.Volumes = append(p.Spec.Spec.Volumes, *volume.DeepCopy())
+	}
+}
+
+// Set the pod priority class
+func PodPriorityClass(pc PriorityClass) PodOpt {
+	return func(p *PodSpec) {
+		p.Spec.Spec.PriorityClassName = pc.Name
+	}
+}
+
+// template returns a copy of the pod template for a workload. Labels already set on the current template, such as
+// by MetaApp, are kept unless the pod spec sets them
+func (p PodSpec) template(current corev1.PodTemplateSpec) corev1.PodTemplateSpec {
+	t := *p.Spec.DeepCopy()
+	for k, v := range current.Labels {
+		if _, ok := t.Labels[k]; !ok {
+			addLabel(k, v, &t.ObjectMeta)
+		}
+	}
+
+	return t
+}
+
+// Apply applies the options to the pod spec
+func (p *PodSpec) Apply(opts ...PodOpt) {
+	for _, opt := range opts {
+		opt(p)
+	}
+}
+
+// With returns a copy of the pod spec with the options applied
+func (p PodSpec) With(opts ...PodOpt) PodSpec {
+	clone := p.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the pod spec
+func (p PodSpec) Clone() PodSpec {
+	p.Spec = *p.Spec.DeepCopy()
+	return p
+}
+
+// Validate checks the pod template labels and that the containers are valid and uniquely named
+func (p PodSpec) Validate() error {
+	return toAggregate(validatePodTemplate(p.Spec, field.NewPath("template")))
+}
@@ -0,0 +1,156 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	policyv1 "k8s.io/api/policy/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// PodDisruptionBudget holds a Kubernetes pod disruption budget
+type PodDisruptionBudget struct {
+	policyv1.PodDisruptionBudget
+}
+
+type PodDisruptionBudgetOpt func(*PodDisruptionBudget)
+
+// NewPodDisruptionBudget returns a pod disruption budget with the given name and options
+func NewPodDisruptionBudget(name string, opts ...PodDisruptionBudgetOpt) PodDisruptionBudget {
+	pdb := PodDisruptionBudget{
+		PodDisruptionBudget: policyv1.PodDisruptionBudget{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "PodDisruptionBudget",
+				APIVersion: "policy/v1",
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&pdb)
+	}
+
+	return pdb
+}
+
+// NewDeploymentPodDisruptionBudget returns a pod disruption budget named after the deployment.
+// The namespace and selector are copied from the deployment, which must have a selector
+func NewDeploymentPodDisruptionBudget(d *Deployment, opts ...PodDisruptionBudgetOpt) (PodDisruptionBudget, error) {
+	opt, err := PodDisruptionBudgetDeployment(d)
+	if err != nil {
+		return PodDisruptionBudget{}, err
+	}
+
+	return NewPodDisruptionBudget(d.Name, append([]PodDisruptionBudgetOpt{opt}, opts...)...), nil
+}
+
+// PodDisruptionBudgetNamespace sets the namespace for the pod disruption budget
+func PodDisruptionBudgetNamespace(n string) PodDisruptionBudgetOpt {
+	return func(pdb *PodDisruptionBudget) {
+		setNamespace(n, &pdb.ObjectMeta)
+	}
+}
+
+// PodDisruptionBudgetSelector adds a pod disruption budget selector
+func PodDisruptionBudgetSelector(key, value string) PodDisruptionBudgetOpt {
+	return func(pdb *PodDisruptionBudget) {
+		if pdb.Spec.Selector == nil {
+			pdb.Spec.Selector = &metav1.LabelSelector{}
+		}
+		metav1.AddLabelToSelector(pdb.Spec.Selector, key, value)
+	}
+}
+
+// PodDisruptionBudgetDeployment copies the namespace and selector of the deployment.
+// Deployment selectors set after this option are not picked up. Deployments without a selector return an error, as
+// an empty selector would cover every pod in the namespace
+func PodDisruptionBudgetDeployment(d *Deployment) (PodDisruptionBudgetOpt, error) {
+	selector, err := d.podSelector()
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := d.Namespace
+	return func(pdb *PodDisruptionBudget) {
+		setNamespace(namespace, &pdb.ObjectMeta)
+		pdb.Spec.Selector = selector.DeepCopy()
+	}, nil
+}
+
+// PodDisruptionBudgetMinAvailable sets the pods that must stay available as an integer or a percentage.
+// It clears max unavailable since only one of them can be set
+func PodDisruptionBudgetMinAvailable(i intstr.IntOrString) PodDisruptionBudgetOpt {
+	return func(pdb *PodDisruptionBudget) {
+		pdb.Spec.MinAvailable = &i
+		pdb.Spec.MaxUnavailable = nil
+	}
+}
+
+// PodDisruptionBudgetMaxUnavailable sets the pods that can be unavailable as an integer or a percentage.
+// It clears min available since only one of them can be set
+func PodDisruptionBudgetMaxUnavailable(i intstr.IntOrString) PodDisruptionBudgetOpt {
+	return func(pdb *PodDisruptionBudget) {
+		pdb.Spec.MaxUnavailable = &i
+		pdb.Spec.MinAvailable = nil
+	}
+}
+
+// PodDisruptionBudgetUnhealthyPodEvictionPolicy sets when unhealthy pods can be evicted
+func PodDisruptionBudgetUnhealthyPodEvictionPolicy(p policyv1.UnhealthyPodEvictionPolicyType) PodDisruptionBudgetOpt {
+	return func(pdb *PodDisruptionBudget) {
+		pdb.Spec.UnhealthyPodEvictionPolicy = &p
+	}
+}
+
+// Apply applies the options to the pod disruption budget
+func (pdb *PodDisruptionBudget) Apply(opts ...PodDisruptionBudgetOpt) {
+	for _, opt := range opts {
+		opt(pdb)
+	}
+}
+
+// With returns a copy of the pod disruption budget with the options applied
+func (pdb PodDisruptionBudget) With(opts ...PodDisruptionBudgetOpt) PodDisruptionBudget {
+	clone := pdb.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the pod disruption budget
+func (pdb PodDisruptionBudget) Clone() PodDisruptionBudget {
+	return PodDisruptionBudget{PodDisruptionBudget: *pdb.PodDisruptionBudget.DeepCopy()}
+}
+
+// Validate checks the metadata, that the selector is set and that only one of minAvailable and maxUnavailable is set to a
+// non-negative number or a percentage
+func (pdb PodDisruptionBudget) Validate() error {
+	errs := validateObjectMeta(&pdb.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	if pdb.Spec.MinAvailable != nil && pdb.Spec.MaxUnavailable != nil {
+		errs = append(errs, field.Invalid(path, pdb.Spec, "minAvailable and maxUnavailable cannot both be set"))
+	}
+	errs = append(errs, validateIntOrPercent(pdb.Spec.MinAvailable, path.Child("minAvailable"))...)
+	errs = append(errs, validateIntOrPercent(pdb.Spec.MaxUnavailable, path.Child("maxUnavailable"))...)
+	errs = append(errs, validateRequiredSelector(pdb.Spec.Selector, path.Child("selector"))...)
+	errs = append(errs, validateEnum(ptrValue(pdb.Spec.UnhealthyPodEvictionPolicy), path.Child("unhealthyPodEvictionPolicy"),
+		policyv1.IfHealthyBudget, policyv1.AlwaysAllow)...)
+
+	return toAggregate(errs)
+}
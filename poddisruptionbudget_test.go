@@ -0,0 +1,139 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestNewDeploymentPodDisruptionBudget(t *testing.T) {
+	tests := []struct {
+		name       string
+		deployment *Deployment
+		selector   *metav1.LabelSelector
+		err        bool
+	}{
+		{
+			name:       "deployment selector",
+			deployment: NewDeployment("web", DeploymentNamespace("prod"), DeploymentSelector("app", "web")),
+			selector:   &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+		{
+			name:       "empty deployment selector",
+			deployment: NewDeployment("web", DeploymentNamespace("prod")),
+			err:        true,
+		},
+		{
+			name:       "nil deployment selector",
+			deployment: NewDeployment("web", func(d *Deployment) { d.Spec.Selector = nil }),
+			err:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdb, err := NewDeploymentPodDisruptionBudget(tt.deployment, PodDisruptionBudgetMinAvailable(intstr.FromInt(1)))
+			if (err != nil) != tt.err {
+				t.Fatalf("NewDeploymentPodDisruptionBudget() error = %v, want error %t", err, tt.err)
+			}
+			if tt.err {
+				return
+			}
+
+			if pdb.Name != "web" || pdb.Namespace != "prod" {
+				t.Errorf("pod disruption budget = %s/%s, want prod/web", pdb.Namespace, pdb.Name)
+			}
+			if !reflect.DeepEqual(pdb.Spec.Selector, tt.selector) {
+				t.Errorf("selector = %v, want %v", pdb.Spec.Selector, tt.selector)
+			}
+			if err := pdb.Validate(); err != nil {
+				t.Errorf("Validate() = %v", err)
+			}
+		})
+	}
+}
+
+func TestPodDisruptionBudgetDeploymentCopiesSelector(t *testing.T) {
+	d := NewDeployment("web", DeploymentSelector("app", "web"))
+	opt, err := PodDisruptionBudgetDeployment(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdb := NewPodDisruptionBudget("web", opt)
+	pdb.Spec.Selector.MatchLabels["app"] = "api"
+
+	if got := d.Spec.Selector.MatchLabels["app"]; got != "web" {
+		t.Errorf("deployment selector app = %q after changing the pod disruption budget, want web", got)
+	}
+	if got := NewPodDisruptionBudget("other", opt).Spec.Selector.MatchLabels["app"]; got != "web" {
+		t.Errorf("selector app = %q for a second pod disruption budget, want web", got)
+	}
+}
+
+func TestPodDisruptionBudgetValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []PodDisruptionBudgetOpt
+		fields []string
+	}{
+		{
+			name: "min available",
+			opts: []PodDisruptionBudgetOpt{PodDisruptionBudgetSelector("app", "web"), PodDisruptionBudgetMinAvailable(intstr.FromString("50%"))},
+		},
+		{
+			name:   "no selector",
+			opts:   []PodDisruptionBudgetOpt{PodDisruptionBudgetMaxUnavailable(intstr.FromInt(1))},
+			fields: []string{"spec.selector"},
+		},
+		{
+			name:   "empty selector",
+			opts:   []PodDisruptionBudgetOpt{func(pdb *PodDisruptionBudget) { pdb.Spec.Selector = &metav1.LabelSelector{} }},
+			fields: []string{"spec.selector"},
+		},
+		{
+			name: "both limits",
+			opts: []PodDisruptionBudgetOpt{
+				PodDisruptionBudgetSelector("app", "web"),
+				PodDisruptionBudgetMinAvailable(intstr.FromInt(1)),
+				func(pdb *PodDisruptionBudget) { pdb.Spec.MaxUnavailable = ptrTo(intstr.FromInt(1)) },
+			},
+			fields: []string{"spec"},
+		},
+		{
+			name:   "percentage over 100",
+			opts:   []PodDisruptionBudgetOpt{PodDisruptionBudgetSelector("app", "web"), PodDisruptionBudgetMaxUnavailable(intstr.FromString("150%"))},
+			fields: []string{"spec.maxUnavailable"},
+		},
+		{
+			name:   "unknown eviction policy",
+			opts:   []PodDisruptionBudgetOpt{PodDisruptionBudgetSelector("app", "web"), PodDisruptionBudgetUnhealthyPodEvictionPolicy("Never")},
+			fields: []string{"spec.unhealthyPodEvictionPolicy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, NewPodDisruptionBudget("web", tt.opts...).Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"slices"
+
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	monitoringv1 "github.com/CoverWhale/kopts/apis/monitoring/v1"
+)
+
+// PodMonitor holds a Prometheus Operator pod monitor
+type PodMonitor struct {
+	monitoringv1.PodMonitor
+}
+
+type PodMonitorOpt func(*PodMonitor)
+
+// NewPodMonitor returns a pod monitor with the given name and options
+func NewPodMonitor(name string, opts ...PodMonitorOpt) PodMonitor {
+	pm := PodMonitor{
+		PodMonitor: monitoringv1.PodMonitor{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "PodMonitor",
+				APIVersion: monitoringv1.APIVersion,
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&pm)
+	}
+
+	return pm
+}
+
+// PodMonitorNamespace sets the namespace for the pod monitor
+func PodMonitorNamespace(n string) PodMonitorOpt {
+	return func(pm *PodMonitor) {
+		setNamespace(n, &pm.ObjectMeta)
+	}
+}
+
+// PodMonitorLabel adds a label to the pod monitor. Prometheus selects monitors by their labels
+func PodMonitorLabel(key, value string) PodMonitorOpt {
+	return func(pm *PodMonitor) {
+		addLabel(key, value, &pm.ObjectMeta)
+	}
+}
+
+// PodMonitorSelector adds a label to the selector of the scraped pods
+func PodMonitorSelector(key, value string) PodMonitorOpt {
+	return func(pm *PodMonitor) {
+		metav1.AddLabelToSelector(&pm.Spec.Selector, key, value)
+	}
+}
+
+// PodMonitorNamespaces scrapes pods in the namespaces instead of the pod monitor namespace
+func PodMonitorNamespaces(namespaces ...string) PodMonitorOpt {
+	namespaces = slices.Clone(namespaces)
+	return func(pm *PodMonitor) {
+		pm.Spec.NamespaceSelector.MatchNames = slices.Clone(namespaces)
+	}
+}
+
+// PodMonitorJobLabel sets the pod label whose value is used as the job name
+func PodMonitorJobLabel(label string) PodMonitorOpt {
+	return func(pm *PodMonitor) {
+		pm.Spec.JobLabel = label
+	}
+}
+
+// PodMonitorEndpoint adds a scraped endpoint. The port is the name of a container port
+func PodMonitorEndpoint(e MetricsEndpoint) PodMonitorOpt {
+	return func(pm *PodMonitor) {
+		pm.Spec.PodMetricsEndpoints = append(pm.Spec.PodMetricsEndpoints, e.podMetricsEndpoint())
+	}
+}
+
+// PodMonitorDeployment scrapes the deployment pods. The namespace and selector are copied from the deployment,
+// and an endpoint is added for each named container port with the other settings of e.
+// Unnamed ports are skipped since endpoints refer to ports by name. Deployments without a selector return an
+// error, as an empty selector would scrape every pod in the namespace
+func PodMonitorDeployment(d *Deployment, e MetricsEndpoint) (PodMonitorOpt, error) {
+	selector, err := d.podSelector()
+	if err != nil {
+		return nil, err
+	}
+	namespace := d.Namespace
+
+	var endpoints []monitoringv1.PodMetricsEndpoint
+	for _, c := range d.Spec.Template.Spec.Containers {
+		for _, p := range c.Ports {
+			if p.Name == "" {
+				continue
+			}
+			e.Port = p.Name
+			endpoints = append(endpoints, e.podMetricsEndpoint())
+		}
+	}
+
+	return func(pm *PodMonitor) {
+		setNamespace(namespace, &pm.ObjectMeta)
+		pm.Spec.Selector = *selector.DeepCopy()
+		pm.Spec.PodMetricsEndpoints = append(pm.Spec.PodMetricsEndpoints, endpoints...)
+	}, nil
+}
+
+// Apply applies the options to the pod monitor
+func (pm *PodMonitor) Apply(opts ...PodMonitorOpt) {
+	for _, opt := range opts {
+		opt(pm)
+	}
+}
+
+// With returns a copy of the pod monitor with the options applied
+func (pm PodMonitor) With(opts ...PodMonitorOpt) PodMonitor {
+	clone := pm.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the pod monitor
+func (pm PodMonitor) Clone() PodMonitor {
+	return PodMonitor{PodMonitor: deepCopyJSON(pm.PodMonitor)}
+}
+
+// Validate checks the metadata, the selector and that every endpoint names a port and has a valid scheme, path
+// and scrape interval and timeout
+func (pm PodMonitor) Validate() error {
+	errs := validateObjectMeta(&pm.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateMonitorSelectors(pm.Spec.Selector, pm.Spec.NamespaceSelector, path)...)
+	if len(pm.Spec.PodMetricsEndpoints) == 0 {
+		errs = append(errs, field.Required(path.Child("podMetricsEndpoints"), "at least one endpoint is required"))
+	}
+	for i, e := range pm.Spec.PodMetricsEndpoints {
+		errs = append(errs, validateScrapeEndpoint(e.Port, e.Path, e.Scheme, e.Interval, e.ScrapeTimeout, path.Child("podMetricsEndpoints").Index(i))...)
+	}
+
+	return toAggregate(errs)
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPodMonitorDeployment(t *testing.T) {
+	d := NewDeployment("web",
+		DeploymentNamespace("apps"),
+		DeploymentSelector("app", "web"),
+		DeploymentPodSpec(NewPodSpec("web", PodContainer(NewContainer("web",
+			ContainerImage("nginx"),
+			ContainerPort("http", 8080),
+			ContainerPort("metrics", 9090),
+			ContainerPort("", 8443),
+		)))),
+	)
+
+	opt, err := PodMonitorDeployment(d, MetricsEndpoint{Path: "/metrics"})
+	if err != nil {
+		t.Fatalf("PodMonitorDeployment() = %v", err)
+	}
+	pm := NewPodMonitor("web", opt)
+
+	if pm.Namespace != "apps" {
+		t.Errorf("namespace = %q, want apps", pm.Namespace)
+	}
+	if want := map[string]string{"app": "web"}; !reflect.DeepEqual(pm.Spec.Selector.MatchLabels, want) {
+		t.Errorf("selector = %v, want %v", pm.Spec.Selector.MatchLabels, want)
+	}
+	var ports []string
+	for _, e := range pm.Spec.PodMetricsEndpoints {
+		ports = append(ports, e.Port)
+	}
+	if want := []string{"http", "metrics"}; !reflect.DeepEqual(ports, want) {
+		t.Errorf("endpoint ports = %v, want the named ports %v", ports, want)
+	}
+	if err := pm.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	pm.Spec.Selector.MatchLabels["app"] = "changed"
+	if got := d.Spec.Selector.MatchLabels["app"]; got != "web" {
+		t.Errorf("deployment selector app = %q, want web", got)
+	}
+	if got := NewPodMonitor("second", opt).Spec.Selector.MatchLabels["app"]; got != "web" {
+		t.Errorf("selector app = %q for a second monitor, want web", got)
+	}
+}
+
+func TestPodMonitorDeploymentWithoutSelector(t *testing.T) {
+	d := NewDeployment("web", DeploymentNamespace("apps"))
+	d.Spec.Selector.MatchLabels = nil
+
+	if _, err := PodMonitorDeployment(d, MetricsEndpoint{}); err == nil {
+		t.Error("PodMonitorDeployment() without a selector = nil, want an error")
+	}
+}
+
+func TestPodMonitorValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []PodMonitorOpt
+		fields []string
+	}{
+		{
+			name:   "valid",
+			opts:   []PodMonitorOpt{PodMonitorSelector("app", "web"), PodMonitorEndpoint(MetricsEndpoint{Port: "metrics"})},
+			fields: nil,
+		},
+		{
+			name:   "without endpoints",
+			opts:   []PodMonitorOpt{PodMonitorSelector("app", "web")},
+			fields: []string{"spec.podMetricsEndpoints"},
+		},
+		{
+			name:   "invalid namespace",
+			opts:   []PodMonitorOpt{PodMonitorEndpoint(MetricsEndpoint{Port: "metrics"}), PodMonitorNamespaces("Apps")},
+			fields: []string{"spec.namespaceSelector.matchNames[0]"},
+		},
+		{
+			name:   "invalid selector",
+			opts:   []PodMonitorOpt{PodMonitorEndpoint(MetricsEndpoint{Port: "metrics"}), PodMonitorSelector("app", "web app")},
+			fields: []string{"spec.selector.matchLabels"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pm := NewPodMonitor("web", append([]PodMonitorOpt{PodMonitorNamespace("apps")}, tt.opts...)...)
+			fields := validationFields(t, pm.Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ package kopts
 
 import (
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 type Verb string
@@ -132,3 +133,106 @@ func policyRuleNonResourceURLs(nru ...string) PolicyRuleOpt {
 		pr.NonResourceURLs = nru
 	}
 }
+
+// Apply applies the options to the policy rule
+func (pr *PolicyRule) Apply(opts ...PolicyRuleOpt) {
+	for _, opt := range opts {
+		opt(pr)
+	}
+}
+
+// With returns a copy of the policy rule with the options applied
+func (pr PolicyRule) With(opts ...PolicyRuleOpt) PolicyRule {
+	clone := pr.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the policy rule
+func (pr PolicyRule) Clone() PolicyRule {
+	return PolicyRule{PolicyRule: *pr.PolicyRule.DeepCopy()}
+}
+
+// Validate checks that the rule has verbs and applies to either resources or non-resource URLs
+func (pr PolicyRule) Validate() error {
+	return toAggregate(validatePolicyRule(pr.PolicyRule, false, nil))
+}
+
+func validatePolicyRule(r rbacv1.PolicyRule, namespaced bool, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if len(r.Verbs) == 0 {
+		errs = append(errs, field.Required(path.Child("verbs"), "verbs must contain at least one value"))
+	}
+
+	if len(r.NonResourceURLs) > 0 {
+		if namespaced {
+			errs = append(errs, field.Invalid(path.Child("nonResourceURLs"), r.NonResourceURLs, "namespaced rules cannot apply to non-resource URLs"))
+		}
+		if len(r.APIGroups) > 0 || len(r.Resources) > 0 || len(r.ResourceNames) > 0 {
+			errs = append(errs, field.Invalid(path.Child("nonResourceURLs"), r.NonResourceURLs, "rules cannot apply to both regular resources and non-resource URLs"))
+		}
+		return errs
+	}
+
+	if len(r.APIGroups) == 0 {
+		errs = append(errs, field.Required(path.Child("apiGroups"), "resource rules must supply at least one api group"))
+	}
+	if len(r.Resources) == 0 {
+		errs = append(errs, field.Required(path.Child("resources"), "resource rules must supply at least one resource"))
+	}
+
+	return errs
+}
+
+// validateRoleRef checks that the role reference has a name and refers to one of the kinds
+func validateRoleRef(ref rbacv1.RoleRef, path *field.Path, kinds ...string) field.ErrorList {
+	var errs field.ErrorList
+
+	if ref.APIGroup != rbacv1.GroupName {
+		errs = append(errs, field.NotSupported(path.Child("apiGroup"), ref.APIGroup, []string{rbacv1.GroupName}))
+	}
+	if ref.Kind == "" {
+		errs = append(errs, field.Required(path.Child("kind"), ""))
+	} else {
+		errs = append(errs, validateEnum(ref.Kind, path.Child("kind"), kinds...)...)
+	}
+	if ref.Name == "" {
+		errs = append(errs, field.Required(path.Child("name"), ""))
+	}
+
+	return errs
+}
+
+// validateSubject checks the subject kind, name and API group. Service account subjects of cluster role bindings
+// must set a namespace
+func validateSubject(s rbacv1.Subject, namespaced bool, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if s.Name == "" {
+		errs = append(errs, field.Required(path.Child("name"), ""))
+	}
+
+	switch s.Kind {
+	case rbacv1.ServiceAccountKind:
+		if s.APIGroup != "" {
+			errs = append(errs, field.NotSupported(path.Child("apiGroup"), s.APIGroup, []string{""}))
+		}
+		if s.Name != "" {
+			errs = append(errs, validateDNSSubdomain(s.Name, path.Child("name"))...)
+		}
+		if !namespaced && s.Namespace == "" {
+			errs = append(errs, field.Required(path.Child("namespace"), ""))
+		}
+	case rbacv1.UserKind, rbacv1.GroupKind:
+		if s.APIGroup != rbacv1.GroupName {
+			errs = append(errs, field.NotSupported(path.Child("apiGroup"), s.APIGroup, []string{rbacv1.GroupName}))
+		}
+	default:
+		errs = append(errs, field.NotSupported(path.Child("kind"), s.Kind,
+			[]string{rbacv1.ServiceAccountKind, rbacv1.UserKind, rbacv1.GroupKind}))
+	}
+
+	return errs
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// highestUserDefinablePriority is the highest priority value for priority classes other than the system ones
+const highestUserDefinablePriority = 1000000000
+
+// PriorityClass holds a Kubernetes priority class
+type PriorityClass struct {
+	schedulingv1.PriorityClass
+}
+
+type PriorityClassOpt func(*PriorityClass)
+
+// NewPriorityClass returns a priority class with the given name and options
+func NewPriorityClass(name string, opts ...PriorityClassOpt) PriorityClass {
+	pc := PriorityClass{
+		PriorityClass: schedulingv1.PriorityClass{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "PriorityClass",
+				APIVersion: "scheduling.k8s.io/v1",
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&pc)
+	}
+
+	return pc
+}
+
+// PriorityClassValue sets the priority of pods using the class. Higher values are scheduled first
+func PriorityClassValue(i int) PriorityClassOpt {
+	value := int32(i)
+	return func(pc *PriorityClass) {
+		pc.Value = value
+	}
+}
+
+// PriorityClassGlobalDefault sets whether the class is used for pods without a priority class
+func PriorityClassGlobalDefault(b bool) PriorityClassOpt {
+	return func(pc *PriorityClass) {
+		pc.GlobalDefault = b
+	}
+}
+
+// PriorityClassPreemptionPolicy sets whether pods using the class can preempt lower priority pods
+func PriorityClassPreemptionPolicy(p corev1.PreemptionPolicy) PriorityClassOpt {
+	return func(pc *PriorityClass) {
+		pc.PreemptionPolicy = ptrTo(p)
+	}
+}
+
+// PriorityClassDescription sets the description of when the class should be used
+func PriorityClassDescription(d string) PriorityClassOpt {
+	return func(pc *PriorityClass) {
+		pc.Description = d
+	}
+}
+
+// Apply applies the options to the priority class
+func (pc *PriorityClass) Apply(opts ...PriorityClassOpt) {
+	for _, opt := range opts {
+		opt(pc)
+	}
+}
+
+// With returns a copy of the priority class with the options applied
+func (pc PriorityClass) With(opts ...PriorityClassOpt) PriorityClass {
+	clone := pc.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the priority class
+func (pc PriorityClass) Clone() PriorityClass {
+	return PriorityClass{PriorityClass: *pc.PriorityClass.DeepCopy()}
+}
+
+// Validate checks the metadata, that the value is not above the highest user definable priority and the
+// preemption policy. The system- prefix is reserved for the built in priority classes
+func (pc PriorityClass) Validate() error {
+	errs := validateObjectMeta(&pc.ObjectMeta, false, apimachineryvalidation.NameIsDNSSubdomain)
+
+	if strings.HasPrefix(pc.Name, "system-") {
+		errs = append(errs, field.Forbidden(field.NewPath("metadata", "name"), "the system- prefix is reserved"))
+	}
+	if pc.Value > highestUserDefinablePriority {
+		errs = append(errs, field.Invalid(field.NewPath("value"), pc.Value,
+			fmt.Sprintf("must not be greater than %d", highestUserDefinablePriority)))
+	}
+	errs = append(errs, validateEnum(ptrValue(pc.PreemptionPolicy), field.NewPath("preemptionPolicy"),
+		corev1.PreemptLowerPriority, corev1.PreemptNever)...)
+
+	return toAggregate(errs)
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPodPriorityClass(t *testing.T) {
+	critical := NewPriorityClass("critical",
+		PriorityClassValue(100000),
+		PriorityClassPreemptionPolicy(corev1.PreemptLowerPriority),
+		PriorityClassDescription("Customer facing services"),
+	)
+	batch := NewPriorityClass("batch", PriorityClassValue(100), PriorityClassPreemptionPolicy(corev1.PreemptNever))
+	web := NewContainer("web", ContainerImage("nginx"))
+
+	d := NewDeployment("web", DeploymentSelector("app", "web"),
+		DeploymentPodSpec(NewPodSpec("web", PodLabel("app", "web"), PodContainer(web), PodPriorityClass(critical))))
+	c := NewCronJob("report", CronJobSchedule("0 * * * *"),
+		CronJobPodSpec(NewPodSpec("report", PodContainer(web), PodPriorityClass(batch))))
+
+	if got := d.Spec.Template.Spec.PriorityClassName; got != "critical" {
+		t.Errorf("deployment priorityClassName = %q, want critical", got)
+	}
+	if got := c.Spec.JobTemplate.Spec.Template.Spec.PriorityClassName; got != "batch" {
+		t.Errorf("cron job priorityClassName = %q, want batch", got)
+	}
+}
+
+func TestPriorityClassValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		pc     PriorityClass
+		fields []string
+	}{
+		{
+			name: "global default",
+			pc:   NewPriorityClass("default", PriorityClassValue(1000), PriorityClassGlobalDefault(true)),
+		},
+		{
+			name:   "reserved prefix",
+			pc:     NewPriorityClass("system-critical", PriorityClassValue(1000)),
+			fields: []string{"metadata.name"},
+		},
+		{
+			name:   "value above the user definable range",
+			pc:     NewPriorityClass("critical", PriorityClassValue(highestUserDefinablePriority+1)),
+			fields: []string{"value"},
+		},
+		{
+			name:   "unknown preemption policy",
+			pc:     NewPriorityClass("critical", PriorityClassPreemptionPolicy("Sometimes")),
+			fields: []string{"preemptionPolicy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, tt.pc.Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"maps"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	monitoringv1 "github.com/CoverWhale/kopts/apis/monitoring/v1"
+)
+
+// PrometheusRule holds Prometheus Operator alerting and recording rules
+type PrometheusRule struct {
+	monitoringv1.PrometheusRule
+}
+
+type PrometheusRuleOpt func(*PrometheusRule)
+
+// RuleGroup holds a group of rules evaluated at the same interval
+type RuleGroup struct {
+	monitoringv1.RuleGroup
+}
+
+type RuleGroupOpt func(*RuleGroup)
+
+// AlertRule holds an alerting rule. The alert fires when the expression returns results for the For duration
+type AlertRule struct {
+	Name        string
+	Expr        string
+	For         time.Duration
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// RecordingRule holds a recording rule storing the expression result as a new series named Name
+type RecordingRule struct {
+	Name   string
+	Expr   string
+	Labels map[string]string
+}
+
+// NewPrometheusRule returns a prometheus rule with the given name and options.
+// Use Validate to check the rule expressions before applying it
+func NewPrometheusRule(name string, opts ...PrometheusRuleOpt) PrometheusRule {
+	pr := PrometheusRule{
+		PrometheusRule: monitoringv1.PrometheusRule{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "PrometheusRule",
+				APIVersion: monitoringv1.APIVersion,
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&pr)
+	}
+
+	return pr
+}
+
+// PrometheusRuleNamespace sets the namespace for the prometheus rule
+func PrometheusRuleNamespace(n string) PrometheusRuleOpt {
+	return func(pr *PrometheusRule) {
+		setNamespace(n, &pr.ObjectMeta)
+	}
+}
+
+// PrometheusRuleLabel adds a label to the prometheus rule. Prometheus selects rules by their labels
+func PrometheusRuleLabel(key, value string) PrometheusRuleOpt {
+	return func(pr *PrometheusRule) {
+		addLabel(key, value, &pr.ObjectMeta)
+	}
+}
+
+// PrometheusRuleGroup adds a rule group
+func PrometheusRuleGroup(g RuleGroup) PrometheusRuleOpt {
+	return func(pr *PrometheusRule) {
+		pr.Spec.Groups = append(pr.Spec.Groups, deepCopyJSON(g.RuleGroup))
+	}
+}
+
+// Apply applies the options to the prometheus rule
+func (pr *PrometheusRule) Apply(opts ...PrometheusRuleOpt) {
+	for _, opt := range opts {
+		opt(pr)
+	}
+}
+
+// With returns a copy of the prometheus rule with the options applied
+func (pr PrometheusRule) With(opts ...PrometheusRuleOpt) PrometheusRule {
+	clone := pr.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the prometheus rule
+func (pr PrometheusRule) Clone() PrometheusRule {
+	return PrometheusRule{PrometheusRule: deepCopyJSON(pr.PrometheusRule)}
+}
+
+// Validate checks the metadata, that the rule groups have unique names and that every rule has a name and
+// an expression that parses as PromQL
+func (pr PrometheusRule) Validate() error {
+	errs := validateObjectMeta(&pr.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	groups := make(map[string]bool)
+	path := field.NewPath("spec", "groups")
+
+	for i, g := range pr.Spec.Groups {
+		groupPath := path.Index(i)
+		switch {
+		case g.Name == "":
+			errs = append(errs, field.Required(groupPath.Child("name"), "rule groups must have a name"))
+		case groups[g.Name]:
+			errs = append(errs, field.Duplicate(groupPath.Child("name"), g.Name))
+		}
+		groups[g.Name] = true
+
+		for j, r := range g.Rules {
+			errs = append(errs, validateRule(r, groupPath.Child("rules").Index(j))...)
+		}
+	}
+
+	return toAggregate(errs)
+}
+
+func validateRule(r monitoringv1.Rule, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	switch {
+	case r.Alert == "" && r.Record == "":
+		errs = append(errs, field.Required(path, "one of alert or record must be set"))
+	case r.Alert != "" && r.Record != "":
+		errs = append(errs, field.Forbidden(path, "only one of alert or record can be set"))
+	case r.Record != "" && !model.IsValidMetricName(model.LabelValue(r.Record)):
+		errs = append(errs, field.Invalid(path.Child("record"), r.Record, "must be a valid metric name"))
+	}
+
+	if r.Expr == "" {
+		return append(errs, field.Required(path.Child("expr"), ""))
+	}
+
+	if _, err := parser.ParseExpr(r.Expr); err != nil {
+		errs = append(errs, field.Invalid(path.Child("expr"), r.Expr, err.Error()))
+	}
+
+	return errs
+}
+
+// NewRuleGroup returns a rule group with the given name and options
+func NewRuleGroup(name string, opts ...RuleGroupOpt) RuleGroup {
+	g := RuleGroup{
+		RuleGroup: monitoringv1.RuleGroup{
+			Name: name,
+		},
+	}
+
+	for _, v := range opts {
+		v(&g)
+	}
+
+	return g
+}
+
+// RuleGroupInterval sets how often the rules of the group are evaluated
+func RuleGroupInterval(d time.Duration) RuleGroupOpt {
+	return func(g *RuleGroup) {
+		g.Interval = ptrTo(monitoringDuration(d))
+	}
+}
+
+// RuleGroupAlertRule adds an alerting rule
+func RuleGroupAlertRule(a AlertRule) RuleGroupOpt {
+	labels := maps.Clone(a.Labels)
+	annotations := maps.Clone(a.Annotations)
+
+	return func(g *RuleGroup) {
+		r := monitoringv1.Rule{
+			Alert:       a.Name,
+			Expr:        a.Expr,
+			Labels:      maps.Clone(labels),
+			Annotations: maps.Clone(annotations),
+		}
+		if a.For > 0 {
+			r.For = ptrTo(monitoringDuration(a.For))
+		}
+
+		g.Rules = append(g.Rules, r)
+	}
+}
+
+// RuleGroupRecordingRule adds a recording rule
+func RuleGroupRecordingRule(rr RecordingRule) RuleGroupOpt {
+	labels := maps.Clone(rr.Labels)
+
+	return func(g *RuleGroup) {
+		g.Rules = append(g.Rules, monitoringv1.Rule{
+			Record: rr.Name,
+			Expr:   rr.Expr,
+			Labels: maps.Clone(labels),
+		})
+	}
+}
+
+// Apply applies the options to the rule group
+func (g *RuleGroup) Apply(opts ...RuleGroupOpt) {
+	for _, opt := range opts {
+		opt(g)
+	}
+}
+
+// With returns a copy of the rule group with the options applied
+func (g RuleGroup) With(opts ...RuleGroupOpt) RuleGroup {
+	clone := g.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the rule group
+func (g RuleGroup) Clone() RuleGroup {
+	return RuleGroup{RuleGroup: deepCopyJSON(g.RuleGroup)}
+}
+
+// Validate checks that the group has a name and that every rule has a name and an expression that parses as PromQL
+func (g RuleGroup) Validate() error {
+	var errs field.ErrorList
+
+	if g.Name == "" {
+		errs = append(errs, field.Required(field.NewPath("name"), "rule groups must have a name"))
+	}
+	for i, r := range g.Rules {
+		errs = append(errs, validateRule(r, field.NewPath("rules").Index(i))...)
+	}
+
+	return toAggregate(errs)
+}
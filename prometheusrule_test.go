@@ -0,0 +1,126 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPrometheusRule(t *testing.T) {
+	labels := map[string]string{"severity": "page"}
+	alert := RuleGroupAlertRule(AlertRule{
+		Name:        "HighErrorRate",
+		Expr:        `sum(rate(http_requests_total{code=~"5.."}[5m])) / sum(rate(http_requests_total[5m])) > 0.05`,
+		For:         10 * time.Minute,
+		Labels:      labels,
+		Annotations: map[string]string{"summary": "more than 5% of requests fail"},
+	})
+	g := NewRuleGroup("web",
+		RuleGroupInterval(time.Minute),
+		RuleGroupRecordingRule(RecordingRule{Name: "job:http_requests:rate5m", Expr: "sum by (job) (rate(http_requests_total[5m]))"}),
+		alert,
+	)
+	labels["severity"] = "ticket"
+	pr := NewPrometheusRule("web", PrometheusRuleNamespace("apps"), PrometheusRuleGroup(g))
+
+	if got := ptrValue(g.Interval); got != "1m" {
+		t.Errorf("interval = %q, want 1m", got)
+	}
+	r := g.Rules[1]
+	if got := ptrValue(r.For); got != "10m" {
+		t.Errorf("for = %q, want 10m", got)
+	}
+	if r.Labels["severity"] != "page" {
+		t.Errorf("labels = %v after changing the caller's map, want severity page", r.Labels)
+	}
+	if err := pr.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	*r.For = "1h"
+	r.Labels["severity"] = "changed"
+	second := NewRuleGroup("second", alert).Rules[0]
+	if ptrValue(second.For) != "10m" || second.Labels["severity"] != "page" {
+		t.Errorf("rule = %+v for a second group, want the original duration and labels", second)
+	}
+
+	pr.Spec.Groups[0].Rules[0].Expr = "changed"
+	if g.Rules[0].Expr == "changed" {
+		t.Error("changing the prometheus rule changed the rule group it was built from")
+	}
+}
+
+func TestPrometheusRuleValidate(t *testing.T) {
+	valid := NewRuleGroup("web", RuleGroupRecordingRule(RecordingRule{Name: "job:up:sum", Expr: "sum by (job) (up)"}))
+
+	tests := []struct {
+		name   string
+		groups []RuleGroup
+		fields []string
+	}{
+		{
+			name:   "valid",
+			groups: []RuleGroup{valid},
+			fields: nil,
+		},
+		{
+			name:   "duplicate group",
+			groups: []RuleGroup{valid, valid},
+			fields: []string{"spec.groups[1].name"},
+		},
+		{
+			name:   "unnamed group",
+			groups: []RuleGroup{NewRuleGroup("")},
+			fields: []string{"spec.groups[0].name"},
+		},
+		{
+			name:   "invalid expression",
+			groups: []RuleGroup{NewRuleGroup("web", RuleGroupAlertRule(AlertRule{Name: "Down", Expr: "up == "}))},
+			fields: []string{"spec.groups[0].rules[0].expr"},
+		},
+		{
+			name:   "invalid metric name",
+			groups: []RuleGroup{NewRuleGroup("web", RuleGroupRecordingRule(RecordingRule{Name: "job-up", Expr: "up"}))},
+			fields: []string{"spec.groups[0].rules[0].record"},
+		},
+		{
+			name: "alert and record",
+			groups: []RuleGroup{NewRuleGroup("web", RuleGroupAlertRule(AlertRule{Name: "Down", Expr: "up == 0"}), func(g *RuleGroup) {
+				g.Rules[0].Record = "job:up"
+			})},
+			fields: []string{"spec.groups[0].rules[0]"},
+		},
+		{
+			name:   "without expression",
+			groups: []RuleGroup{NewRuleGroup("web", RuleGroupAlertRule(AlertRule{Name: "Down"}))},
+			fields: []string{"spec.groups[0].rules[0].expr"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []PrometheusRuleOpt{PrometheusRuleNamespace("apps")}
+			for _, g := range tt.groups {
+				opts = append(opts, PrometheusRuleGroup(g))
+			}
+			fields := validationFields(t, NewPrometheusRule("web", opts...).Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	gatewayv1 "github.com/CoverWhale/kopts/apis/gateway/v1"
+	gatewayv1beta1 "github.com/CoverWhale/kopts/apis/gateway/v1beta1"
+)
+
+// ReferenceGrant holds a Gateway API reference grant
+type ReferenceGrant struct {
+	gatewayv1beta1.ReferenceGrant
+}
+
+type ReferenceGrantOpt func(*ReferenceGrant)
+
+// NewReferenceGrant returns a reference grant with the given name and options.
+// The grant must be in the namespace of the objects it allows references to
+func NewReferenceGrant(name string, opts ...ReferenceGrantOpt) ReferenceGrant {
+	rg := ReferenceGrant{
+		ReferenceGrant: gatewayv1beta1.ReferenceGrant{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ReferenceGrant",
+				APIVersion: gatewayv1beta1.APIVersion,
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&rg)
+	}
+
+	return rg
+}
+
+// ReferenceGrantNamespace sets the namespace for the reference grant
+func ReferenceGrantNamespace(n string) ReferenceGrantOpt {
+	return func(rg *ReferenceGrant) {
+		setNamespace(n, &rg.ObjectMeta)
+	}
+}
+
+// ReferenceGrantFrom trusts references from objects of the group and kind in the namespace
+func ReferenceGrantFrom(group, kind, namespace string) ReferenceGrantOpt {
+	return func(rg *ReferenceGrant) {
+		rg.Spec.From = append(rg.Spec.From, gatewayv1beta1.ReferenceGrantFrom{
+			Group:     group,
+			Kind:      kind,
+			Namespace: namespace,
+		})
+	}
+}
+
+// ReferenceGrantFromHTTPRoute trusts references from HTTP routes in the namespace of the route
+func ReferenceGrantFromHTTPRoute(r HTTPRoute) ReferenceGrantOpt {
+	return ReferenceGrantFrom(gatewayv1.GroupName, "HTTPRoute", r.Namespace)
+}
+
+// ReferenceGrantFromGRPCRoute trusts references from gRPC routes in the namespace of the route
+func ReferenceGrantFromGRPCRoute(r GRPCRoute) ReferenceGrantOpt {
+	return ReferenceGrantFrom(gatewayv1.GroupName, "GRPCRoute", r.Namespace)
+}
+
+// ReferenceGrantFromGateway trusts references from gateways in the namespace of the gateway
+func ReferenceGrantFromGateway(g Gateway) ReferenceGrantOpt {
+	return ReferenceGrantFrom(gatewayv1.GroupName, "Gateway", g.Namespace)
+}
+
+// ReferenceGrantTo allows references to all objects of the group and kind in the grant namespace
+func ReferenceGrantTo(group, kind string) ReferenceGrantOpt {
+	return func(rg *ReferenceGrant) {
+		rg.Spec.To = append(rg.Spec.To, gatewayv1beta1.ReferenceGrantTo{
+			Group: group,
+			Kind:  kind,
+		})
+	}
+}
+
+// ReferenceGrantToService allows references to the service. The grant is placed in the service namespace
+func ReferenceGrantToService(s Service) ReferenceGrantOpt {
+	return referenceGrantToObject("Service", s.ObjectMeta)
+}
+
+// ReferenceGrantToSecret allows references to the secret. The grant is placed in the secret namespace
+func ReferenceGrantToSecret(s Secret) ReferenceGrantOpt {
+	return referenceGrantToObject("Secret", s.ObjectMeta)
+}
+
+func referenceGrantToObject(kind string, m metav1.ObjectMeta) ReferenceGrantOpt {
+	name := m.Name
+	namespace := m.Namespace
+	return func(rg *ReferenceGrant) {
+		setNamespace(namespace, &rg.ObjectMeta)
+		rg.Spec.To = append(rg.Spec.To, gatewayv1beta1.ReferenceGrantTo{
+			Kind: kind,
+			Name: ptrTo(name),
+		})
+	}
+}
+
+// Apply applies the options to the reference grant
+func (rg *ReferenceGrant) Apply(opts ...ReferenceGrantOpt) {
+	for _, opt := range opts {
+		opt(rg)
+	}
+}
+
+// With returns a copy of the reference grant with the options applied
+func (rg ReferenceGrant) With(opts ...ReferenceGrantOpt) ReferenceGrant {
+	clone := rg.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the reference grant
+func (rg ReferenceGrant) Clone() ReferenceGrant {
+	return ReferenceGrant{ReferenceGrant: deepCopyJSON(rg.ReferenceGrant)}
+}
+
+// Validate checks the metadata and that the grant has at least one referrer and one target, each with a kind and
+// referrers with a namespace
+func (rg ReferenceGrant) Validate() error {
+	errs := validateObjectMeta(&rg.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	if len(rg.Spec.From) == 0 {
+		errs = append(errs, field.Required(path.Child("from"), "at least one referrer is required"))
+	}
+	for i, f := range rg.Spec.From {
+		fromPath := path.Child("from").Index(i)
+		if f.Kind == "" {
+			errs = append(errs, field.Required(fromPath.Child("kind"), ""))
+		}
+		errs = append(errs, validateDNSLabel(f.Namespace, fromPath.Child("namespace"))...)
+	}
+
+	if len(rg.Spec.To) == 0 {
+		errs = append(errs, field.Required(path.Child("to"), "at least one target is required"))
+	}
+	for i, t := range rg.Spec.To {
+		if t.Kind == "" {
+			errs = append(errs, field.Required(path.Child("to").Index(i).Child("kind"), ""))
+		}
+	}
+
+	return toAggregate(errs)
+}
@@ -0,0 +1,193 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ResourceQuota holds a Kubernetes resource quota
+type ResourceQuota struct {
+	corev1.ResourceQuota
+}
+
+type ResourceQuotaOpt func(*ResourceQuota)
+
+// NewResourceQuota returns a resource quota with the given name and options
+func NewResourceQuota(name string, opts ...ResourceQuotaOpt) ResourceQuota {
+	rq := ResourceQuota{
+		ResourceQuota: corev1.ResourceQuota{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ResourceQuota",
+				APIVersion: "v1",
+			},
+			ObjectMeta: newObjectMeta(name),
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: corev1.ResourceList{},
+			},
+		},
+	}
+
+	for _, v := range opts {
+		v(&rq)
+	}
+
+	return rq
+}
+
+// ResourceQuotaNamespace sets the namespace for the resource quota
+func ResourceQuotaNamespace(n string) ResourceQuotaOpt {
+	return func(rq *ResourceQuota) {
+		setNamespace(n, &rq.ObjectMeta)
+	}
+}
+
+// ResourceQuotaHard sets the hard limit for a resource such as corev1.ResourceRequestsCPU
+func ResourceQuotaHard(r corev1.ResourceName, q resource.Quantity) ResourceQuotaOpt {
+	return func(rq *ResourceQuota) {
+		if rq.Spec.Hard == nil {
+			rq.Spec.Hard = make(corev1.ResourceList)
+		}
+		rq.Spec.Hard[r] = q.DeepCopy()
+	}
+}
+
+// ResourceQuotaCompute sets the hard limits for the CPU and memory requests and limits
+func ResourceQuotaCompute(requestsCPU, requestsMemory, limitsCPU, limitsMemory resource.Quantity) ResourceQuotaOpt {
+	return resourceQuotaHardLimits(corev1.ResourceList{
+		corev1.ResourceRequestsCPU:    requestsCPU,
+		corev1.ResourceRequestsMemory: requestsMemory,
+		corev1.ResourceLimitsCPU:      limitsCPU,
+		corev1.ResourceLimitsMemory:   limitsMemory,
+	})
+}
+
+// ResourceQuotaStorage sets the hard limits for the total requested storage and the number of claims
+func ResourceQuotaStorage(storage resource.Quantity, claims int) ResourceQuotaOpt {
+	return resourceQuotaHardLimits(corev1.ResourceList{
+		corev1.ResourceRequestsStorage:        storage,
+		corev1.ResourcePersistentVolumeClaims: *resource.NewQuantity(int64(claims), resource.DecimalSI),
+	})
+}
+
+func resourceQuotaHardLimits(limits corev1.ResourceList) ResourceQuotaOpt {
+	return func(rq *ResourceQuota) {
+		for r, q := range limits {
+			ResourceQuotaHard(r, q)(rq)
+		}
+	}
+}
+
+// ResourceQuotaStorageClass sets the hard limit for the requested storage of the storage class
+func ResourceQuotaStorageClass(sc StorageClass, storage resource.Quantity) ResourceQuotaOpt {
+	name := corev1.ResourceName(fmt.Sprintf("%s.storageclass.storage.k8s.io/%s", sc.Name, corev1.ResourceRequestsStorage))
+	return ResourceQuotaHard(name, storage)
+}
+
+// ResourceQuotaObjectCount sets the hard limit for the number of objects of a resource such as "deployments.apps"
+func ResourceQuotaObjectCount(r string, count int) ResourceQuotaOpt {
+	name := corev1.ResourceName(fmt.Sprintf("count/%s", r))
+	return ResourceQuotaHard(name, *resource.NewQuantity(int64(count), resource.DecimalSI))
+}
+
+// ResourceQuotaScopes sets the scopes a resource must match to be tracked by the quota
+func ResourceQuotaScopes(scopes ...corev1.ResourceQuotaScope) ResourceQuotaOpt {
+	return func(rq *ResourceQuota) {
+		rq.Spec.Scopes = slices.Clone(scopes)
+	}
+}
+
+// ResourceQuotaScopeSelector adds a scope selector expression
+func ResourceQuotaScopeSelector(scope corev1.ResourceQuotaScope, operator corev1.ScopeSelectorOperator, values ...string) ResourceQuotaOpt {
+	return func(rq *ResourceQuota) {
+		if rq.Spec.ScopeSelector == nil {
+			rq.Spec.ScopeSelector = &corev1.ScopeSelector{}
+		}
+		rq.Spec.ScopeSelector.MatchExpressions = append(rq.Spec.ScopeSelector.MatchExpressions, corev1.ScopedResourceSelectorRequirement{
+			ScopeName: scope,
+			Operator:  operator,
+			Values:    slices.Clone(values),
+		})
+	}
+}
+
+// Apply applies the options to the resource quota
+func (rq *ResourceQuota) Apply(opts ...ResourceQuotaOpt) {
+	for _, opt := range opts {
+		opt(rq)
+	}
+}
+
+// With returns a copy of the resource quota with the options applied
+func (rq ResourceQuota) With(opts ...ResourceQuotaOpt) ResourceQuota {
+	clone := rq.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the resource quota
+func (rq ResourceQuota) Clone() ResourceQuota {
+	return ResourceQuota{ResourceQuota: *rq.ResourceQuota.DeepCopy()}
+}
+
+// Validate checks the metadata, that the hard limits are not negative and the scopes
+func (rq ResourceQuota) Validate() error {
+	errs := validateObjectMeta(&rq.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateResourceList(rq.Spec.Hard, path.Child("hard"))...)
+	for i, s := range rq.Spec.Scopes {
+		errs = append(errs, validateEnum(s, path.Child("scopes").Index(i), resourceQuotaScopes...)...)
+	}
+
+	if rq.Spec.ScopeSelector != nil {
+		for i, e := range rq.Spec.ScopeSelector.MatchExpressions {
+			exprPath := path.Child("scopeSelector", "matchExpressions").Index(i)
+			errs = append(errs, validateEnum(e.ScopeName, exprPath.Child("scopeName"), resourceQuotaScopes...)...)
+			errs = append(errs, validateEnum(e.Operator, exprPath.Child("operator"), corev1.ScopeSelectorOpIn,
+				corev1.ScopeSelectorOpNotIn, corev1.ScopeSelectorOpExists, corev1.ScopeSelectorOpDoesNotExist)...)
+
+			switch e.Operator {
+			case corev1.ScopeSelectorOpIn, corev1.ScopeSelectorOpNotIn:
+				if len(e.Values) == 0 {
+					errs = append(errs, field.Required(exprPath.Child("values"), "must be specified when operator is In or NotIn"))
+				}
+			case corev1.ScopeSelectorOpExists, corev1.ScopeSelectorOpDoesNotExist:
+				if len(e.Values) != 0 {
+					errs = append(errs, field.Invalid(exprPath.Child("values"), e.Values, "must not be specified when operator is Exists or DoesNotExist"))
+				}
+			}
+		}
+	}
+
+	return toAggregate(errs)
+}
+
+var resourceQuotaScopes = []corev1.ResourceQuotaScope{
+	corev1.ResourceQuotaScopeTerminating,
+	corev1.ResourceQuotaScopeNotTerminating,
+	corev1.ResourceQuotaScopeBestEffort,
+	corev1.ResourceQuotaScopeNotBestEffort,
+	corev1.ResourceQuotaScopePriorityClass,
+	corev1.ResourceQuotaScopeCrossNamespacePodAffinity,
+}
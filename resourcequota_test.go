@@ -0,0 +1,154 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestResourceQuotaHardLimits(t *testing.T) {
+	clearHard := func(rq *ResourceQuota) { rq.Spec.Hard = nil }
+
+	tests := []struct {
+		name string
+		opts []ResourceQuotaOpt
+		hard corev1.ResourceList
+	}{
+		{
+			name: "compute",
+			opts: []ResourceQuotaOpt{clearHard, ResourceQuotaCompute(resource.MustParse("2"), resource.MustParse("4Gi"), resource.MustParse("4"), resource.MustParse("8Gi"))},
+			hard: corev1.ResourceList{
+				corev1.ResourceRequestsCPU:    resource.MustParse("2"),
+				corev1.ResourceRequestsMemory: resource.MustParse("4Gi"),
+				corev1.ResourceLimitsCPU:      resource.MustParse("4"),
+				corev1.ResourceLimitsMemory:   resource.MustParse("8Gi"),
+			},
+		},
+		{
+			name: "storage",
+			opts: []ResourceQuotaOpt{clearHard, ResourceQuotaStorage(resource.MustParse("100Gi"), 10)},
+			hard: corev1.ResourceList{
+				corev1.ResourceRequestsStorage:        resource.MustParse("100Gi"),
+				corev1.ResourcePersistentVolumeClaims: resource.MustParse("10"),
+			},
+		},
+		{
+			name: "storage class and object count",
+			opts: []ResourceQuotaOpt{
+				ResourceQuotaStorageClass(NewStorageClass("gp3"), resource.MustParse("50Gi")),
+				ResourceQuotaObjectCount("deployments.apps", 20),
+			},
+			hard: corev1.ResourceList{
+				"gp3.storageclass.storage.k8s.io/requests.storage": resource.MustParse("50Gi"),
+				"count/deployments.apps":                           resource.MustParse("20"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rq := NewResourceQuota("quota", tt.opts...)
+			if len(rq.Spec.Hard) != len(tt.hard) {
+				t.Fatalf("hard = %v, want %v", rq.Spec.Hard, tt.hard)
+			}
+			for r, want := range tt.hard {
+				if got := rq.Spec.Hard[r]; got.Cmp(want) != 0 {
+					t.Errorf("hard[%s] = %s, want %s", r, got.String(), want.String())
+				}
+			}
+		})
+	}
+}
+
+func TestNamespaceResourceQuota(t *testing.T) {
+	ns := NewNamespace("team-a")
+	rq := ns.NewResourceQuota("quota", ResourceQuotaNamespace("other"), ResourceQuotaObjectCount("pods", 50))
+	lr := ns.NewLimitRange("limits", LimitRangeNamespace("other"))
+
+	if rq.Namespace != "team-a" || lr.Namespace != "team-a" {
+		t.Errorf("namespaces = %q and %q, want team-a", rq.Namespace, lr.Namespace)
+	}
+}
+
+func TestLimitRangeCopiesLimits(t *testing.T) {
+	l := Limits{Default: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")}}
+	lr := NewLimitRange("limits", LimitRangeContainer(l))
+
+	l.Default[corev1.ResourceCPU] = resource.MustParse("1")
+	if got := lr.Spec.Limits[0].Default[corev1.ResourceCPU]; got.String() != "500m" {
+		t.Errorf("default cpu = %s after changing the caller's limits, want 500m", got.String())
+	}
+}
+
+func TestResourceQuotaValidate(t *testing.T) {
+	cpu := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}
+
+	tests := []struct {
+		name   string
+		object Validator
+		fields []string
+	}{
+		{
+			name: "valid quota",
+			object: NewResourceQuota("quota", ResourceQuotaObjectCount("pods", 10),
+				ResourceQuotaScopeSelector(corev1.ResourceQuotaScopePriorityClass, corev1.ScopeSelectorOpIn, "high")),
+		},
+		{
+			name:   "scope selector without values",
+			object: NewResourceQuota("quota", ResourceQuotaScopeSelector(corev1.ResourceQuotaScopePriorityClass, corev1.ScopeSelectorOpIn)),
+			fields: []string{"spec.scopeSelector.matchExpressions[0].values"},
+		},
+		{
+			name:   "unknown scope",
+			object: NewResourceQuota("quota", ResourceQuotaScopes("Everything")),
+			fields: []string{"spec.scopes[0]"},
+		},
+		{
+			name:   "container defaults",
+			object: NewLimitRange("limits", LimitRangeContainer(Limits{Default: cpu, DefaultRequest: cpu, Max: cpu})),
+		},
+		{
+			name:   "pod defaults",
+			object: NewLimitRange("limits", LimitRangePod(Limits{Default: cpu, DefaultRequest: cpu})),
+			fields: []string{"spec.limits[0].default", "spec.limits[0].defaultRequest"},
+		},
+		{
+			name:   "claim default",
+			object: NewLimitRange("limits", LimitRangePersistentVolumeClaim(Limits{Default: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")}})),
+			fields: []string{"spec.limits[0].default"},
+		},
+		{
+			name: "default above max",
+			object: NewLimitRange("limits", LimitRangeContainer(Limits{
+				Default: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				Max:     cpu,
+			})),
+			fields: []string{"spec.limits[0].default[cpu]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, tt.object.Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -16,7 +16,9 @@ package kopts
 
 import (
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/validation/path"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // Role is a Kubernetes role
@@ -71,3 +73,33 @@ func rolePolicyRules(pr ...PolicyRule) RoleOpt {
 		}
 	}
 }
+
+// Apply applies the options to the role
+func (r *Role) Apply(opts ...RoleOpt) {
+	for _, opt := range opts {
+		opt(r)
+	}
+}
+
+// With returns a copy of the role with the options applied
+func (r Role) With(opts ...RoleOpt) Role {
+	clone := r.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the role
+func (r Role) Clone() Role {
+	return Role{Role: *r.Role.DeepCopy()}
+}
+
+// Validate checks the metadata and the policy rules. Role rules cannot apply to non-resource URLs
+func (r Role) Validate() error {
+	errs := validateObjectMeta(&r.ObjectMeta, true, path.ValidatePathSegmentName)
+	for i, pr := range r.Rules {
+		errs = append(errs, validatePolicyRule(pr, true, field.NewPath("rules").Index(i))...)
+	}
+
+	return toAggregate(errs)
+}
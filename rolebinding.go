@@ -16,7 +16,9 @@ package kopts
 
 import (
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/validation/path"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // RoleBinding is a Kubernetes role binding
@@ -76,3 +78,53 @@ func RoleBindingRoleRef(rf rbacv1.RoleRef) RoleBindingOpt {
 		r.RoleRef = rf
 	}
 }
+
+// Set the role the binding refers to
+func RoleBindingRole(role Role) RoleBindingOpt {
+	return func(r *RoleBinding) {
+		r.RoleRef = rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     role.Name,
+		}
+	}
+}
+
+// Set the cluster role the binding refers to. The cluster role permissions are granted within the binding namespace
+func RoleBindingClusterRole(cr ClusterRole) RoleBindingOpt {
+	return func(r *RoleBinding) {
+		r.RoleRef = clusterRoleRef(cr)
+	}
+}
+
+// Apply applies the options to the role binding
+func (r *RoleBinding) Apply(opts ...RoleBindingOpt) {
+	for _, opt := range opts {
+		opt(r)
+	}
+}
+
+// With returns a copy of the role binding with the options applied
+func (r RoleBinding) With(opts ...RoleBindingOpt) RoleBinding {
+	clone := r.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the role binding
+func (r RoleBinding) Clone() RoleBinding {
+	return RoleBinding{RoleBinding: *r.RoleBinding.DeepCopy()}
+}
+
+// Validate checks the metadata, that the role reference points to a Role or ClusterRole and that the subjects
+// are valid
+func (r RoleBinding) Validate() error {
+	errs := validateObjectMeta(&r.ObjectMeta, true, path.ValidatePathSegmentName)
+	errs = append(errs, validateRoleRef(r.RoleRef, field.NewPath("roleRef"), "Role", "ClusterRole")...)
+	for i, s := range r.Subjects {
+		errs = append(errs, validateSubject(s, true, field.NewPath("subjects").Index(i))...)
+	}
+
+	return toAggregate(errs)
+}
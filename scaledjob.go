@@ -0,0 +1,159 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	kedav1alpha1 "github.com/CoverWhale/kopts/apis/keda/v1alpha1"
+)
+
+// ScaledJob holds a KEDA scaled job
+type ScaledJob struct {
+	kedav1alpha1.ScaledJob
+}
+
+type ScaledJobOpt func(*ScaledJob)
+
+// NewScaledJob returns a scaled job with the given name and options
+func NewScaledJob(name string, opts ...ScaledJobOpt) ScaledJob {
+	sj := ScaledJob{
+		ScaledJob: kedav1alpha1.ScaledJob{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ScaledJob",
+				APIVersion: kedav1alpha1.APIVersion,
+			},
+			ObjectMeta: newObjectMeta(name),
+			Spec: kedav1alpha1.ScaledJobSpec{
+				JobTargetRef: &batchv1.JobSpec{},
+			},
+		},
+	}
+
+	for _, v := range opts {
+		v(&sj)
+	}
+
+	return sj
+}
+
+// ScaledJobNamespace sets the namespace for the scaled job
+func ScaledJobNamespace(n string) ScaledJobOpt {
+	return func(sj *ScaledJob) {
+		setNamespace(n, &sj.ObjectMeta)
+	}
+}
+
+// ScaledJobJobSpec applies the job spec options to the template of the created jobs
+func ScaledJobJobSpec(opts ...JobSpecOpt) ScaledJobOpt {
+	return func(sj *ScaledJob) {
+		if sj.Spec.JobTargetRef == nil {
+			sj.Spec.JobTargetRef = &batchv1.JobSpec{}
+		}
+		for _, v := range opts {
+			v(sj.Spec.JobTargetRef)
+		}
+	}
+}
+
+// ScaledJobPodSpec sets the pod template of the created jobs
+func ScaledJobPodSpec(p PodSpec) ScaledJobOpt {
+	return ScaledJobJobSpec(JobSpecPodSpec(p))
+}
+
+// ScaledJobMinReplicas sets the minimum number of jobs
+func ScaledJobMinReplicas(i int) ScaledJobOpt {
+	return func(sj *ScaledJob) {
+		sj.Spec.MinReplicaCount = ptrTo(int32(i))
+	}
+}
+
+// ScaledJobMaxReplicas sets the maximum number of jobs running at the same time
+func ScaledJobMaxReplicas(i int) ScaledJobOpt {
+	return func(sj *ScaledJob) {
+		sj.Spec.MaxReplicaCount = ptrTo(int32(i))
+	}
+}
+
+// ScaledJobPollingInterval sets how often the triggers are checked in seconds
+func ScaledJobPollingInterval(seconds int) ScaledJobOpt {
+	return func(sj *ScaledJob) {
+		sj.Spec.PollingInterval = ptrTo(int32(seconds))
+	}
+}
+
+// ScaledJobSuccessfulJobsHistoryLimit sets how many successful jobs are kept
+func ScaledJobSuccessfulJobsHistoryLimit(i int) ScaledJobOpt {
+	return func(sj *ScaledJob) {
+		sj.Spec.SuccessfulJobsHistoryLimit = ptrTo(int32(i))
+	}
+}
+
+// ScaledJobFailedJobsHistoryLimit sets how many failed jobs are kept
+func ScaledJobFailedJobsHistoryLimit(i int) ScaledJobOpt {
+	return func(sj *ScaledJob) {
+		sj.Spec.FailedJobsHistoryLimit = ptrTo(int32(i))
+	}
+}
+
+// ScaledJobTrigger adds a trigger
+func ScaledJobTrigger(t ScaleTrigger) ScaledJobOpt {
+	trigger := deepCopyJSON(t.ScaleTriggers)
+	return func(sj *ScaledJob) {
+		sj.Spec.Triggers = append(sj.Spec.Triggers, deepCopyJSON(trigger))
+	}
+}
+
+// Apply applies the options to the scaled job
+func (sj *ScaledJob) Apply(opts ...ScaledJobOpt) {
+	for _, opt := range opts {
+		opt(sj)
+	}
+}
+
+// With returns a copy of the scaled job with the options applied
+func (sj ScaledJob) With(opts ...ScaledJobOpt) ScaledJob {
+	clone := sj.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the scaled job
+func (sj ScaledJob) Clone() ScaledJob {
+	return ScaledJob{ScaledJob: deepCopyJSON(sj.ScaledJob)}
+}
+
+// Validate checks the metadata, the job spec, the replica counts and the triggers
+func (sj ScaledJob) Validate() error {
+	errs := validateObjectMeta(&sj.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	if sj.Spec.JobTargetRef == nil {
+		errs = append(errs, field.Required(path.Child("jobTargetRef"), ""))
+	} else {
+		errs = append(errs, validateJobSpec(*sj.Spec.JobTargetRef, path.Child("jobTargetRef"))...)
+	}
+
+	errs = append(errs, validateReplicaCounts(sj.Spec.MinReplicaCount, sj.Spec.MaxReplicaCount, path)...)
+	errs = append(errs, validateNonNegative(sj.Spec.PollingInterval, path.Child("pollingInterval"))...)
+	errs = append(errs, validateNonNegative(sj.Spec.SuccessfulJobsHistoryLimit, path.Child("successfulJobsHistoryLimit"))...)
+	errs = append(errs, validateNonNegative(sj.Spec.FailedJobsHistoryLimit, path.Child("failedJobsHistoryLimit"))...)
+
+	return toAggregate(append(errs, validateScaleTriggers(sj.Spec.Triggers, path.Child("triggers"))...))
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestScaledJob(t *testing.T) {
+	pod := NewPodSpec("worker", PodContainer(NewContainer("worker", ContainerImage("worker:1.0"))))
+	maxReplicas := ScaledJobMaxReplicas(20)
+	sj := NewScaledJob("worker",
+		ScaledJobNamespace("queues"),
+		ScaledJobPodSpec(pod),
+		ScaledJobJobSpec(JobSpecRestartPolicy(corev1.RestartPolicyNever), JobSpecBackoffLimit(2)),
+		maxReplicas,
+		ScaledJobSuccessfulJobsHistoryLimit(3),
+		ScaledJobTrigger(NewAWSSQSTrigger("https://sqs.us-east-1.amazonaws.com/123456789012/jobs", "us-east-1", 1)),
+	)
+
+	spec := sj.Spec.JobTargetRef
+	if len(spec.Template.Spec.Containers) != 1 || spec.Template.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Errorf("job template = %+v, want the worker container restarted never", spec.Template.Spec)
+	}
+	if got := ptrValue(spec.BackoffLimit); got != 2 {
+		t.Errorf("backoffLimit = %d, want 2", got)
+	}
+	if err := sj.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	*sj.Spec.MaxReplicaCount = 1
+	if got := ptrValue(NewScaledJob("second", maxReplicas).Spec.MaxReplicaCount); got != 20 {
+		t.Errorf("maxReplicaCount = %d for a second scaled job, want 20", got)
+	}
+}
+
+func TestScaledJobJobSpecWithoutTarget(t *testing.T) {
+	sj := ScaledJob{}
+	sj.Apply(ScaledJobJobSpec(JobSpecBackoffLimit(1)))
+
+	if sj.Spec.JobTargetRef == nil || ptrValue(sj.Spec.JobTargetRef.BackoffLimit) != 1 {
+		t.Errorf("jobTargetRef = %+v, want a job spec with a backoff limit of 1", sj.Spec.JobTargetRef)
+	}
+}
+
+func TestScaledJobValidate(t *testing.T) {
+	pod := ScaledJobPodSpec(NewPodSpec("worker", PodContainer(NewContainer("worker", ContainerImage("worker:1.0")))))
+	never := ScaledJobJobSpec(JobSpecRestartPolicy(corev1.RestartPolicyNever))
+	trigger := ScaledJobTrigger(NewRabbitMQTrigger("jobs", 1))
+
+	tests := []struct {
+		name   string
+		opts   []ScaledJobOpt
+		fields []string
+	}{
+		{
+			name:   "without triggers",
+			opts:   []ScaledJobOpt{pod, never},
+			fields: []string{"spec.triggers"},
+		},
+		{
+			name:   "without job target",
+			opts:   []ScaledJobOpt{trigger, func(sj *ScaledJob) { sj.Spec.JobTargetRef = nil }},
+			fields: []string{"spec.jobTargetRef"},
+		},
+		{
+			name:   "negative history limits",
+			opts:   []ScaledJobOpt{pod, never, trigger, ScaledJobSuccessfulJobsHistoryLimit(-1), ScaledJobFailedJobsHistoryLimit(-1)},
+			fields: []string{"spec.failedJobsHistoryLimit", "spec.successfulJobsHistoryLimit"},
+		},
+		{
+			name:   "minimum over maximum",
+			opts:   []ScaledJobOpt{pod, never, trigger, ScaledJobMinReplicas(3), ScaledJobMaxReplicas(1)},
+			fields: []string{"spec.minReplicaCount"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sj := NewScaledJob("worker", append([]ScaledJobOpt{ScaledJobNamespace("queues")}, tt.opts...)...)
+			fields := validationFields(t, sj.Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
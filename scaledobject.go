@@ -0,0 +1,160 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	kedav1alpha1 "github.com/CoverWhale/kopts/apis/keda/v1alpha1"
+)
+
+// ScaledObject holds a KEDA scaled object
+type ScaledObject struct {
+	kedav1alpha1.ScaledObject
+}
+
+type ScaledObjectOpt func(*ScaledObject)
+
+// NewScaledObject returns a scaled object with the given name and options
+func NewScaledObject(name string, opts ...ScaledObjectOpt) ScaledObject {
+	so := ScaledObject{
+		ScaledObject: kedav1alpha1.ScaledObject{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ScaledObject",
+				APIVersion: kedav1alpha1.APIVersion,
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&so)
+	}
+
+	return so
+}
+
+// ScaledObjectNamespace sets the namespace for the scaled object
+func ScaledObjectNamespace(n string) ScaledObjectOpt {
+	return func(so *ScaledObject) {
+		setNamespace(n, &so.ObjectMeta)
+	}
+}
+
+// ScaledObjectDeployment sets the deployment as the scale target. The scaled object is placed in the deployment namespace
+func ScaledObjectDeployment(d *Deployment) ScaledObjectOpt {
+	return scaledObjectTarget(d.TypeMeta, d.ObjectMeta)
+}
+
+// ScaledObjectStatefulSet sets the stateful set as the scale target. The scaled object is placed in the stateful set namespace
+func ScaledObjectStatefulSet(s *StatefulSet) ScaledObjectOpt {
+	return scaledObjectTarget(s.TypeMeta, s.ObjectMeta)
+}
+
+func scaledObjectTarget(t metav1.TypeMeta, m metav1.ObjectMeta) ScaledObjectOpt {
+	return func(so *ScaledObject) {
+		setNamespace(m.Namespace, &so.ObjectMeta)
+		so.Spec.ScaleTargetRef = &kedav1alpha1.ScaleTarget{
+			Name:       m.Name,
+			APIVersion: t.APIVersion,
+			Kind:       t.Kind,
+		}
+	}
+}
+
+// ScaledObjectMinReplicas sets the minimum replicas. Zero allows the target to scale to zero
+func ScaledObjectMinReplicas(i int) ScaledObjectOpt {
+	return func(so *ScaledObject) {
+		so.Spec.MinReplicaCount = ptrTo(int32(i))
+	}
+}
+
+// ScaledObjectMaxReplicas sets the maximum replicas
+func ScaledObjectMaxReplicas(i int) ScaledObjectOpt {
+	return func(so *ScaledObject) {
+		so.Spec.MaxReplicaCount = ptrTo(int32(i))
+	}
+}
+
+// ScaledObjectIdleReplicas sets the replicas used when no trigger is active. It must be lower than the minimum replicas
+func ScaledObjectIdleReplicas(i int) ScaledObjectOpt {
+	return func(so *ScaledObject) {
+		so.Spec.IdleReplicaCount = ptrTo(int32(i))
+	}
+}
+
+// ScaledObjectPollingInterval sets how often the triggers are checked in seconds
+func ScaledObjectPollingInterval(seconds int) ScaledObjectOpt {
+	return func(so *ScaledObject) {
+		so.Spec.PollingInterval = ptrTo(int32(seconds))
+	}
+}
+
+// ScaledObjectCooldownPeriod sets how long to wait in seconds after the last active trigger before scaling to zero
+func ScaledObjectCooldownPeriod(seconds int) ScaledObjectOpt {
+	return func(so *ScaledObject) {
+		so.Spec.CooldownPeriod = ptrTo(int32(seconds))
+	}
+}
+
+// ScaledObjectTrigger adds a trigger
+func ScaledObjectTrigger(t ScaleTrigger) ScaledObjectOpt {
+	trigger := deepCopyJSON(t.ScaleTriggers)
+	return func(so *ScaledObject) {
+		so.Spec.Triggers = append(so.Spec.Triggers, deepCopyJSON(trigger))
+	}
+}
+
+// Apply applies the options to the scaled object
+func (so *ScaledObject) Apply(opts ...ScaledObjectOpt) {
+	for _, opt := range opts {
+		opt(so)
+	}
+}
+
+// With returns a copy of the scaled object with the options applied
+func (so ScaledObject) With(opts ...ScaledObjectOpt) ScaledObject {
+	clone := so.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the scaled object
+func (so ScaledObject) Clone() ScaledObject {
+	return ScaledObject{ScaledObject: deepCopyJSON(so.ScaledObject)}
+}
+
+// Validate checks the metadata, that the scale target is named, the replica counts and the triggers
+func (so ScaledObject) Validate() error {
+	errs := validateObjectMeta(&so.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	if so.Spec.ScaleTargetRef == nil || so.Spec.ScaleTargetRef.Name == "" {
+		errs = append(errs, field.Required(path.Child("scaleTargetRef", "name"), ""))
+	}
+
+	errs = append(errs, validateReplicaCounts(so.Spec.MinReplicaCount, so.Spec.MaxReplicaCount, path)...)
+	errs = append(errs, validateNonNegative(so.Spec.IdleReplicaCount, path.Child("idleReplicaCount"))...)
+	if idle, min := so.Spec.IdleReplicaCount, ptrValue(so.Spec.MinReplicaCount); idle != nil && *idle >= min {
+		errs = append(errs, field.Invalid(path.Child("idleReplicaCount"), *idle, "must be less than minReplicaCount"))
+	}
+	errs = append(errs, validateNonNegative(so.Spec.PollingInterval, path.Child("pollingInterval"))...)
+	errs = append(errs, validateNonNegative(so.Spec.CooldownPeriod, path.Child("cooldownPeriod"))...)
+
+	return toAggregate(append(errs, validateScaleTriggers(so.Spec.Triggers, path.Child("triggers"))...))
+}
@@ -0,0 +1,173 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScaledObjectDeployment(t *testing.T) {
+	d := NewDeployment("consumer", DeploymentNamespace("queues"))
+	secret := NewSecret("rabbitmq", SecretNamespace("queues"), SecretData("host", []byte("amqp://rabbitmq:5672")))
+	ta := NewTriggerAuthentication("rabbitmq", TriggerAuthenticationSecret(secret))
+	trigger := NewRabbitMQTrigger("orders", 20, ScaleTriggerAuthentication(ta))
+
+	minReplicas := ScaledObjectMinReplicas(0)
+	opt := ScaledObjectTrigger(trigger)
+	so := NewScaledObject("consumer",
+		ScaledObjectDeployment(d),
+		minReplicas,
+		ScaledObjectMaxReplicas(10),
+		ScaledObjectPollingInterval(15),
+		ScaledObjectCooldownPeriod(120),
+		opt,
+	)
+	trigger.Metadata["queueName"] = "changed"
+
+	if so.Namespace != "queues" {
+		t.Errorf("namespace = %q, want queues", so.Namespace)
+	}
+	if ref := so.Spec.ScaleTargetRef; ref.Name != "consumer" || ref.Kind != "Deployment" || ref.APIVersion != "apps/v1" {
+		t.Errorf("scaleTargetRef = %+v, want the consumer deployment", *ref)
+	}
+	if got := ptrValue(so.Spec.MinReplicaCount); got != 0 || so.Spec.MinReplicaCount == nil {
+		t.Errorf("minReplicaCount = %v, want 0", so.Spec.MinReplicaCount)
+	}
+	if ta.Namespace != "queues" || len(ta.Spec.SecretTargetRef) != 1 || ta.Spec.SecretTargetRef[0].Parameter != "host" {
+		t.Errorf("trigger authentication = %+v, want the host parameter in queues", ta.ObjectMeta)
+	}
+	tr := so.Spec.Triggers[0]
+	if tr.Type != "rabbitmq" || tr.Metadata["queueName"] != "orders" || tr.AuthenticationRef.Name != "rabbitmq" {
+		t.Errorf("trigger = %+v after changing the caller's trigger, want the orders queue with the rabbitmq authentication", tr)
+	}
+	for _, v := range []Validator{so, ta} {
+		if err := v.Validate(); err != nil {
+			t.Errorf("Validate() = %v", err)
+		}
+	}
+
+	*so.Spec.MinReplicaCount = 5
+	tr.Metadata["queueName"] = "changed"
+	second := NewScaledObject("second", minReplicas, opt)
+	if got := ptrValue(second.Spec.MinReplicaCount); got != 0 {
+		t.Errorf("minReplicaCount = %d for a second scaled object, want 0", got)
+	}
+	if got := second.Spec.Triggers[0].Metadata["queueName"]; got != "orders" {
+		t.Errorf("queueName = %q for a second scaled object, want orders", got)
+	}
+}
+
+func TestScaleTriggers(t *testing.T) {
+	tests := []struct {
+		name     string
+		trigger  ScaleTrigger
+		scaler   string
+		metadata map[string]string
+	}{
+		{
+			name:     "cron",
+			trigger:  NewCronTrigger("Europe/Paris", "0 8 * * 1-5", "0 20 * * 1-5", 4),
+			scaler:   "cron",
+			metadata: map[string]string{"timezone": "Europe/Paris", "start": "0 8 * * 1-5", "end": "0 20 * * 1-5", "desiredReplicas": "4"},
+		},
+		{
+			name:     "prometheus",
+			trigger:  NewPrometheusTrigger("http://prometheus:9090", "sum(rate(http_requests_total[1m]))", 100.5),
+			scaler:   "prometheus",
+			metadata: map[string]string{"serverAddress": "http://prometheus:9090", "query": "sum(rate(http_requests_total[1m]))", "threshold": "100.5"},
+		},
+		{
+			name:     "kafka",
+			trigger:  NewKafkaTrigger("kafka:9092", "orders", "orders", 50, ScaleTriggerName("lag")),
+			scaler:   "kafka",
+			metadata: map[string]string{"bootstrapServers": "kafka:9092", "consumerGroup": "orders", "topic": "orders", "lagThreshold": "50"},
+		},
+		{
+			name:     "aws-sqs",
+			trigger:  NewAWSSQSTrigger("https://sqs.us-east-1.amazonaws.com/123456789012/orders", "us-east-1", 5),
+			scaler:   "aws-sqs-queue",
+			metadata: map[string]string{"queueURL": "https://sqs.us-east-1.amazonaws.com/123456789012/orders", "awsRegion": "us-east-1", "queueLength": "5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.trigger.Type != tt.scaler {
+				t.Errorf("type = %q, want %q", tt.trigger.Type, tt.scaler)
+			}
+			if !reflect.DeepEqual(tt.trigger.Metadata, tt.metadata) {
+				t.Errorf("metadata = %v, want %v", tt.trigger.Metadata, tt.metadata)
+			}
+			if err := tt.trigger.Validate(); err != nil {
+				t.Errorf("Validate() = %v", err)
+			}
+		})
+	}
+}
+
+func TestScaledObjectValidate(t *testing.T) {
+	target := ScaledObjectDeployment(NewDeployment("consumer", DeploymentNamespace("queues")))
+	trigger := ScaledObjectTrigger(NewRabbitMQTrigger("orders", 20))
+
+	tests := []struct {
+		name   string
+		opts   []ScaledObjectOpt
+		fields []string
+	}{
+		{
+			name:   "without target and triggers",
+			opts:   []ScaledObjectOpt{ScaledObjectNamespace("queues")},
+			fields: []string{"spec.scaleTargetRef.name", "spec.triggers"},
+		},
+		{
+			name:   "minimum over maximum",
+			opts:   []ScaledObjectOpt{target, trigger, ScaledObjectMinReplicas(5), ScaledObjectMaxReplicas(2)},
+			fields: []string{"spec.minReplicaCount"},
+		},
+		{
+			name:   "idle replicas not under the minimum",
+			opts:   []ScaledObjectOpt{target, trigger, ScaledObjectMinReplicas(1), ScaledObjectIdleReplicas(1)},
+			fields: []string{"spec.idleReplicaCount"},
+		},
+		{
+			name:   "negative intervals",
+			opts:   []ScaledObjectOpt{target, trigger, ScaledObjectPollingInterval(-1), ScaledObjectCooldownPeriod(-1)},
+			fields: []string{"spec.cooldownPeriod", "spec.pollingInterval"},
+		},
+		{
+			name: "duplicate trigger names",
+			opts: []ScaledObjectOpt{target,
+				ScaledObjectTrigger(NewRabbitMQTrigger("orders", 20, ScaleTriggerName("queue"))),
+				ScaledObjectTrigger(NewRabbitMQTrigger("refunds", 20, ScaleTriggerName("queue"))),
+			},
+			fields: []string{"spec.triggers[1].name"},
+		},
+		{
+			name:   "unknown metric type",
+			opts:   []ScaledObjectOpt{target, ScaledObjectTrigger(NewRabbitMQTrigger("orders", 20, ScaleTriggerMetricType("Average")))},
+			fields: []string{"spec.triggers[0].metricType"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, NewScaledObject("consumer", tt.opts...).Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"strconv"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	kedav1alpha1 "github.com/CoverWhale/kopts/apis/keda/v1alpha1"
+)
+
+// ScaleTrigger holds a KEDA trigger for a scaled object or a scaled job
+type ScaleTrigger struct {
+	kedav1alpha1.ScaleTriggers
+}
+
+type ScaleTriggerOpt func(*ScaleTrigger)
+
+// NewScaleTrigger returns a trigger of the scaler type with the given options.
+// The typed constructors below set the required metadata of the common scalers
+func NewScaleTrigger(scalerType string, opts ...ScaleTriggerOpt) ScaleTrigger {
+	t := ScaleTrigger{
+		ScaleTriggers: kedav1alpha1.ScaleTriggers{
+			Type:     scalerType,
+			Metadata: make(map[string]string),
+		},
+	}
+
+	for _, v := range opts {
+		v(&t)
+	}
+
+	return t
+}
+
+// NewCronTrigger returns a trigger that scales to the desired replicas between the start and end cron expressions
+// in the IANA timezone
+func NewCronTrigger(timezone, start, end string, desiredReplicas int, opts ...ScaleTriggerOpt) ScaleTrigger {
+	return NewScaleTrigger("cron", append([]ScaleTriggerOpt{
+		ScaleTriggerMetadata("timezone", timezone),
+		ScaleTriggerMetadata("start", start),
+		ScaleTriggerMetadata("end", end),
+		ScaleTriggerMetadata("desiredReplicas", strconv.Itoa(desiredReplicas)),
+	}, opts...)...)
+}
+
+// NewPrometheusTrigger returns a trigger that scales on the value of the Prometheus query compared to the threshold
+func NewPrometheusTrigger(serverAddress, query string, threshold float64, opts ...ScaleTriggerOpt) ScaleTrigger {
+	return NewScaleTrigger("prometheus", append([]ScaleTriggerOpt{
+		ScaleTriggerMetadata("serverAddress", serverAddress),
+		ScaleTriggerMetadata("query", query),
+		ScaleTriggerMetadata("threshold", strconv.FormatFloat(threshold, 'f', -1, 64)),
+	}, opts...)...)
+}
+
+// NewKafkaTrigger returns a trigger that scales on the consumer group lag of the topic
+func NewKafkaTrigger(bootstrapServers, consumerGroup, topic string, lagThreshold int, opts ...ScaleTriggerOpt) ScaleTrigger {
+	return NewScaleTrigger("kafka", append([]ScaleTriggerOpt{
+		ScaleTriggerMetadata("bootstrapServers", bootstrapServers),
+		ScaleTriggerMetadata("consumerGroup", consumerGroup),
+		ScaleTriggerMetadata("topic", topic),
+		ScaleTriggerMetadata("lagThreshold", strconv.Itoa(lagThreshold)),
+	}, opts...)...)
+}
+
+// NewRabbitMQTrigger returns a trigger that scales on the message count of the queue. The connection string is
+// read from the host parameter of the trigger authentication
+func NewRabbitMQTrigger(queueName string, queueLength int, opts ...ScaleTriggerOpt) ScaleTrigger {
+	return NewScaleTrigger("rabbitmq", append([]ScaleTriggerOpt{
+		ScaleTriggerMetadata("queueName", queueName),
+		ScaleTriggerMetadata("mode", "QueueLength"),
+		ScaleTriggerMetadata("value", strconv.Itoa(queueLength)),
+	}, opts...)...)
+}
+
+// NewAWSSQSTrigger returns a trigger that scales on the approximate message count of the SQS queue
+func NewAWSSQSTrigger(queueURL, region string, queueLength int, opts ...ScaleTriggerOpt) ScaleTrigger {
+	return NewScaleTrigger("aws-sqs-queue", append([]ScaleTriggerOpt{
+		ScaleTriggerMetadata("queueURL", queueURL),
+		ScaleTriggerMetadata("awsRegion", region),
+		ScaleTriggerMetadata("queueLength", strconv.Itoa(queueLength)),
+	}, opts...)...)
+}
+
+// ScaleTriggerName sets the trigger name. Named triggers can be referenced in scaling modifiers
+func ScaleTriggerName(n string) ScaleTriggerOpt {
+	return func(t *ScaleTrigger) {
+		t.Name = n
+	}
+}
+
+// ScaleTriggerMetadata sets a metadata value of the scaler
+func ScaleTriggerMetadata(key, value string) ScaleTriggerOpt {
+	return func(t *ScaleTrigger) {
+		if t.Metadata == nil {
+			t.Metadata = make(map[string]string)
+		}
+		t.Metadata[key] = value
+	}
+}
+
+// ScaleTriggerMetricType sets the metric target type of the trigger. Scalers default to AverageValue
+func ScaleTriggerMetricType(m autoscalingv2.MetricTargetType) ScaleTriggerOpt {
+	return func(t *ScaleTrigger) {
+		t.MetricType = m
+	}
+}
+
+// ScaleTriggerAuthentication sets the trigger authentication supplying the scaler credentials
+func ScaleTriggerAuthentication(ta TriggerAuthentication) ScaleTriggerOpt {
+	name := ta.Name
+	return func(t *ScaleTrigger) {
+		t.AuthenticationRef = &kedav1alpha1.AuthenticationRef{
+			Name: name,
+		}
+	}
+}
+
+// Apply applies the options to the trigger
+func (t *ScaleTrigger) Apply(opts ...ScaleTriggerOpt) {
+	for _, opt := range opts {
+		opt(t)
+	}
+}
+
+// With returns a copy of the trigger with the options applied
+func (t ScaleTrigger) With(opts ...ScaleTriggerOpt) ScaleTrigger {
+	clone := t.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the trigger
+func (t ScaleTrigger) Clone() ScaleTrigger {
+	return ScaleTrigger{ScaleTriggers: deepCopyJSON(t.ScaleTriggers)}
+}
+
+// Validate checks that the trigger has a type, the metric type and that the authentication reference is named
+func (t ScaleTrigger) Validate() error {
+	return toAggregate(validateScaleTrigger(t.ScaleTriggers, nil))
+}
+
+func validateScaleTrigger(t kedav1alpha1.ScaleTriggers, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if t.Type == "" {
+		errs = append(errs, field.Required(path.Child("type"), ""))
+	}
+	errs = append(errs, validateEnum(t.MetricType, path.Child("metricType"),
+		autoscalingv2.AverageValueMetricType, autoscalingv2.ValueMetricType, autoscalingv2.UtilizationMetricType)...)
+
+	if ref := t.AuthenticationRef; ref != nil {
+		errs = append(errs, validateDNSSubdomain(ref.Name, path.Child("authenticationRef", "name"))...)
+		errs = append(errs, validateEnum(ref.Kind, path.Child("authenticationRef", "kind"),
+			"TriggerAuthentication", "ClusterTriggerAuthentication")...)
+	}
+
+	return errs
+}
+
+// validateScaleTriggers checks that there is at least one trigger, that every trigger is valid and that named
+// triggers are unique
+func validateScaleTriggers(triggers []kedav1alpha1.ScaleTriggers, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if len(triggers) == 0 {
+		errs = append(errs, field.Required(path, "at least one trigger is required"))
+	}
+
+	names := sets.New[string]()
+	for i, t := range triggers {
+		if t.Name != "" && names.Has(t.Name) {
+			errs = append(errs, field.Duplicate(path.Index(i).Child("name"), t.Name))
+		}
+		names.Insert(t.Name)
+		errs = append(errs, validateScaleTrigger(t, path.Index(i))...)
+	}
+
+	return errs
+}
+
+// validateReplicaCounts checks that the replica counts are not negative and that the minimum is not greater than
+// the maximum
+func validateReplicaCounts(min, max *int32, path *field.Path) field.ErrorList {
+	errs := validateNonNegative(min, path.Child("minReplicaCount"))
+	errs = append(errs, validateNonNegative(max, path.Child("maxReplicaCount"))...)
+	if min != nil && max != nil && *min > *max {
+		errs = append(errs, field.Invalid(path.Child("minReplicaCount"), *min,
+			fmt.Sprintf("must not be greater than maxReplicaCount %d", *max)))
+	}
+
+	return errs
+}
@@ -16,7 +16,9 @@ package kopts
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // Secret holds a Kubernetes secret
@@ -54,11 +56,59 @@ func SecretNamespace(n string) SecretOpt {
 	}
 }
 
-// Set secret data
+// Set secret data. Each call adds a key to the secret data
 func SecretData(key string, value []byte) SecretOpt {
 	return func(s *Secret) {
-		s.Data = map[string][]byte{
-			key: value,
+		if s.Data == nil {
+			s.Data = make(map[string][]byte)
 		}
+		s.Data[key] = value
 	}
 }
+
+// SecretTLS makes the secret a TLS secret holding the PEM encoded certificate and private key
+func SecretTLS(cert, key []byte) SecretOpt {
+	return func(s *Secret) {
+		s.Type = corev1.SecretTypeTLS
+		SecretData(corev1.TLSCertKey, cert)(s)
+		SecretData(corev1.TLSPrivateKeyKey, key)(s)
+	}
+}
+
+// Apply applies the options to the secret
+func (s *Secret) Apply(opts ...SecretOpt) {
+	for _, opt := range opts {
+		opt(s)
+	}
+}
+
+// With returns a copy of the secret with the options applied
+func (s Secret) With(opts ...SecretOpt) Secret {
+	clone := s.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the secret
+func (s Secret) Clone() Secret {
+	return Secret{Secret: *s.Secret.DeepCopy()}
+}
+
+// Validate checks the metadata, the data keys and that TLS secrets have a certificate and key
+func (s Secret) Validate() error {
+	errs := validateObjectMeta(&s.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("data")
+	errs = append(errs, validateDataKeys(s.Data, path)...)
+	errs = append(errs, validateDataKeys(s.StringData, field.NewPath("stringData"))...)
+
+	if s.Type == corev1.SecretTypeTLS {
+		for _, k := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+			if _, ok := s.Data[k]; !ok {
+				errs = append(errs, field.Required(path.Key(k), "required for kubernetes.io/tls secrets"))
+			}
+		}
+	}
+
+	return toAggregate(errs)
+}
@@ -16,8 +16,12 @@ package kopts
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // Service holds a kubernetes service
@@ -74,3 +78,99 @@ func ServiceSelector(key, value string) ServiceOpt {
 		s.Spec.Selector[key] = value
 	}
 }
+
+// Make the service headless. Stateful sets use a headless service for the network identity of their pods
+func ServiceHeadless() ServiceOpt {
+	return func(s *Service) {
+		s.Spec.ClusterIP = corev1.ClusterIPNone
+	}
+}
+
+// ServiceLabel adds a label to the service
+func ServiceLabel(key, value string) ServiceOpt {
+	return func(s *Service) {
+		addLabel(key, value, &s.ObjectMeta)
+	}
+}
+
+// ServiceLabels adds the labels to the service
+func ServiceLabels(labels map[string]string) ServiceOpt {
+	return func(s *Service) {
+		for k, v := range labels {
+			addLabel(k, v, &s.ObjectMeta)
+		}
+	}
+}
+
+// ServiceNamedPort adds a named service port. Monitors and routes refer to service ports by name
+func ServiceNamedPort(name string, port, targetPort int) ServiceOpt {
+	return func(s *Service) {
+		s.Spec.Ports = append(s.Spec.Ports, corev1.ServicePort{
+			Name:       name,
+			Port:       int32(port),
+			TargetPort: intstr.FromInt(targetPort),
+		})
+	}
+}
+
+// Apply applies the options to the service
+func (s *Service) Apply(opts ...ServiceOpt) {
+	for _, opt := range opts {
+		opt(s)
+	}
+}
+
+// With returns a copy of the service with the options applied
+func (s Service) With(opts ...ServiceOpt) Service {
+	clone := s.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the service
+func (s Service) Clone() Service {
+	return Service{Service: *s.Service.DeepCopy()}
+}
+
+// Validate checks the metadata, the service type and that the ports are valid and uniquely named. Services with
+// more than one port must name every port
+func (s Service) Validate() error {
+	errs := validateObjectMeta(&s.ObjectMeta, true, apimachineryvalidation.NameIsDNS1035Label)
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateEnum(s.Spec.Type, path.Child("type"), corev1.ServiceTypeClusterIP,
+		corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeExternalName)...)
+	if s.Spec.Type == corev1.ServiceTypeExternalName {
+		errs = append(errs, validateDNSSubdomain(s.Spec.ExternalName, path.Child("externalName"))...)
+	}
+
+	errs = append(errs, metav1validation.ValidateLabels(s.Spec.Selector, path.Child("selector"))...)
+
+	names := sets.New[string]()
+	for i, p := range s.Spec.Ports {
+		portPath := path.Child("ports").Index(i)
+		switch {
+		case p.Name == "" && len(s.Spec.Ports) > 1:
+			errs = append(errs, field.Required(portPath.Child("name"), "required when there is more than one port"))
+		case p.Name != "":
+			errs = append(errs, validateDNSLabel(p.Name, portPath.Child("name"))...)
+			if names.Has(p.Name) {
+				errs = append(errs, field.Duplicate(portPath.Child("name"), p.Name))
+			}
+			names.Insert(p.Name)
+		}
+
+		errs = append(errs, validatePort(p.Port, portPath.Child("port"))...)
+		if p.TargetPort.Type == intstr.String || p.TargetPort.IntVal != 0 {
+			errs = append(errs, validateIntOrStringPort(p.TargetPort, portPath.Child("targetPort"))...)
+		}
+		if p.NodePort != 0 {
+			errs = append(errs, validatePort(p.NodePort, portPath.Child("nodePort"))...)
+		}
+		errs = append(errs, validateEnum(p.Protocol, portPath.Child("protocol"),
+			corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP)...)
+	}
+
+	return toAggregate(errs)
+}
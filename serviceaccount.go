@@ -16,7 +16,9 @@ package kopts
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // ServiceAccount is a Kubernetes service account
@@ -82,3 +84,33 @@ func ServiceAccountAutoMountToken(b bool) ServiceAccountOpt {
 		s.AutomountServiceAccountToken = &autoMount
 	}
 }
+
+// Apply applies the options to the service account
+func (s *ServiceAccount) Apply(opts ...ServiceAccountOpt) {
+	for _, opt := range opts {
+		opt(s)
+	}
+}
+
+// With returns a copy of the service account with the options applied
+func (s ServiceAccount) With(opts ...ServiceAccountOpt) ServiceAccount {
+	clone := s.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the service account
+func (s ServiceAccount) Clone() ServiceAccount {
+	return ServiceAccount{ServiceAccount: *s.ServiceAccount.DeepCopy()}
+}
+
+// Validate checks the metadata and that the image pull secrets are named
+func (s ServiceAccount) Validate() error {
+	errs := validateObjectMeta(&s.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	for i, ref := range s.ImagePullSecrets {
+		errs = append(errs, validateDNSSubdomain(ref.Name, field.NewPath("imagePullSecrets").Index(i).Child("name"))...)
+	}
+
+	return toAggregate(errs)
+}
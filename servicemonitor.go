@@ -0,0 +1,244 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	monitoringv1 "github.com/CoverWhale/kopts/apis/monitoring/v1"
+)
+
+// MetricsEndpoint holds the scrape settings of a service monitor or pod monitor endpoint.
+// Zero values use the Prometheus defaults
+type MetricsEndpoint struct {
+	Port          string
+	Path          string
+	Scheme        string
+	Interval      time.Duration
+	ScrapeTimeout time.Duration
+	HonorLabels   bool
+}
+
+func (e MetricsEndpoint) endpoint() monitoringv1.Endpoint {
+	return monitoringv1.Endpoint{
+		Port:          e.Port,
+		Path:          e.Path,
+		Scheme:        e.Scheme,
+		Interval:      monitoringDuration(e.Interval),
+		ScrapeTimeout: monitoringDuration(e.ScrapeTimeout),
+		HonorLabels:   e.HonorLabels,
+	}
+}
+
+func (e MetricsEndpoint) podMetricsEndpoint() monitoringv1.PodMetricsEndpoint {
+	return monitoringv1.PodMetricsEndpoint(e.endpoint())
+}
+
+func monitoringDuration(d time.Duration) monitoringv1.Duration {
+	if d == 0 {
+		return ""
+	}
+	return monitoringv1.Duration(model.Duration(d).String())
+}
+
+// ServiceMonitor holds a Prometheus Operator service monitor
+type ServiceMonitor struct {
+	monitoringv1.ServiceMonitor
+}
+
+type ServiceMonitorOpt func(*ServiceMonitor)
+
+// NewServiceMonitor returns a service monitor with the given name and options
+func NewServiceMonitor(name string, opts ...ServiceMonitorOpt) ServiceMonitor {
+	sm := ServiceMonitor{
+		ServiceMonitor: monitoringv1.ServiceMonitor{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ServiceMonitor",
+				APIVersion: monitoringv1.APIVersion,
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&sm)
+	}
+
+	return sm
+}
+
+// ServiceMonitorNamespace sets the namespace for the service monitor
+func ServiceMonitorNamespace(n string) ServiceMonitorOpt {
+	return func(sm *ServiceMonitor) {
+		setNamespace(n, &sm.ObjectMeta)
+	}
+}
+
+// ServiceMonitorLabel adds a label to the service monitor. Prometheus selects monitors by their labels
+func ServiceMonitorLabel(key, value string) ServiceMonitorOpt {
+	return func(sm *ServiceMonitor) {
+		addLabel(key, value, &sm.ObjectMeta)
+	}
+}
+
+// ServiceMonitorSelector adds a label to the selector of the scraped services
+func ServiceMonitorSelector(key, value string) ServiceMonitorOpt {
+	return func(sm *ServiceMonitor) {
+		metav1.AddLabelToSelector(&sm.Spec.Selector, key, value)
+	}
+}
+
+// ServiceMonitorNamespaces scrapes services in the namespaces instead of the service monitor namespace
+func ServiceMonitorNamespaces(namespaces ...string) ServiceMonitorOpt {
+	namespaces = slices.Clone(namespaces)
+	return func(sm *ServiceMonitor) {
+		sm.Spec.NamespaceSelector.MatchNames = slices.Clone(namespaces)
+	}
+}
+
+// ServiceMonitorJobLabel sets the service label whose value is used as the job name
+func ServiceMonitorJobLabel(label string) ServiceMonitorOpt {
+	return func(sm *ServiceMonitor) {
+		sm.Spec.JobLabel = label
+	}
+}
+
+// ServiceMonitorEndpoint adds a scraped endpoint. The port is the name of a service port
+func ServiceMonitorEndpoint(e MetricsEndpoint) ServiceMonitorOpt {
+	return func(sm *ServiceMonitor) {
+		sm.Spec.Endpoints = append(sm.Spec.Endpoints, e.endpoint())
+	}
+}
+
+// ServiceMonitorService scrapes the service. The namespace and the selector are copied from the service
+// namespace and labels, and an endpoint is added for each named service port with the other settings of e.
+// Unnamed ports are skipped since endpoints refer to ports by name. Services without labels return an error, as
+// an empty selector would scrape every service in the namespace
+func ServiceMonitorService(s Service, e MetricsEndpoint) (ServiceMonitorOpt, error) {
+	if len(s.Labels) == 0 {
+		return nil, fmt.Errorf("service %s has no labels to select it by", s.Name)
+	}
+
+	namespace := s.Namespace
+	labels := make(map[string]string)
+	for k, v := range s.Labels {
+		labels[k] = v
+	}
+
+	var endpoints []monitoringv1.Endpoint
+	for _, v := range s.Spec.Ports {
+		if v.Name == "" {
+			continue
+		}
+		e.Port = v.Name
+		endpoints = append(endpoints, e.endpoint())
+	}
+
+	return func(sm *ServiceMonitor) {
+		setNamespace(namespace, &sm.ObjectMeta)
+		for k, v := range labels {
+			metav1.AddLabelToSelector(&sm.Spec.Selector, k, v)
+		}
+		sm.Spec.Endpoints = append(sm.Spec.Endpoints, endpoints...)
+	}, nil
+}
+
+// Apply applies the options to the service monitor
+func (sm *ServiceMonitor) Apply(opts ...ServiceMonitorOpt) {
+	for _, opt := range opts {
+		opt(sm)
+	}
+}
+
+// With returns a copy of the service monitor with the options applied
+func (sm ServiceMonitor) With(opts ...ServiceMonitorOpt) ServiceMonitor {
+	clone := sm.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the service monitor
+func (sm ServiceMonitor) Clone() ServiceMonitor {
+	return ServiceMonitor{ServiceMonitor: deepCopyJSON(sm.ServiceMonitor)}
+}
+
+// Validate checks the metadata, the selector and that every endpoint names a port and has a valid scheme, path
+// and scrape interval and timeout
+func (sm ServiceMonitor) Validate() error {
+	errs := validateObjectMeta(&sm.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateMonitorSelectors(sm.Spec.Selector, sm.Spec.NamespaceSelector, path)...)
+	if len(sm.Spec.Endpoints) == 0 {
+		errs = append(errs, field.Required(path.Child("endpoints"), "at least one endpoint is required"))
+	}
+	for i, e := range sm.Spec.Endpoints {
+		errs = append(errs, validateScrapeEndpoint(e.Port, e.Path, e.Scheme, e.Interval, e.ScrapeTimeout, path.Child("endpoints").Index(i))...)
+	}
+
+	return toAggregate(errs)
+}
+
+func validateMonitorSelectors(s metav1.LabelSelector, ns monitoringv1.NamespaceSelector, path *field.Path) field.ErrorList {
+	errs := metav1validation.ValidateLabelSelector(&s, metav1validation.LabelSelectorValidationOptions{}, path.Child("selector"))
+	if ns.Any && len(ns.MatchNames) > 0 {
+		errs = append(errs, field.Invalid(path.Child("namespaceSelector"), ns, "any and matchNames cannot both be set"))
+	}
+	for i, n := range ns.MatchNames {
+		errs = append(errs, validateDNSLabel(n, path.Child("namespaceSelector", "matchNames").Index(i))...)
+	}
+
+	return errs
+}
+
+func validateScrapeEndpoint(port, metricsPath, scheme string, interval, timeout monitoringv1.Duration, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if port == "" {
+		errs = append(errs, field.Required(path.Child("port"), "must name a port"))
+	}
+	if metricsPath != "" && !strings.HasPrefix(metricsPath, "/") {
+		errs = append(errs, field.Invalid(path.Child("path"), metricsPath, "must be an absolute path"))
+	}
+	errs = append(errs, validateEnum(scheme, path.Child("scheme"), "http", "https")...)
+
+	var i, t model.Duration
+	var err error
+	if interval != "" {
+		if i, err = model.ParseDuration(string(interval)); err != nil {
+			errs = append(errs, field.Invalid(path.Child("interval"), interval, err.Error()))
+		}
+	}
+	if timeout != "" {
+		if t, err = model.ParseDuration(string(timeout)); err != nil {
+			errs = append(errs, field.Invalid(path.Child("scrapeTimeout"), timeout, err.Error()))
+		}
+	}
+	if i > 0 && t > i {
+		errs = append(errs, field.Invalid(path.Child("scrapeTimeout"), timeout, "must not be greater than the interval"))
+	}
+
+	return errs
+}
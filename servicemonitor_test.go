@@ -0,0 +1,126 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestServiceMonitorService(t *testing.T) {
+	s := NewService("web",
+		ServiceNamespace("apps"),
+		ServiceLabel("app", "web"),
+		ServiceNamedPort("http", 80, 8080),
+		ServiceNamedPort("metrics", 9090, 9090),
+		ServicePort(8443, 8443),
+	)
+
+	opt, err := ServiceMonitorService(s, MetricsEndpoint{Path: "/metrics", Interval: 30 * time.Second})
+	if err != nil {
+		t.Fatalf("ServiceMonitorService() = %v", err)
+	}
+	sm := NewServiceMonitor("web", opt, ServiceMonitorLabel("release", "prometheus"))
+
+	if sm.Namespace != "apps" {
+		t.Errorf("namespace = %q, want apps", sm.Namespace)
+	}
+	if want := map[string]string{"app": "web"}; !reflect.DeepEqual(sm.Spec.Selector.MatchLabels, want) {
+		t.Errorf("selector = %v, want %v", sm.Spec.Selector.MatchLabels, want)
+	}
+	var ports []string
+	for _, e := range sm.Spec.Endpoints {
+		ports = append(ports, e.Port)
+		if e.Interval != "30s" || e.Path != "/metrics" {
+			t.Errorf("endpoint %s = %+v, want the /metrics path every 30s", e.Port, e)
+		}
+	}
+	if want := []string{"http", "metrics"}; !reflect.DeepEqual(ports, want) {
+		t.Errorf("endpoint ports = %v, want the named ports %v", ports, want)
+	}
+	if err := sm.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	sm.Spec.Selector.MatchLabels["app"] = "changed"
+	if got := NewServiceMonitor("second", opt).Spec.Selector.MatchLabels["app"]; got != "web" {
+		t.Errorf("selector app = %q for a second monitor, want web", got)
+	}
+
+	if _, err := ServiceMonitorService(NewService("unlabelled"), MetricsEndpoint{}); err == nil {
+		t.Error("ServiceMonitorService() without labels = nil, want an error")
+	}
+}
+
+func TestServiceMonitorNamespaces(t *testing.T) {
+	namespaces := []string{"apps", "jobs"}
+	opt := ServiceMonitorNamespaces(namespaces...)
+	sm := NewServiceMonitor("web", opt)
+	namespaces[0] = "changed"
+	sm.Spec.NamespaceSelector.MatchNames[1] = "changed"
+
+	if got := NewServiceMonitor("second", opt).Spec.NamespaceSelector.MatchNames; !reflect.DeepEqual(got, []string{"apps", "jobs"}) {
+		t.Errorf("namespaces = %v for a second monitor, want [apps jobs]", got)
+	}
+}
+
+func TestServiceMonitorValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []ServiceMonitorOpt
+		fields []string
+	}{
+		{
+			name:   "without endpoints",
+			opts:   []ServiceMonitorOpt{ServiceMonitorSelector("app", "web")},
+			fields: []string{"spec.endpoints"},
+		},
+		{
+			name:   "endpoint without port",
+			opts:   []ServiceMonitorOpt{ServiceMonitorEndpoint(MetricsEndpoint{Path: "/metrics"})},
+			fields: []string{"spec.endpoints[0].port"},
+		},
+		{
+			name:   "relative path and unknown scheme",
+			opts:   []ServiceMonitorOpt{ServiceMonitorEndpoint(MetricsEndpoint{Port: "metrics", Path: "metrics", Scheme: "tcp"})},
+			fields: []string{"spec.endpoints[0].path", "spec.endpoints[0].scheme"},
+		},
+		{
+			name:   "timeout over the interval",
+			opts:   []ServiceMonitorOpt{ServiceMonitorEndpoint(MetricsEndpoint{Port: "metrics", Interval: 10 * time.Second, ScrapeTimeout: time.Minute})},
+			fields: []string{"spec.endpoints[0].scrapeTimeout"},
+		},
+		{
+			name: "any and named namespaces",
+			opts: []ServiceMonitorOpt{
+				ServiceMonitorEndpoint(MetricsEndpoint{Port: "metrics"}),
+				ServiceMonitorNamespaces("apps"),
+				func(sm *ServiceMonitor) { sm.Spec.NamespaceSelector.Any = true },
+			},
+			fields: []string{"spec.namespaceSelector"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := NewServiceMonitor("web", append([]ServiceMonitorOpt{ServiceMonitorNamespace("apps")}, tt.opts...)...)
+			fields := validationFields(t, sm.Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"maps"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// StatefulSet holds a Kubernetes stateful set
+type StatefulSet struct {
+	appsv1.StatefulSet
+}
+
+type StatefulSetOpt func(*StatefulSet)
+
+// NewStatefulSet returns a stateful set with the given name and options
+func NewStatefulSet(name string, opts ...StatefulSetOpt) *StatefulSet {
+	s := &StatefulSet{
+		appsv1.StatefulSet{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "StatefulSet",
+				APIVersion: "apps/v1",
+			},
+			ObjectMeta: newObjectMeta(name),
+			Spec: appsv1.StatefulSetSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: make(map[string]string),
+				},
+				Template: corev1.PodTemplateSpec{},
+			},
+		},
+	}
+
+	for _, v := range opts {
+		v(s)
+	}
+
+	return s
+}
+
+// StatefulSetNamespace sets the namespace for the stateful set
+func StatefulSetNamespace(n string) StatefulSetOpt {
+	return func(s *StatefulSet) {
+		setNamespace(n, &s.ObjectMeta)
+	}
+}
+
+// StatefulSetSelector adds a stateful set selector
+func StatefulSetSelector(key, value string) StatefulSetOpt {
+	return func(s *StatefulSet) {
+		metav1.AddLabelToSelector(s.Spec.Selector, key, value)
+	}
+}
+
+// StatefulSetLabel adds a single stateful set label
+func StatefulSetLabel(key, value string) StatefulSetOpt {
+	return func(s *StatefulSet) {
+		addLabel(key, value, &s.ObjectMeta)
+	}
+}
+
+// StatefulSetLabels adds multiple stateful set labels
+func StatefulSetLabels(labels map[string]string) StatefulSetOpt {
+	return func(s *StatefulSet) {
+		for k, v := range labels {
+			addLabel(k, v, &s.ObjectMeta)
+		}
+	}
+}
+
+// StatefulSetPodSpec sets the pod spec for the stateful set
+func StatefulSetPodSpec(p PodSpec) StatefulSetOpt {
+	return func(s *StatefulSet) {
+		s.Spec.Template = p.template(s.Spec.Template)
+	}
+}
+
+// StatefulSetReplicas sets the replicas for the stateful set
+func StatefulSetReplicas(r int) StatefulSetOpt {
+	return func(s *StatefulSet) {
+		replicas := int32(r)
+		s.Spec.Replicas = &replicas
+	}
+}
+
+// StatefulSetNodeSelector sets the node selector for the stateful set pods
+func StatefulSetNodeSelector(selectors map[string]string) StatefulSetOpt {
+	return func(s *StatefulSet) {
+		s.Spec.Template.Spec.NodeSelector = maps.Clone(selectors)
+	}
+}
+
+// StatefulSetTolerations sets the tolerations for the stateful set pods
+func StatefulSetTolerations(tolerations []Toleration) StatefulSetOpt {
+	return func(s *StatefulSet) {
+		s.Spec.Template.Spec.Tolerations = coreTolerations(tolerations)
+	}
+}
+
+// StatefulSetServiceName sets the name of the headless service governing the stateful set
+func StatefulSetServiceName(name string) StatefulSetOpt {
+	return func(s *StatefulSet) {
+		s.Spec.ServiceName = name
+	}
+}
+
+// StatefulSetService sets the governing service of the stateful set from a headless service
+func StatefulSetService(svc Service) StatefulSetOpt {
+	return StatefulSetServiceName(svc.Name)
+}
+
+// StatefulSetPodManagementPolicy sets the pod management policy for the stateful set
+func StatefulSetPodManagementPolicy(p appsv1.PodManagementPolicyType) StatefulSetOpt {
+	return func(s *StatefulSet) {
+		s.Spec.PodManagementPolicy = p
+	}
+}
+
+// StatefulSetRollingUpdate sets a rolling update strategy. Pods with an ordinal below the partition are not updated
+func StatefulSetRollingUpdate(partition int) StatefulSetOpt {
+	return func(s *StatefulSet) {
+		p := int32(partition)
+		s.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+			Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+				Partition: &p,
+			},
+		}
+	}
+}
+
+// StatefulSetOnDelete sets the on delete update strategy for the stateful set
+func StatefulSetOnDelete() StatefulSetOpt {
+	return func(s *StatefulSet) {
+		s.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+			Type: appsv1.OnDeleteStatefulSetStrategyType,
+		}
+	}
+}
+
+// StatefulSetPVCRetentionPolicy sets what happens to the claims created from the volume claim templates
+// when the stateful set is deleted or scaled down
+func StatefulSetPVCRetentionPolicy(whenDeleted, whenScaled appsv1.PersistentVolumeClaimRetentionPolicyType) StatefulSetOpt {
+	return func(s *StatefulSet) {
+		s.Spec.PersistentVolumeClaimRetentionPolicy = &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+			WhenDeleted: whenDeleted,
+			WhenScaled:  whenScaled,
+		}
+	}
+}
+
+// StatefulSetVolumeClaimTemplate adds a volume claim template. Containers mount the claim by name with ContainerVolumeClaim
+func StatefulSetVolumeClaimTemplate(pvc PersistentVolumeClaim) StatefulSetOpt {
+	claim := *pvc.PersistentVolumeClaim.DeepCopy()
+	claim.TypeMeta = metav1.TypeMeta{}
+	claim.Namespace = ""
+	return func(s *StatefulSet) {
+		s.Spec.VolumeClaimTemplates = append(s.Spec.VolumeClaimTemplates, *claim.DeepCopy())
+	}
+}
+
+// Apply applies the options to the stateful set
+func (s *StatefulSet) Apply(opts ...StatefulSetOpt) {
+	for _, opt := range opts {
+		opt(s)
+	}
+}
+
+// With returns a copy of the stateful set with the options applied
+func (s *StatefulSet) With(opts ...StatefulSetOpt) *StatefulSet {
+	clone := s.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the stateful set
+func (s *StatefulSet) Clone() *StatefulSet {
+	return &StatefulSet{StatefulSet: *s.StatefulSet.DeepCopy()}
+}
+
+// Validate checks the metadata, that the selector matches the pod template and that the pod template is valid.
+// Volume claim template names must be DNS labels since the pods mount them as volumes by name
+func (s StatefulSet) Validate() error {
+	errs := validateObjectMeta(&s.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateNonNegative(s.Spec.Replicas, path.Child("replicas"))...)
+	errs = append(errs, validateSelector(s.Spec.Selector, s.Spec.Template.Labels, path.Child("selector"))...)
+	errs = append(errs, validatePodTemplate(s.Spec.Template, path.Child("template"), corev1.RestartPolicyAlways)...)
+	errs = append(errs, validateEnum(s.Spec.PodManagementPolicy, path.Child("podManagementPolicy"),
+		appsv1.OrderedReadyPodManagement, appsv1.ParallelPodManagement)...)
+	errs = append(errs, validateEnum(s.Spec.UpdateStrategy.Type, path.Child("updateStrategy", "type"),
+		appsv1.RollingUpdateStatefulSetStrategyType, appsv1.OnDeleteStatefulSetStrategyType)...)
+
+	for i, pvc := range s.Spec.VolumeClaimTemplates {
+		errs = append(errs, validateDNSLabel(pvc.Name, path.Child("volumeClaimTemplates").Index(i).Child("metadata", "name"))...)
+	}
+
+	return toAggregate(errs)
+}
@@ -0,0 +1,153 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStatefulSetHeadlessService(t *testing.T) {
+	svc := NewService("db", ServiceHeadless(), ServiceSelector("app", "db"))
+	s := NewStatefulSet("db", StatefulSetService(svc))
+
+	if s.Spec.ServiceName != "db" {
+		t.Errorf("serviceName = %q, want db", s.Spec.ServiceName)
+	}
+	if svc.Spec.ClusterIP != corev1.ClusterIPNone {
+		t.Errorf("clusterIP = %q, want %q", svc.Spec.ClusterIP, corev1.ClusterIPNone)
+	}
+}
+
+func TestStatefulSetVolumeClaimTemplate(t *testing.T) {
+	pvc := NewPersistentVolumeClaim("data",
+		PersistentVolumeClaimNamespace("prod"),
+		PersistentVolumeClaimAccessModes(corev1.ReadWriteOnce),
+		PersistentVolumeClaimStorage(resource.MustParse("10Gi")),
+	)
+	opt := StatefulSetVolumeClaimTemplate(pvc)
+	s := NewStatefulSet("db", opt)
+
+	if len(s.Spec.VolumeClaimTemplates) != 1 {
+		t.Fatalf("volumeClaimTemplates = %d, want 1", len(s.Spec.VolumeClaimTemplates))
+	}
+
+	claim := s.Spec.VolumeClaimTemplates[0]
+	if claim.TypeMeta != (metav1.TypeMeta{}) || claim.Namespace != "" {
+		t.Errorf("template keeps type meta %+v and namespace %q, want neither", claim.TypeMeta, claim.Namespace)
+	}
+	if got := claim.Spec.Resources.Requests[corev1.ResourceStorage]; got.String() != "10Gi" {
+		t.Errorf("storage request = %s, want 10Gi", got.String())
+	}
+
+	claim.Labels["tier"] = "db"
+	if _, ok := NewStatefulSet("other", opt).Spec.VolumeClaimTemplates[0].Labels["tier"]; ok {
+		t.Error("changing one template changed another stateful set built from the same option")
+	}
+}
+
+func TestStatefulSetSharedOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		opt    StatefulSetOpt
+		mutate func(*StatefulSet)
+		check  func(*StatefulSet) bool
+	}{
+		{
+			name:   "replicas",
+			opt:    StatefulSetReplicas(3),
+			mutate: func(s *StatefulSet) { *s.Spec.Replicas = 1 },
+			check:  func(s *StatefulSet) bool { return *s.Spec.Replicas == 3 },
+		},
+		{
+			name:   "rolling update partition",
+			opt:    StatefulSetRollingUpdate(2),
+			mutate: func(s *StatefulSet) { *s.Spec.UpdateStrategy.RollingUpdate.Partition = 0 },
+			check:  func(s *StatefulSet) bool { return *s.Spec.UpdateStrategy.RollingUpdate.Partition == 2 },
+		},
+		{
+			name:   "tolerations",
+			opt:    StatefulSetTolerations([]Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}),
+			mutate: func(s *StatefulSet) { s.Spec.Template.Spec.Tolerations[0].Key = "spot" },
+			check:  func(s *StatefulSet) bool { return s.Spec.Template.Spec.Tolerations[0].Key == "dedicated" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := NewStatefulSet("first", tt.opt)
+			second := NewStatefulSet("second", tt.opt)
+
+			tt.mutate(first)
+			if !tt.check(second) {
+				t.Error("changing one stateful set changed another built from the same option")
+			}
+		})
+	}
+}
+
+func TestStatefulSetValidate(t *testing.T) {
+	pod := NewPodSpec("db", PodLabel("app", "db"), PodContainer(NewContainer("db", ContainerImage("postgres"))))
+
+	tests := []struct {
+		name   string
+		opts   []StatefulSetOpt
+		fields []string
+	}{
+		{
+			name: "valid",
+			opts: []StatefulSetOpt{
+				StatefulSetSelector("app", "db"),
+				StatefulSetPodSpec(pod),
+				StatefulSetServiceName("db"),
+				StatefulSetPodManagementPolicy(appsv1.ParallelPodManagement),
+				StatefulSetVolumeClaimTemplate(NewPersistentVolumeClaim("data")),
+			},
+		},
+		{
+			name:   "no selector",
+			opts:   []StatefulSetOpt{StatefulSetPodSpec(pod)},
+			fields: []string{"spec.selector"},
+		},
+		{
+			name:   "unknown pod management policy",
+			opts:   []StatefulSetOpt{StatefulSetSelector("app", "db"), StatefulSetPodSpec(pod), StatefulSetPodManagementPolicy("Random")},
+			fields: []string{"spec.podManagementPolicy"},
+		},
+		{
+			name: "volume claim template name is not a volume name",
+			opts: []StatefulSetOpt{
+				StatefulSetSelector("app", "db"),
+				StatefulSetPodSpec(pod),
+				StatefulSetVolumeClaimTemplate(NewPersistentVolumeClaim("data.db")),
+			},
+			fields: []string{"spec.volumeClaimTemplates[0].metadata.name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, NewStatefulSet("db", tt.opts...).Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// StorageClass holds a Kubernetes storage class
+type StorageClass struct {
+	storagev1.StorageClass
+}
+
+type StorageClassOpt func(*StorageClass)
+
+// NewStorageClass returns a storage class with the given name and options
+func NewStorageClass(name string, opts ...StorageClassOpt) StorageClass {
+	sc := StorageClass{
+		StorageClass: storagev1.StorageClass{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "StorageClass",
+				APIVersion: "storage.k8s.io/v1",
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&sc)
+	}
+
+	return sc
+}
+
+// StorageClassProvisioner sets the provisioner for the storage class
+func StorageClassProvisioner(p string) StorageClassOpt {
+	return func(sc *StorageClass) {
+		sc.Provisioner = p
+	}
+}
+
+// StorageClassParameter sets a single provisioner parameter
+func StorageClassParameter(key, value string) StorageClassOpt {
+	return func(sc *StorageClass) {
+		if sc.Parameters == nil {
+			sc.Parameters = make(map[string]string)
+		}
+		sc.Parameters[key] = value
+	}
+}
+
+// StorageClassParameters sets multiple provisioner parameters
+func StorageClassParameters(params map[string]string) StorageClassOpt {
+	return func(sc *StorageClass) {
+		for k, v := range params {
+			StorageClassParameter(k, v)(sc)
+		}
+	}
+}
+
+// StorageClassReclaimPolicy sets the reclaim policy for volumes provisioned by the storage class
+func StorageClassReclaimPolicy(p corev1.PersistentVolumeReclaimPolicy) StorageClassOpt {
+	return func(sc *StorageClass) {
+		sc.ReclaimPolicy = ptrTo(p)
+	}
+}
+
+// StorageClassVolumeBindingMode sets when volumes are provisioned and bound
+func StorageClassVolumeBindingMode(m storagev1.VolumeBindingMode) StorageClassOpt {
+	return func(sc *StorageClass) {
+		sc.VolumeBindingMode = ptrTo(m)
+	}
+}
+
+// StorageClassAllowVolumeExpansion sets whether claims of the storage class can be expanded
+func StorageClassAllowVolumeExpansion(b bool) StorageClassOpt {
+	return func(sc *StorageClass) {
+		sc.AllowVolumeExpansion = ptrTo(b)
+	}
+}
+
+// StorageClassMountOptions sets the mount options for volumes provisioned by the storage class
+func StorageClassMountOptions(options ...string) StorageClassOpt {
+	return func(sc *StorageClass) {
+		sc.MountOptions = slices.Clone(options)
+	}
+}
+
+// StorageClassAllowedTopology adds a topology term. Volumes can be provisioned where the label has one of the values
+func StorageClassAllowedTopology(key string, values ...string) StorageClassOpt {
+	return func(sc *StorageClass) {
+		sc.AllowedTopologies = append(sc.AllowedTopologies, corev1.TopologySelectorTerm{
+			MatchLabelExpressions: []corev1.TopologySelectorLabelRequirement{
+				{
+					Key:    key,
+					Values: slices.Clone(values),
+				},
+			},
+		})
+	}
+}
+
+// StorageClassDefault marks the storage class as the cluster default
+func StorageClassDefault() StorageClassOpt {
+	return func(sc *StorageClass) {
+		addAnnotation("storageclass.kubernetes.io/is-default-class", "true", &sc.ObjectMeta)
+	}
+}
+
+// Apply applies the options to the storage class
+func (sc *StorageClass) Apply(opts ...StorageClassOpt) {
+	for _, opt := range opts {
+		opt(sc)
+	}
+}
+
+// With returns a copy of the storage class with the options applied
+func (sc StorageClass) With(opts ...StorageClassOpt) StorageClass {
+	clone := sc.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the storage class
+func (sc StorageClass) Clone() StorageClass {
+	return StorageClass{StorageClass: *sc.StorageClass.DeepCopy()}
+}
+
+// Validate checks the metadata, that the provisioner is set and the reclaim policy and volume binding mode
+func (sc StorageClass) Validate() error {
+	errs := validateObjectMeta(&sc.ObjectMeta, false, apimachineryvalidation.NameIsDNSSubdomain)
+
+	if sc.Provisioner == "" {
+		errs = append(errs, field.Required(field.NewPath("provisioner"), ""))
+	} else {
+		for _, msg := range validation.IsQualifiedName(strings.ToLower(sc.Provisioner)) {
+			errs = append(errs, field.Invalid(field.NewPath("provisioner"), sc.Provisioner, msg))
+		}
+	}
+	errs = append(errs, validateEnum(ptrValue(sc.ReclaimPolicy), field.NewPath("reclaimPolicy"),
+		corev1.PersistentVolumeReclaimDelete, corev1.PersistentVolumeReclaimRetain)...)
+	errs = append(errs, validateEnum(ptrValue(sc.VolumeBindingMode), field.NewPath("volumeBindingMode"),
+		storagev1.VolumeBindingImmediate, storagev1.VolumeBindingWaitForFirstConsumer)...)
+
+	return toAggregate(errs)
+}
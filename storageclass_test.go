@@ -0,0 +1,131 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+
+	snapshotv1 "github.com/CoverWhale/kopts/apis/snapshot/v1"
+)
+
+func TestStorageClass(t *testing.T) {
+	zones := []string{"us-east-1a", "us-east-1b"}
+	sc := NewStorageClass("gp3",
+		StorageClassProvisioner("ebs.csi.aws.com"),
+		StorageClassParameters(map[string]string{"type": "gp3", "encrypted": "true"}),
+		StorageClassReclaimPolicy(corev1.PersistentVolumeReclaimRetain),
+		StorageClassVolumeBindingMode(storagev1.VolumeBindingWaitForFirstConsumer),
+		StorageClassAllowVolumeExpansion(true),
+		StorageClassAllowedTopology("topology.ebs.csi.aws.com/zone", zones...),
+		StorageClassDefault(),
+	)
+	zones[0] = "us-west-2a"
+
+	if want := map[string]string{"type": "gp3", "encrypted": "true"}; !reflect.DeepEqual(sc.Parameters, want) {
+		t.Errorf("parameters = %v, want %v", sc.Parameters, want)
+	}
+	if got := sc.AllowedTopologies[0].MatchLabelExpressions[0].Values; !reflect.DeepEqual(got, []string{"us-east-1a", "us-east-1b"}) {
+		t.Errorf("topology values = %v after changing the caller's slice", got)
+	}
+	if sc.Annotations["storageclass.kubernetes.io/is-default-class"] != "true" {
+		t.Errorf("annotations = %v, want the default class annotation", sc.Annotations)
+	}
+	if err := sc.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestStorageClassPVC(t *testing.T) {
+	sc := NewStorageClass("gp3", StorageClassProvisioner("ebs.csi.aws.com"))
+	pvc := NewPersistentVolumeClaim("data", PersistentVolumeClaimStorageClass(sc))
+
+	if got := ptrValue(pvc.Spec.StorageClassName); got != "gp3" {
+		t.Errorf("storageClassName = %q, want gp3", got)
+	}
+}
+
+func TestVolumeSnapshot(t *testing.T) {
+	vsc := NewVolumeSnapshotClass("csi-snapshots", VolumeSnapshotClassDriver("ebs.csi.aws.com"), VolumeSnapshotClassDefault())
+	pvc := NewPersistentVolumeClaim("data", PersistentVolumeClaimNamespace("prod"))
+	opt := VolumeSnapshotClaim(pvc)
+	vs := NewVolumeSnapshot("nightly", opt, VolumeSnapshotClassRef(vsc))
+
+	if vs.Namespace != "prod" {
+		t.Errorf("namespace = %q, want prod", vs.Namespace)
+	}
+	if got := ptrValue(vs.Spec.Source.PersistentVolumeClaimName); got != "data" {
+		t.Errorf("source claim = %q, want data", got)
+	}
+	if got := ptrValue(vs.Spec.VolumeSnapshotClassName); got != "csi-snapshots" {
+		t.Errorf("volumeSnapshotClassName = %q, want csi-snapshots", got)
+	}
+	if vsc.DeletionPolicy != snapshotv1.VolumeSnapshotContentDelete {
+		t.Errorf("deletionPolicy = %q, want Delete", vsc.DeletionPolicy)
+	}
+
+	*vs.Spec.Source.PersistentVolumeClaimName = "other"
+	if got := ptrValue(NewVolumeSnapshot("second", opt).Spec.Source.PersistentVolumeClaimName); got != "data" {
+		t.Errorf("source claim = %q for a second snapshot, want data", got)
+	}
+}
+
+func TestStorageValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		object Validator
+		fields []string
+	}{
+		{
+			name:   "storage class without provisioner",
+			object: NewStorageClass("gp3"),
+			fields: []string{"provisioner"},
+		},
+		{
+			name:   "unknown reclaim policy",
+			object: NewStorageClass("gp3", StorageClassProvisioner("ebs.csi.aws.com"), StorageClassReclaimPolicy("Recycle")),
+			fields: []string{"reclaimPolicy"},
+		},
+		{
+			name:   "snapshot class without driver",
+			object: NewVolumeSnapshotClass("csi-snapshots", VolumeSnapshotClassDeletionPolicy("Keep")),
+			fields: []string{"deletionPolicy", "driver"},
+		},
+		{
+			name:   "snapshot without source",
+			object: NewVolumeSnapshot("nightly"),
+			fields: []string{"spec.source"},
+		},
+		{
+			name: "snapshot with two sources",
+			object: NewVolumeSnapshot("nightly", VolumeSnapshotClaim(NewPersistentVolumeClaim("data")), func(vs *VolumeSnapshot) {
+				vs.Spec.Source.VolumeSnapshotContentName = ptrTo("nightly-content")
+			}),
+			fields: []string{"spec.source"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, tt.object.Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -14,16 +14,50 @@
 
 package kopts
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	corev1 "k8s.io/api/core/v1"
+)
 
 type TolerationOperator = corev1.TolerationOperator
 type TaintEffect = corev1.TaintEffect
 
+// Toleration holds a pod toleration. TolerationSeconds is only valid with the NoExecute effect and is the time the pod
+// stays bound after the taint is added. Nil tolerates the taint forever and zero evicts the pod immediately
 type Toleration struct {
-	corev1.Toleration
 	Key               string
 	Value             string
-	TolerationSeconds int
+	TolerationSeconds *int
 	Operator          corev1.TolerationOperator
 	Effect            corev1.TaintEffect
 }
+
+func coreTolerations(tolerations []Toleration) []corev1.Toleration {
+	var coreTolerations []corev1.Toleration
+	for _, v := range tolerations {
+		t := corev1.Toleration{
+			Key:      v.Key,
+			Value:    v.Value,
+			Operator: v.Operator,
+			Effect:   v.Effect,
+		}
+		if v.TolerationSeconds != nil {
+			t.TolerationSeconds = ptrTo(int64(*v.TolerationSeconds))
+		}
+		coreTolerations = append(coreTolerations, t)
+	}
+
+	return coreTolerations
+}
+
+// Clone returns a deep copy of the toleration
+func (t Toleration) Clone() Toleration {
+	if t.TolerationSeconds != nil {
+		t.TolerationSeconds = ptrTo(*t.TolerationSeconds)
+	}
+	return t
+}
+
+// Validate checks the toleration operator, effect and key
+func (t Toleration) Validate() error {
+	return toAggregate(validateToleration(coreTolerations([]Toleration{t})[0], nil))
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCoreTolerations(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds *int
+		want    *int64
+	}{
+		{name: "tolerate forever", seconds: nil, want: nil},
+		{name: "evict immediately", seconds: ptrTo(0), want: ptrTo[int64](0)},
+		{name: "evict after a delay", seconds: ptrTo(300), want: ptrTo[int64](300)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tol := Toleration{
+				Key:               "node.kubernetes.io/unreachable",
+				Operator:          corev1.TolerationOpExists,
+				Effect:            corev1.TaintEffectNoExecute,
+				TolerationSeconds: tt.seconds,
+			}
+
+			got := coreTolerations([]Toleration{tol})[0].TolerationSeconds
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TolerationSeconds = %v, want %v", got, tt.want)
+			}
+			if err := tol.Validate(); err != nil {
+				t.Errorf("Validate() = %v", err)
+			}
+		})
+	}
+}
+
+func TestTolerationClone(t *testing.T) {
+	tol := Toleration{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute, TolerationSeconds: ptrTo(30)}
+	clone := tol.Clone()
+	*clone.TolerationSeconds = 60
+
+	if *tol.TolerationSeconds != 30 {
+		t.Errorf("TolerationSeconds = %d after changing the clone, want 30", *tol.TolerationSeconds)
+	}
+}
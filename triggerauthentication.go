@@ -0,0 +1,155 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"sort"
+
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	kedav1alpha1 "github.com/CoverWhale/kopts/apis/keda/v1alpha1"
+)
+
+// TriggerAuthentication holds a KEDA trigger authentication
+type TriggerAuthentication struct {
+	kedav1alpha1.TriggerAuthentication
+}
+
+type TriggerAuthenticationOpt func(*TriggerAuthentication)
+
+// NewTriggerAuthentication returns a trigger authentication with the given name and options
+func NewTriggerAuthentication(name string, opts ...TriggerAuthenticationOpt) TriggerAuthentication {
+	ta := TriggerAuthentication{
+		TriggerAuthentication: kedav1alpha1.TriggerAuthentication{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "TriggerAuthentication",
+				APIVersion: kedav1alpha1.APIVersion,
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&ta)
+	}
+
+	return ta
+}
+
+// TriggerAuthenticationNamespace sets the namespace for the trigger authentication
+func TriggerAuthenticationNamespace(n string) TriggerAuthenticationOpt {
+	return func(ta *TriggerAuthentication) {
+		setNamespace(n, &ta.ObjectMeta)
+	}
+}
+
+// TriggerAuthenticationSecretKey sets the trigger parameter from the secret key. The trigger authentication
+// is placed in the secret namespace since secrets are only read from the same namespace
+func TriggerAuthenticationSecretKey(parameter string, s Secret, key string) TriggerAuthenticationOpt {
+	name := s.Name
+	namespace := s.Namespace
+	return func(ta *TriggerAuthentication) {
+		setNamespace(namespace, &ta.ObjectMeta)
+		ta.Spec.SecretTargetRef = append(ta.Spec.SecretTargetRef, kedav1alpha1.AuthSecretTargetRef{
+			Parameter: parameter,
+			Name:      name,
+			Key:       key,
+		})
+	}
+}
+
+// TriggerAuthenticationSecret sets a trigger parameter for every key of the secret, named after the key.
+// The trigger authentication is placed in the secret namespace
+func TriggerAuthenticationSecret(s Secret) TriggerAuthenticationOpt {
+	var keys []string
+	for k := range s.Data {
+		keys = append(keys, k)
+	}
+	for k := range s.StringData {
+		if _, ok := s.Data[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var opts []TriggerAuthenticationOpt
+	for _, k := range keys {
+		opts = append(opts, TriggerAuthenticationSecretKey(k, s, k))
+	}
+
+	return func(ta *TriggerAuthentication) {
+		for _, v := range opts {
+			v(ta)
+		}
+	}
+}
+
+// TriggerAuthenticationPodIdentity authenticates with the workload identity of the provider, such as aws or azure-workload
+func TriggerAuthenticationPodIdentity(provider string) TriggerAuthenticationOpt {
+	return func(ta *TriggerAuthentication) {
+		ta.Spec.PodIdentity = &kedav1alpha1.AuthPodIdentity{
+			Provider: provider,
+		}
+	}
+}
+
+// Apply applies the options to the trigger authentication
+func (ta *TriggerAuthentication) Apply(opts ...TriggerAuthenticationOpt) {
+	for _, opt := range opts {
+		opt(ta)
+	}
+}
+
+// With returns a copy of the trigger authentication with the options applied
+func (ta TriggerAuthentication) With(opts ...TriggerAuthenticationOpt) TriggerAuthentication {
+	clone := ta.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the trigger authentication
+func (ta TriggerAuthentication) Clone() TriggerAuthentication {
+	return TriggerAuthentication{TriggerAuthentication: deepCopyJSON(ta.TriggerAuthentication)}
+}
+
+// Validate checks the metadata, that a pod identity or secret reference is set and that every secret reference
+// names the parameter, secret and key
+func (ta TriggerAuthentication) Validate() error {
+	errs := validateObjectMeta(&ta.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	if ta.Spec.PodIdentity == nil && len(ta.Spec.SecretTargetRef) == 0 {
+		errs = append(errs, field.Required(path, "one of podIdentity or secretTargetRef must be set"))
+	}
+	if ta.Spec.PodIdentity != nil && ta.Spec.PodIdentity.Provider == "" {
+		errs = append(errs, field.Required(path.Child("podIdentity", "provider"), ""))
+	}
+
+	for i, ref := range ta.Spec.SecretTargetRef {
+		refPath := path.Child("secretTargetRef").Index(i)
+		if ref.Parameter == "" {
+			errs = append(errs, field.Required(refPath.Child("parameter"), ""))
+		}
+		errs = append(errs, validateDNSSubdomain(ref.Name, refPath.Child("name"))...)
+		if ref.Key == "" {
+			errs = append(errs, field.Required(refPath.Child("key"), ""))
+		}
+	}
+
+	return toAggregate(errs)
+}
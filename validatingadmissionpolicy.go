@@ -0,0 +1,354 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"regexp"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidatingAdmissionPolicy holds a Kubernetes validating admission policy
+type ValidatingAdmissionPolicy struct {
+	admissionregistrationv1.ValidatingAdmissionPolicy
+}
+
+type ValidatingAdmissionPolicyOpt func(*ValidatingAdmissionPolicy)
+
+// PolicyValidation holds a CEL expression that must evaluate to true for the request to be admitted.
+// The message expression is evaluated when the validation fails and takes precedence over the message
+type PolicyValidation struct {
+	Expression        string
+	Message           string
+	MessageExpression string
+	Reason            metav1.StatusReason
+}
+
+// NewValidatingAdmissionPolicy returns a validating admission policy with the given name and options
+func NewValidatingAdmissionPolicy(name string, opts ...ValidatingAdmissionPolicyOpt) ValidatingAdmissionPolicy {
+	p := ValidatingAdmissionPolicy{
+		ValidatingAdmissionPolicy: admissionregistrationv1.ValidatingAdmissionPolicy{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ValidatingAdmissionPolicy",
+				APIVersion: "admissionregistration.k8s.io/v1",
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&p)
+	}
+
+	return p
+}
+
+// ValidatingAdmissionPolicyParamKind sets the kind of the parameter resource bindings refer to
+func ValidatingAdmissionPolicyParamKind(apiVersion, kind string) ValidatingAdmissionPolicyOpt {
+	return func(p *ValidatingAdmissionPolicy) {
+		p.Spec.ParamKind = &admissionregistrationv1.ParamKind{
+			APIVersion: apiVersion,
+			Kind:       kind,
+		}
+	}
+}
+
+// ValidatingAdmissionPolicyResource adds the operations on a resource of the API group and version to the
+// match constraints. Use an empty group for the core API group and * to match all groups, versions or resources
+func ValidatingAdmissionPolicyResource(group, version, resource string, operations ...admissionregistrationv1.OperationType) ValidatingAdmissionPolicyOpt {
+	return func(p *ValidatingAdmissionPolicy) {
+		m := p.matchConstraints()
+		m.ResourceRules = append(m.ResourceRules, admissionregistrationv1.NamedRuleWithOperations{
+			RuleWithOperations: admissionregistrationv1.RuleWithOperations{
+				Operations: operations,
+				Rule: admissionregistrationv1.Rule{
+					APIGroups:   []string{group},
+					APIVersions: []string{version},
+					Resources:   []string{resource},
+				},
+			},
+		})
+	}
+}
+
+// ValidatingAdmissionPolicyNamespaceSelector adds a label to the selector of the namespaces the policy applies to
+func ValidatingAdmissionPolicyNamespaceSelector(key, value string) ValidatingAdmissionPolicyOpt {
+	return func(p *ValidatingAdmissionPolicy) {
+		m := p.matchConstraints()
+		if m.NamespaceSelector == nil {
+			m.NamespaceSelector = &metav1.LabelSelector{}
+		}
+		metav1.AddLabelToSelector(m.NamespaceSelector, key, value)
+	}
+}
+
+// ValidatingAdmissionPolicyObjectSelector adds a label to the selector of the objects the policy applies to
+func ValidatingAdmissionPolicyObjectSelector(key, value string) ValidatingAdmissionPolicyOpt {
+	return func(p *ValidatingAdmissionPolicy) {
+		m := p.matchConstraints()
+		if m.ObjectSelector == nil {
+			m.ObjectSelector = &metav1.LabelSelector{}
+		}
+		metav1.AddLabelToSelector(m.ObjectSelector, key, value)
+	}
+}
+
+func (p *ValidatingAdmissionPolicy) matchConstraints() *admissionregistrationv1.MatchResources {
+	if p.Spec.MatchConstraints == nil {
+		p.Spec.MatchConstraints = &admissionregistrationv1.MatchResources{}
+	}
+	return p.Spec.MatchConstraints
+}
+
+// ValidatingAdmissionPolicyValidation adds a validation
+func ValidatingAdmissionPolicyValidation(v PolicyValidation) ValidatingAdmissionPolicyOpt {
+	validation := admissionregistrationv1.Validation{
+		Expression:        v.Expression,
+		Message:           v.Message,
+		MessageExpression: v.MessageExpression,
+	}
+
+	if v.Reason != "" {
+		validation.Reason = &v.Reason
+	}
+
+	return func(p *ValidatingAdmissionPolicy) {
+		p.Spec.Validations = append(p.Spec.Validations, validation)
+	}
+}
+
+// ValidatingAdmissionPolicyVariable adds a variable that later variables and the validations can use as variables.<name>
+func ValidatingAdmissionPolicyVariable(name, expression string) ValidatingAdmissionPolicyOpt {
+	return func(p *ValidatingAdmissionPolicy) {
+		p.Spec.Variables = append(p.Spec.Variables, admissionregistrationv1.Variable{
+			Name:       name,
+			Expression: expression,
+		})
+	}
+}
+
+// ValidatingAdmissionPolicyMatchCondition adds a CEL expression that must be true for the policy to be evaluated
+func ValidatingAdmissionPolicyMatchCondition(name, expression string) ValidatingAdmissionPolicyOpt {
+	return func(p *ValidatingAdmissionPolicy) {
+		p.Spec.MatchConditions = append(p.Spec.MatchConditions, admissionregistrationv1.MatchCondition{
+			Name:       name,
+			Expression: expression,
+		})
+	}
+}
+
+// ValidatingAdmissionPolicyAuditAnnotation adds an audit annotation with the value of the expression
+func ValidatingAdmissionPolicyAuditAnnotation(key, valueExpression string) ValidatingAdmissionPolicyOpt {
+	return func(p *ValidatingAdmissionPolicy) {
+		p.Spec.AuditAnnotations = append(p.Spec.AuditAnnotations, admissionregistrationv1.AuditAnnotation{
+			Key:             key,
+			ValueExpression: valueExpression,
+		})
+	}
+}
+
+// ValidatingAdmissionPolicyFailurePolicy sets whether requests are rejected or allowed when an expression fails to evaluate
+func ValidatingAdmissionPolicyFailurePolicy(f admissionregistrationv1.FailurePolicyType) ValidatingAdmissionPolicyOpt {
+	return func(p *ValidatingAdmissionPolicy) {
+		p.Spec.FailurePolicy = &f
+	}
+}
+
+// Apply applies the options to the policy
+func (p *ValidatingAdmissionPolicy) Apply(opts ...ValidatingAdmissionPolicyOpt) {
+	for _, opt := range opts {
+		opt(p)
+	}
+}
+
+// With returns a copy of the policy with the options applied
+func (p ValidatingAdmissionPolicy) With(opts ...ValidatingAdmissionPolicyOpt) ValidatingAdmissionPolicy {
+	clone := p.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the policy
+func (p ValidatingAdmissionPolicy) Clone() ValidatingAdmissionPolicy {
+	return ValidatingAdmissionPolicy{ValidatingAdmissionPolicy: *p.ValidatingAdmissionPolicy.DeepCopy()}
+}
+
+// Validate checks the metadata, the match constraints, that the policy has validations or audit annotations, that
+// every expression parses as CEL and the failure policy. Expressions are only parsed, so type errors and unknown
+// functions are reported by the API server
+func (p ValidatingAdmissionPolicy) Validate() error {
+	errs := validateObjectMeta(&p.ObjectMeta, false, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	if p.Spec.ParamKind != nil {
+		if p.Spec.ParamKind.APIVersion == "" {
+			errs = append(errs, field.Required(path.Child("paramKind", "apiVersion"), ""))
+		}
+		if p.Spec.ParamKind.Kind == "" {
+			errs = append(errs, field.Required(path.Child("paramKind", "kind"), ""))
+		}
+	}
+
+	if p.Spec.MatchConstraints == nil || len(p.Spec.MatchConstraints.ResourceRules) == 0 {
+		errs = append(errs, field.Required(path.Child("matchConstraints", "resourceRules"), "at least one resource rule is required"))
+	} else {
+		errs = append(errs, validateMatchResources(*p.Spec.MatchConstraints, path.Child("matchConstraints"))...)
+	}
+
+	if len(p.Spec.Validations) == 0 && len(p.Spec.AuditAnnotations) == 0 {
+		errs = append(errs, field.Required(path.Child("validations"), "validations or auditAnnotations must contain at least one item"))
+	}
+	for i, v := range p.Spec.Validations {
+		validationPath := path.Child("validations").Index(i)
+		errs = append(errs, validateCELExpression(v.Expression, validationPath.Child("expression"))...)
+		if v.MessageExpression != "" {
+			errs = append(errs, validateCELExpression(v.MessageExpression, validationPath.Child("messageExpression"))...)
+		}
+	}
+
+	variables := sets.New[string]()
+	for i, v := range p.Spec.Variables {
+		variablePath := path.Child("variables").Index(i)
+		if variables.Has(v.Name) {
+			errs = append(errs, field.Duplicate(variablePath.Child("name"), v.Name))
+		}
+		variables.Insert(v.Name)
+		errs = append(errs, validateCELIdentifier(v.Name, variablePath.Child("name"))...)
+		errs = append(errs, validateCELExpression(v.Expression, variablePath.Child("expression"))...)
+	}
+
+	for i, a := range p.Spec.AuditAnnotations {
+		annotationPath := path.Child("auditAnnotations").Index(i)
+		for _, msg := range validation.IsQualifiedName(fmt.Sprintf("%s/%s", p.Name, a.Key)) {
+			errs = append(errs, field.Invalid(annotationPath.Child("key"), a.Key, msg))
+		}
+		errs = append(errs, validateCELExpression(a.ValueExpression, annotationPath.Child("valueExpression"))...)
+	}
+
+	errs = append(errs, validateMatchConditions(p.Spec.MatchConditions, path.Child("matchConditions"))...)
+	errs = append(errs, validateEnum(ptrValue(p.Spec.FailurePolicy), path.Child("failurePolicy"),
+		admissionregistrationv1.Ignore, admissionregistrationv1.Fail)...)
+
+	return toAggregate(errs)
+}
+
+// validateMatchResources checks the selectors and the resource rules of a policy or binding
+func validateMatchResources(m admissionregistrationv1.MatchResources, path *field.Path) field.ErrorList {
+	opts := metav1validation.LabelSelectorValidationOptions{}
+	errs := metav1validation.ValidateLabelSelector(m.NamespaceSelector, opts, path.Child("namespaceSelector"))
+	errs = append(errs, metav1validation.ValidateLabelSelector(m.ObjectSelector, opts, path.Child("objectSelector"))...)
+	errs = append(errs, validateEnum(ptrValue(m.MatchPolicy), path.Child("matchPolicy"), admissionregistrationv1.Exact, admissionregistrationv1.Equivalent)...)
+
+	for i, r := range m.ResourceRules {
+		errs = append(errs, validateRuleWithOperations(r.RuleWithOperations, path.Child("resourceRules").Index(i))...)
+	}
+	for i, r := range m.ExcludeResourceRules {
+		errs = append(errs, validateRuleWithOperations(r.RuleWithOperations, path.Child("excludeResourceRules").Index(i))...)
+	}
+
+	return errs
+}
+
+// validateRuleWithOperations checks that the rule has operations, API groups, versions and resources
+func validateRuleWithOperations(r admissionregistrationv1.RuleWithOperations, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if len(r.Operations) == 0 {
+		errs = append(errs, field.Required(path.Child("operations"), ""))
+	}
+	for i, o := range r.Operations {
+		errs = append(errs, validateEnum(o, path.Child("operations").Index(i), admissionregistrationv1.OperationAll,
+			admissionregistrationv1.Create, admissionregistrationv1.Update, admissionregistrationv1.Delete, admissionregistrationv1.Connect)...)
+	}
+	if len(r.APIGroups) == 0 {
+		errs = append(errs, field.Required(path.Child("apiGroups"), ""))
+	}
+	if len(r.APIVersions) == 0 {
+		errs = append(errs, field.Required(path.Child("apiVersions"), ""))
+	}
+	if len(r.Resources) == 0 {
+		errs = append(errs, field.Required(path.Child("resources"), ""))
+	}
+	errs = append(errs, validateEnum(ptrValue(r.Scope), path.Child("scope"),
+		admissionregistrationv1.ClusterScope, admissionregistrationv1.NamespacedScope, admissionregistrationv1.AllScopes)...)
+
+	return errs
+}
+
+// validateMatchConditions checks that the match conditions have unique qualified names and expressions that parse
+func validateMatchConditions(conditions []admissionregistrationv1.MatchCondition, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	names := sets.New[string]()
+	for i, c := range conditions {
+		conditionPath := path.Index(i)
+		if names.Has(c.Name) {
+			errs = append(errs, field.Duplicate(conditionPath.Child("name"), c.Name))
+		}
+		names.Insert(c.Name)
+
+		if c.Name == "" {
+			errs = append(errs, field.Required(conditionPath.Child("name"), ""))
+		} else {
+			for _, msg := range validation.IsQualifiedName(c.Name) {
+				errs = append(errs, field.Invalid(conditionPath.Child("name"), c.Name, msg))
+			}
+		}
+		errs = append(errs, validateCELExpression(c.Expression, conditionPath.Child("expression"))...)
+	}
+
+	return errs
+}
+
+// validateCELExpression checks that the expression is set and parses as CEL
+func validateCELExpression(expression string, path *field.Path) field.ErrorList {
+	if expression == "" {
+		return field.ErrorList{field.Required(path, "")}
+	}
+
+	env, err := policyEnv()
+	if err != nil {
+		return field.ErrorList{field.InternalError(path, err)}
+	}
+
+	if _, iss := env.Parse(expression); iss.Err() != nil {
+		return field.ErrorList{field.Invalid(path, expression, iss.Err().Error())}
+	}
+
+	return nil
+}
+
+// validateCELIdentifier checks that the variable name can be used as variables.<name>
+func validateCELIdentifier(name string, path *field.Path) field.ErrorList {
+	if name == "" {
+		return field.ErrorList{field.Required(path, "")}
+	}
+
+	if !celIdentifier.MatchString(name) {
+		return field.ErrorList{field.Invalid(path, name, "must be a valid CEL identifier")}
+	}
+
+	return nil
+}
+
+var celIdentifier = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*$`)
@@ -0,0 +1,172 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidatingAdmissionPolicyBinding holds a Kubernetes validating admission policy binding
+type ValidatingAdmissionPolicyBinding struct {
+	admissionregistrationv1.ValidatingAdmissionPolicyBinding
+}
+
+type ValidatingAdmissionPolicyBindingOpt func(*ValidatingAdmissionPolicyBinding)
+
+// NewValidatingAdmissionPolicyBinding returns a validating admission policy binding with the given name and options.
+// Failed validations deny the request unless other actions are set with ValidatingAdmissionPolicyBindingActions
+func NewValidatingAdmissionPolicyBinding(name string, opts ...ValidatingAdmissionPolicyBindingOpt) ValidatingAdmissionPolicyBinding {
+	b := ValidatingAdmissionPolicyBinding{
+		ValidatingAdmissionPolicyBinding: admissionregistrationv1.ValidatingAdmissionPolicyBinding{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ValidatingAdmissionPolicyBinding",
+				APIVersion: "admissionregistration.k8s.io/v1",
+			},
+			ObjectMeta: newObjectMeta(name),
+			Spec: admissionregistrationv1.ValidatingAdmissionPolicyBindingSpec{
+				ValidationActions: []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny},
+			},
+		},
+	}
+
+	for _, v := range opts {
+		v(&b)
+	}
+
+	return b
+}
+
+// ValidatingAdmissionPolicyBindingPolicy sets the bound policy
+func ValidatingAdmissionPolicyBindingPolicy(p ValidatingAdmissionPolicy) ValidatingAdmissionPolicyBindingOpt {
+	name := p.Name
+	return func(b *ValidatingAdmissionPolicyBinding) {
+		b.Spec.PolicyName = name
+	}
+}
+
+// ValidatingAdmissionPolicyBindingParamRef sets the parameter resource passed to the policy as params.
+// An empty namespace uses the namespace of the request for namespaced parameter kinds. Requests are denied
+// when the parameter resource is not found, unless changed with ValidatingAdmissionPolicyBindingParamNotFoundAction
+func ValidatingAdmissionPolicyBindingParamRef(name, namespace string) ValidatingAdmissionPolicyBindingOpt {
+	return func(b *ValidatingAdmissionPolicyBinding) {
+		b.Spec.ParamRef = &admissionregistrationv1.ParamRef{
+			Name:                    name,
+			Namespace:               namespace,
+			ParameterNotFoundAction: ptrTo(admissionregistrationv1.DenyAction),
+		}
+	}
+}
+
+// ValidatingAdmissionPolicyBindingParamNotFoundAction sets whether requests are allowed or denied when the parameter
+// resource is not found. It must be used after ValidatingAdmissionPolicyBindingParamRef
+func ValidatingAdmissionPolicyBindingParamNotFoundAction(a admissionregistrationv1.ParameterNotFoundActionType) ValidatingAdmissionPolicyBindingOpt {
+	return func(b *ValidatingAdmissionPolicyBinding) {
+		if b.Spec.ParamRef == nil {
+			b.Spec.ParamRef = &admissionregistrationv1.ParamRef{}
+		}
+		b.Spec.ParamRef.ParameterNotFoundAction = &a
+	}
+}
+
+// ValidatingAdmissionPolicyBindingActions sets what happens when a validation fails
+func ValidatingAdmissionPolicyBindingActions(actions ...admissionregistrationv1.ValidationAction) ValidatingAdmissionPolicyBindingOpt {
+	return func(b *ValidatingAdmissionPolicyBinding) {
+		b.Spec.ValidationActions = actions
+	}
+}
+
+// ValidatingAdmissionPolicyBindingNamespaceSelector adds a label to the selector of the namespaces the binding applies to
+func ValidatingAdmissionPolicyBindingNamespaceSelector(key, value string) ValidatingAdmissionPolicyBindingOpt {
+	return func(b *ValidatingAdmissionPolicyBinding) {
+		if b.Spec.MatchResources == nil {
+			b.Spec.MatchResources = &admissionregistrationv1.MatchResources{}
+		}
+		if b.Spec.MatchResources.NamespaceSelector == nil {
+			b.Spec.MatchResources.NamespaceSelector = &metav1.LabelSelector{}
+		}
+		metav1.AddLabelToSelector(b.Spec.MatchResources.NamespaceSelector, key, value)
+	}
+}
+
+// Apply applies the options to the policy binding
+func (b *ValidatingAdmissionPolicyBinding) Apply(opts ...ValidatingAdmissionPolicyBindingOpt) {
+	for _, opt := range opts {
+		opt(b)
+	}
+}
+
+// With returns a copy of the policy binding with the options applied
+func (b ValidatingAdmissionPolicyBinding) With(opts ...ValidatingAdmissionPolicyBindingOpt) ValidatingAdmissionPolicyBinding {
+	clone := b.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the policy binding
+func (b ValidatingAdmissionPolicyBinding) Clone() ValidatingAdmissionPolicyBinding {
+	return ValidatingAdmissionPolicyBinding{ValidatingAdmissionPolicyBinding: *b.ValidatingAdmissionPolicyBinding.DeepCopy()}
+}
+
+// Validate checks the metadata, that the binding names a policy, the parameter reference and its not found action,
+// the match resources and that the validation actions are unique and do not combine Deny and Warn
+func (b ValidatingAdmissionPolicyBinding) Validate() error {
+	errs := validateObjectMeta(&b.ObjectMeta, false, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec")
+
+	errs = append(errs, validateDNSSubdomain(b.Spec.PolicyName, path.Child("policyName"))...)
+
+	if ref := b.Spec.ParamRef; ref != nil {
+		switch {
+		case ref.Name != "" && ref.Selector != nil:
+			errs = append(errs, field.Forbidden(path.Child("paramRef"), "only one of name or selector can be set"))
+		case ref.Name == "" && ref.Selector == nil:
+			errs = append(errs, field.Required(path.Child("paramRef"), "one of name or selector must be set"))
+		}
+
+		actionPath := path.Child("paramRef", "parameterNotFoundAction")
+		if ref.ParameterNotFoundAction == nil {
+			errs = append(errs, field.Required(actionPath, ""))
+		} else {
+			errs = append(errs, validateEnum(*ref.ParameterNotFoundAction, actionPath, admissionregistrationv1.AllowAction, admissionregistrationv1.DenyAction)...)
+		}
+	}
+
+	if b.Spec.MatchResources != nil {
+		errs = append(errs, validateMatchResources(*b.Spec.MatchResources, path.Child("matchResources"))...)
+	}
+
+	actions := sets.New[admissionregistrationv1.ValidationAction]()
+	if len(b.Spec.ValidationActions) == 0 {
+		errs = append(errs, field.Required(path.Child("validationActions"), "at least one validation action is required"))
+	}
+	for i, a := range b.Spec.ValidationActions {
+		actionPath := path.Child("validationActions").Index(i)
+		if actions.Has(a) {
+			errs = append(errs, field.Duplicate(actionPath, a))
+		}
+		actions.Insert(a)
+		errs = append(errs, validateEnum(a, actionPath, admissionregistrationv1.Deny, admissionregistrationv1.Warn, admissionregistrationv1.Audit)...)
+	}
+	if actions.Has(admissionregistrationv1.Deny) && actions.Has(admissionregistrationv1.Warn) {
+		errs = append(errs, field.Invalid(path.Child("validationActions"), b.Spec.ValidationActions, "Deny and Warn cannot be used together"))
+	}
+
+	return toAggregate(errs)
+}
@@ -0,0 +1,205 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// PolicyViolation holds a failed validation of a validating admission policy
+type PolicyViolation struct {
+	Expression string
+	Message    string
+	Reason     metav1.StatusReason
+}
+
+// Evaluate runs the CEL expressions of the policy in memory against the object as if it was being created, so policies
+// can be tested against kopts objects without a cluster. The object and params can be kopts objects or Kubernetes API
+// objects, and params is nil for policies without a param kind. Validations are skipped when a match condition is
+// false and an error is returned when an expression doesn't compile or evaluate. Match constraints are not checked,
+// and only the standard CEL extension libraries are available so expressions using the Kubernetes libraries such as
+// quantity, url or authorizer fail to compile
+func (p ValidatingAdmissionPolicy) Evaluate(object, params interface{}) ([]PolicyViolation, error) {
+	obj, err := jsonValue(object)
+	if err != nil {
+		return nil, fmt.Errorf("converting object: %w", err)
+	}
+
+	prms, err := jsonValue(params)
+	if err != nil {
+		return nil, fmt.Errorf("converting params: %w", err)
+	}
+
+	env, err := policyEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	variables := make(map[string]interface{})
+	activation := map[string]interface{}{
+		"object":          obj,
+		"oldObject":       nil,
+		"params":          prms,
+		"request":         policyRequest(obj),
+		"namespaceObject": nil,
+		"variables":       variables,
+	}
+
+	for _, v := range p.Spec.MatchConditions {
+		match, err := evalBool(env, v.Expression, activation)
+		if err != nil {
+			return nil, fmt.Errorf("match condition %s: %w", v.Name, err)
+		}
+		if !match {
+			return nil, nil
+		}
+	}
+
+	// variables are evaluated lazily by the API server, so an evaluation error is stored and only
+	// returned when an expression uses the variable
+	for _, v := range p.Spec.Variables {
+		val, err := evalCEL(env, v.Expression, activation)
+		if err != nil {
+			if _, ok := err.(evalError); !ok {
+				return nil, fmt.Errorf("variable %s: %w", v.Name, err)
+			}
+			val = types.NewErr("variables.%s: %v", v.Name, err)
+		}
+		variables[v.Name] = val
+	}
+
+	var violations []PolicyViolation
+	for _, v := range p.Spec.Validations {
+		valid, err := evalBool(env, v.Expression, activation)
+		if err != nil {
+			return nil, fmt.Errorf("validation %q: %w", v.Expression, err)
+		}
+		if valid {
+			continue
+		}
+
+		violation := PolicyViolation{
+			Expression: v.Expression,
+			Message:    v.Message,
+			Reason:     metav1.StatusReasonInvalid,
+		}
+
+		if v.Reason != nil {
+			violation.Reason = *v.Reason
+		}
+
+		if v.MessageExpression != "" {
+			if msg, err := evalCEL(env, v.MessageExpression, activation); err == nil {
+				if s, ok := msg.Value().(string); ok && s != "" {
+					violation.Message = s
+				}
+			}
+		}
+
+		if violation.Message == "" {
+			violation.Message = fmt.Sprintf("failed expression: %s", v.Expression)
+		}
+
+		violations = append(violations, violation)
+	}
+
+	return violations, nil
+}
+
+// evalError is returned when a compiled expression fails to evaluate
+type evalError struct {
+	err error
+}
+
+func (e evalError) Error() string {
+	return e.err.Error()
+}
+
+func policyEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.Variable("oldObject", cel.DynType),
+		cel.Variable("params", cel.DynType),
+		cel.Variable("request", cel.DynType),
+		cel.Variable("namespaceObject", cel.DynType),
+		cel.Variable("variables", cel.MapType(cel.StringType, cel.DynType)),
+		cel.OptionalTypes(),
+		ext.Strings(ext.StringsVersion(2)),
+		ext.Sets(),
+		ext.Lists(),
+		ext.Encoders(),
+	)
+}
+
+func evalCEL(env *cel.Env, expression string, activation map[string]interface{}) (ref.Val, error) {
+	ast, iss := env.Compile(expression)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	out, _, err := prg.Eval(activation)
+	if err != nil {
+		return nil, evalError{err: err}
+	}
+
+	return out, nil
+}
+
+func evalBool(env *cel.Env, expression string, activation map[string]interface{}) (bool, error) {
+	out, err := evalCEL(env, expression, activation)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression evaluated to %s instead of bool", out.Type().TypeName())
+	}
+
+	return b, nil
+}
+
+func policyRequest(obj interface{}) map[string]interface{} {
+	m, _ := obj.(map[string]interface{})
+	apiVersion, _ := m["apiVersion"].(string)
+	kind, _ := m["kind"].(string)
+	metadata, _ := m["metadata"].(map[string]interface{})
+	gv, _ := schema.ParseGroupVersion(apiVersion)
+
+	return map[string]interface{}{
+		"operation": "CREATE",
+		"kind": map[string]interface{}{
+			"group":   gv.Group,
+			"version": gv.Version,
+			"kind":    kind,
+		},
+		"name":      metadata["name"],
+		"namespace": metadata["namespace"],
+		"object":    obj,
+		"dryRun":    true,
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidatingAdmissionPolicyEvaluate(t *testing.T) {
+	forbidden := metav1.StatusReasonForbidden
+	d := NewDeployment("web",
+		DeploymentNamespace("prod"),
+		DeploymentReplicas(5),
+		DeploymentLabel("team", "platform"),
+	)
+	params := NewConfigMap("limits", ConfigMapData("maxReplicas", "3"))
+
+	tests := []struct {
+		name       string
+		opts       []ValidatingAdmissionPolicyOpt
+		params     interface{}
+		violations []PolicyViolation
+		err        bool
+	}{
+		{
+			name: "valid",
+			opts: []ValidatingAdmissionPolicyOpt{
+				ValidatingAdmissionPolicyValidation(PolicyValidation{Expression: "object.spec.replicas <= 5"}),
+			},
+		},
+		{
+			name: "message",
+			opts: []ValidatingAdmissionPolicyOpt{
+				ValidatingAdmissionPolicyValidation(PolicyValidation{Expression: "object.spec.replicas <= 3", Message: "too many replicas"}),
+			},
+			violations: []PolicyViolation{
+				{Expression: "object.spec.replicas <= 3", Message: "too many replicas", Reason: metav1.StatusReasonInvalid},
+			},
+		},
+		{
+			name: "default message and reason",
+			opts: []ValidatingAdmissionPolicyOpt{
+				ValidatingAdmissionPolicyValidation(PolicyValidation{Expression: "object.spec.replicas <= 3", Reason: forbidden}),
+			},
+			violations: []PolicyViolation{
+				{Expression: "object.spec.replicas <= 3", Message: "failed expression: object.spec.replicas <= 3", Reason: forbidden},
+			},
+		},
+		{
+			name: "message expression",
+			opts: []ValidatingAdmissionPolicyOpt{
+				ValidatingAdmissionPolicyValidation(PolicyValidation{
+					Expression:        "object.spec.replicas <= 3",
+					Message:           "too many replicas",
+					MessageExpression: "'replicas ' + string(object.spec.replicas) + ' exceed 3'",
+				}),
+			},
+			violations: []PolicyViolation{
+				{Expression: "object.spec.replicas <= 3", Message: "replicas 5 exceed 3", Reason: metav1.StatusReasonInvalid},
+			},
+		},
+		{
+			name: "failing message expression falls back to message",
+			opts: []ValidatingAdmissionPolicyOpt{
+				ValidatingAdmissionPolicyValidation(PolicyValidation{
+					Expression:        "object.spec.replicas <= 3",
+					Message:           "too many replicas",
+					MessageExpression: "object.metadata.missing",
+				}),
+			},
+			violations: []PolicyViolation{
+				{Expression: "object.spec.replicas <= 3", Message: "too many replicas", Reason: metav1.StatusReasonInvalid},
+			},
+		},
+		{
+			name: "match condition skips validations",
+			opts: []ValidatingAdmissionPolicyOpt{
+				ValidatingAdmissionPolicyMatchCondition("staging", "object.metadata.namespace == 'staging'"),
+				ValidatingAdmissionPolicyValidation(PolicyValidation{Expression: "false"}),
+			},
+		},
+		{
+			name: "variables",
+			opts: []ValidatingAdmissionPolicyOpt{
+				ValidatingAdmissionPolicyVariable("team", "object.metadata.labels.team"),
+				ValidatingAdmissionPolicyValidation(PolicyValidation{Expression: "variables.team == 'apps'", Message: "wrong team"}),
+			},
+			violations: []PolicyViolation{
+				{Expression: "variables.team == 'apps'", Message: "wrong team", Reason: metav1.StatusReasonInvalid},
+			},
+		},
+		{
+			name: "params",
+			opts: []ValidatingAdmissionPolicyOpt{
+				ValidatingAdmissionPolicyParamKind("v1", "ConfigMap"),
+				ValidatingAdmissionPolicyValidation(PolicyValidation{Expression: "object.spec.replicas <= int(params.data.maxReplicas)", Message: "over limit"}),
+			},
+			params: params,
+			violations: []PolicyViolation{
+				{Expression: "object.spec.replicas <= int(params.data.maxReplicas)", Message: "over limit", Reason: metav1.StatusReasonInvalid},
+			},
+		},
+		{
+			name: "compile error",
+			opts: []ValidatingAdmissionPolicyOpt{
+				ValidatingAdmissionPolicyValidation(PolicyValidation{Expression: "object.spec.replicas <="}),
+			},
+			err: true,
+		},
+		{
+			name: "non boolean validation",
+			opts: []ValidatingAdmissionPolicyOpt{
+				ValidatingAdmissionPolicyValidation(PolicyValidation{Expression: "object.spec.replicas"}),
+			},
+			err: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewValidatingAdmissionPolicy("policy", tt.opts...)
+			violations, err := p.Evaluate(d, tt.params)
+			if (err != nil) != tt.err {
+				t.Fatalf("Evaluate() error = %v, want error %t", err, tt.err)
+			}
+			if !reflect.DeepEqual(violations, tt.violations) {
+				t.Errorf("Evaluate() = %+v, want %+v", violations, tt.violations)
+			}
+		})
+	}
+}
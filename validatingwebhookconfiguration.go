@@ -0,0 +1,99 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidatingWebhookConfiguration holds a Kubernetes validating admission webhook configuration
+type ValidatingWebhookConfiguration struct {
+	admissionregistrationv1.ValidatingWebhookConfiguration
+}
+
+type ValidatingWebhookConfigurationOpt func(*ValidatingWebhookConfiguration)
+
+// NewValidatingWebhookConfiguration returns a validating webhook configuration with the given name and options
+func NewValidatingWebhookConfiguration(name string, opts ...ValidatingWebhookConfigurationOpt) ValidatingWebhookConfiguration {
+	wc := ValidatingWebhookConfiguration{
+		ValidatingWebhookConfiguration: admissionregistrationv1.ValidatingWebhookConfiguration{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ValidatingWebhookConfiguration",
+				APIVersion: "admissionregistration.k8s.io/v1",
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&wc)
+	}
+
+	return wc
+}
+
+// ValidatingWebhookConfigurationWebhook adds a webhook
+func ValidatingWebhookConfigurationWebhook(w Webhook) ValidatingWebhookConfigurationOpt {
+	webhook := *w.ValidatingWebhook.DeepCopy()
+	return func(wc *ValidatingWebhookConfiguration) {
+		wc.Webhooks = append(wc.Webhooks, *webhook.DeepCopy())
+	}
+}
+
+// ValidatingWebhookConfigurationInjectCAFrom lets the cert-manager CA injector fill the CA bundle of every webhook
+// from the certificate of the webhook server
+func ValidatingWebhookConfigurationInjectCAFrom(c Certificate) ValidatingWebhookConfigurationOpt {
+	value := injectCAFromAnnotation(c)
+	return func(wc *ValidatingWebhookConfiguration) {
+		addAnnotation(injectCAFromKey, value, &wc.ObjectMeta)
+	}
+}
+
+// Apply applies the options to the webhook configuration
+func (wc *ValidatingWebhookConfiguration) Apply(opts ...ValidatingWebhookConfigurationOpt) {
+	for _, opt := range opts {
+		opt(wc)
+	}
+}
+
+// With returns a copy of the webhook configuration with the options applied
+func (wc ValidatingWebhookConfiguration) With(opts ...ValidatingWebhookConfigurationOpt) ValidatingWebhookConfiguration {
+	clone := wc.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the webhook configuration
+func (wc ValidatingWebhookConfiguration) Clone() ValidatingWebhookConfiguration {
+	return ValidatingWebhookConfiguration{ValidatingWebhookConfiguration: *wc.ValidatingWebhookConfiguration.DeepCopy()}
+}
+
+// Validate checks the metadata and that the webhooks are valid and uniquely named
+func (wc ValidatingWebhookConfiguration) Validate() error {
+	errs := validateObjectMeta(&wc.ObjectMeta, false, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("webhooks")
+
+	names := make([]string, len(wc.Webhooks))
+	for i, w := range wc.Webhooks {
+		names[i] = w.Name
+		errs = append(errs, validateWebhook(w, path.Index(i))...)
+	}
+
+	return toAggregate(append(errs, validateWebhookNames(names, path)...))
+}
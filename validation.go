@@ -0,0 +1,492 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Validator is implemented by every kopts type
+type Validator interface {
+	Validate() error
+}
+
+// validationError keeps the field path of a validation error while matching its cause with errors.Is
+type validationError struct {
+	err   *field.Error
+	cause error
+}
+
+func (e validationError) Error() string {
+	return e.err.Error()
+}
+
+func (e validationError) Unwrap() error {
+	return e.cause
+}
+
+// toAggregate returns the errors as an aggregate. Missing names match ErrNameRequired with errors.Is
+func toAggregate(errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	agg := make([]error, 0, len(errs))
+	for _, e := range errs {
+		if e.Type == field.ErrorTypeRequired && e.Detail == ErrNameRequired.Error() {
+			agg = append(agg, validationError{err: e, cause: ErrNameRequired})
+			continue
+		}
+		agg = append(agg, e)
+	}
+
+	return utilerrors.NewAggregate(agg)
+}
+
+// validateObjectMeta checks the name or generateName, the namespace, the label and annotation syntax and that owner
+// references are complete, including the UID. Cluster scoped objects must not set a namespace
+func validateObjectMeta(m metav1.Object, namespaced bool, nameFn apimachineryvalidation.ValidateNameFunc) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("metadata")
+
+	switch {
+	case m.GetName() != "":
+		for _, msg := range nameFn(m.GetName(), false) {
+			errs = append(errs, field.Invalid(path.Child("name"), m.GetName(), msg))
+		}
+	case m.GetGenerateName() != "":
+		for _, msg := range nameFn(m.GetGenerateName(), true) {
+			errs = append(errs, field.Invalid(path.Child("generateName"), m.GetGenerateName(), msg))
+		}
+	default:
+		errs = append(errs, field.Required(path.Child("name"), ErrNameRequired.Error()))
+	}
+
+	if ns := m.GetNamespace(); ns != "" {
+		if !namespaced {
+			errs = append(errs, field.Forbidden(path.Child("namespace"), "not allowed on cluster scoped objects"))
+		}
+		for _, msg := range apimachineryvalidation.ValidateNamespaceName(ns, false) {
+			errs = append(errs, field.Invalid(path.Child("namespace"), ns, msg))
+		}
+	}
+
+	errs = append(errs, metav1validation.ValidateLabels(m.GetLabels(), path.Child("labels"))...)
+	errs = append(errs, apimachineryvalidation.ValidateAnnotations(m.GetAnnotations(), path.Child("annotations"))...)
+	errs = append(errs, apimachineryvalidation.ValidateFinalizers(m.GetFinalizers(), path.Child("finalizers"))...)
+	errs = append(errs, apimachineryvalidation.ValidateOwnerReferences(m.GetOwnerReferences(), path.Child("ownerReferences"))...)
+
+	return errs
+}
+
+// validateEnum checks that a set value is one of the allowed values
+func validateEnum[T ~string](v T, path *field.Path, allowed ...T) field.ErrorList {
+	if v == "" {
+		return nil
+	}
+
+	for _, a := range allowed {
+		if v == a {
+			return nil
+		}
+	}
+
+	values := make([]string, len(allowed))
+	for i, a := range allowed {
+		values[i] = string(a)
+	}
+
+	return field.ErrorList{field.NotSupported(path, v, values)}
+}
+
+// validatePort checks that the port is between 1 and 65535
+func validatePort(port int32, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for _, msg := range validation.IsValidPortNum(int(port)) {
+		errs = append(errs, field.Invalid(path, port, msg))
+	}
+
+	return errs
+}
+
+// validatePortName checks that a set port name is an IANA service name
+func validatePortName(name string, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if name == "" {
+		return nil
+	}
+
+	for _, msg := range validation.IsValidPortName(name) {
+		errs = append(errs, field.Invalid(path, name, msg))
+	}
+
+	return errs
+}
+
+// validateIntOrStringPort checks a port given by number or by name
+func validateIntOrStringPort(port intstr.IntOrString, path *field.Path) field.ErrorList {
+	if port.Type == intstr.String {
+		if port.StrVal == "" {
+			return field.ErrorList{field.Required(path, "")}
+		}
+		return validatePortName(port.StrVal, path)
+	}
+
+	return validatePort(port.IntVal, path)
+}
+
+// validateNonNegative checks that an optional count is not negative
+func validateNonNegative(v *int32, path *field.Path) field.ErrorList {
+	if v == nil {
+		return nil
+	}
+
+	return apimachineryvalidation.ValidateNonnegativeField(int64(*v), path)
+}
+
+// validateDNSLabel checks a required DNS-1123 label such as a container or port name
+func validateDNSLabel(v string, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if v == "" {
+		return field.ErrorList{field.Required(path, "")}
+	}
+
+	for _, msg := range validation.IsDNS1123Label(v) {
+		errs = append(errs, field.Invalid(path, v, msg))
+	}
+
+	return errs
+}
+
+// validateDNSSubdomain checks a required DNS-1123 subdomain such as the name of a referenced object
+func validateDNSSubdomain(v string, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if v == "" {
+		return field.ErrorList{field.Required(path, "")}
+	}
+
+	for _, msg := range validation.IsDNS1123Subdomain(v) {
+		errs = append(errs, field.Invalid(path, v, msg))
+	}
+
+	return errs
+}
+
+// validateSelector checks that the selector is set, is well formed and matches the pod template labels
+func validateSelector(s *metav1.LabelSelector, template map[string]string, path *field.Path) field.ErrorList {
+	errs := validateRequiredSelector(s, path)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(s)
+	if err != nil {
+		return append(errs, field.Invalid(path, s, err.Error()))
+	}
+
+	if !selector.Matches(labels.Set(template)) {
+		errs = append(errs, field.Invalid(path, s, "selector does not match the pod template labels"))
+	}
+
+	return errs
+}
+
+// validateRequiredSelector checks that the selector has labels or expressions, as an empty one selects every pod
+func validateRequiredSelector(s *metav1.LabelSelector, path *field.Path) field.ErrorList {
+	if emptySelector(s) {
+		return field.ErrorList{field.Required(path, "")}
+	}
+
+	return metav1validation.ValidateLabelSelector(s, metav1validation.LabelSelectorValidationOptions{}, path)
+}
+
+// emptySelector reports whether the selector is nil or has neither labels nor expressions
+func emptySelector(s *metav1.LabelSelector) bool {
+	return s == nil || (len(s.MatchLabels) == 0 && len(s.MatchExpressions) == 0)
+}
+
+// validatePodTemplate checks the pod template labels and spec. The restart policy must be one of the given
+// policies when any are given
+func validatePodTemplate(t corev1.PodTemplateSpec, path *field.Path, restartPolicies ...corev1.RestartPolicy) field.ErrorList {
+	errs := metav1validation.ValidateLabels(t.Labels, path.Child("metadata", "labels"))
+	errs = append(errs, apimachineryvalidation.ValidateAnnotations(t.Annotations, path.Child("metadata", "annotations"))...)
+
+	return append(errs, validatePodSpec(t.Spec, path.Child("spec"), restartPolicies...)...)
+}
+
+// validatePodSpec checks that the pod has containers with unique names and valid enum values
+func validatePodSpec(s corev1.PodSpec, path *field.Path, restartPolicies ...corev1.RestartPolicy) field.ErrorList {
+	var errs field.ErrorList
+
+	if len(s.Containers) == 0 {
+		errs = append(errs, field.Required(path.Child("containers"), "at least one container is required"))
+	}
+
+	names := sets.New[string]()
+	for i, c := range s.InitContainers {
+		errs = append(errs, validateContainerName(c.Name, names, path.Child("initContainers").Index(i).Child("name"))...)
+		errs = append(errs, validateContainer(c, path.Child("initContainers").Index(i))...)
+	}
+	for i, c := range s.Containers {
+		errs = append(errs, validateContainerName(c.Name, names, path.Child("containers").Index(i).Child("name"))...)
+		errs = append(errs, validateContainer(c, path.Child("containers").Index(i))...)
+	}
+
+	volumes := sets.New[string]()
+	for i, v := range s.Volumes {
+		volumePath := path.Child("volumes").Index(i).Child("name")
+		errs = append(errs, validateDNSLabel(v.Name, volumePath)...)
+		if volumes.Has(v.Name) {
+			errs = append(errs, field.Duplicate(volumePath, v.Name))
+		}
+		volumes.Insert(v.Name)
+	}
+
+	if len(restartPolicies) == 0 {
+		restartPolicies = []corev1.RestartPolicy{corev1.RestartPolicyAlways, corev1.RestartPolicyOnFailure, corev1.RestartPolicyNever}
+	}
+	errs = append(errs, validateEnum(s.RestartPolicy, path.Child("restartPolicy"), restartPolicies...)...)
+	errs = append(errs, validateEnum(s.DNSPolicy, path.Child("dnsPolicy"),
+		corev1.DNSClusterFirst, corev1.DNSClusterFirstWithHostNet, corev1.DNSDefault, corev1.DNSNone)...)
+
+	for i, t := range s.Tolerations {
+		errs = append(errs, validateToleration(t, path.Child("tolerations").Index(i))...)
+	}
+
+	return errs
+}
+
+func validateContainerName(name string, names sets.Set[string], path *field.Path) field.ErrorList {
+	if name != "" && names.Has(name) {
+		return field.ErrorList{field.Duplicate(path, name)}
+	}
+	names.Insert(name)
+
+	return nil
+}
+
+// validateContainer checks the container name, image, ports, pull policy and probes
+func validateContainer(c corev1.Container, path *field.Path) field.ErrorList {
+	errs := validateDNSLabel(c.Name, path.Child("name"))
+
+	if c.Image == "" {
+		errs = append(errs, field.Required(path.Child("image"), ""))
+	}
+
+	errs = append(errs, validateEnum(c.ImagePullPolicy, path.Child("imagePullPolicy"),
+		corev1.PullAlways, corev1.PullNever, corev1.PullIfNotPresent)...)
+
+	ports := sets.New[string]()
+	for i, p := range c.Ports {
+		portPath := path.Child("ports").Index(i)
+		errs = append(errs, validatePortName(p.Name, portPath.Child("name"))...)
+		if p.Name != "" && ports.Has(p.Name) {
+			errs = append(errs, field.Duplicate(portPath.Child("name"), p.Name))
+		}
+		ports.Insert(p.Name)
+
+		errs = append(errs, validatePort(p.ContainerPort, portPath.Child("containerPort"))...)
+		if p.HostPort != 0 {
+			errs = append(errs, validatePort(p.HostPort, portPath.Child("hostPort"))...)
+		}
+		errs = append(errs, validateEnum(p.Protocol, portPath.Child("protocol"),
+			corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP)...)
+	}
+
+	for i, e := range c.Env {
+		if e.Name == "" {
+			errs = append(errs, field.Required(path.Child("env").Index(i).Child("name"), ""))
+		}
+	}
+
+	errs = append(errs, validateProbe(c.LivenessProbe, path.Child("livenessProbe"))...)
+	errs = append(errs, validateProbe(c.ReadinessProbe, path.Child("readinessProbe"))...)
+	errs = append(errs, validateProbe(c.StartupProbe, path.Child("startupProbe"))...)
+
+	for r, req := range c.Resources.Requests {
+		if limit, ok := c.Resources.Limits[r]; ok && req.Cmp(limit) > 0 {
+			errs = append(errs, field.Invalid(path.Child("resources", "requests").Key(string(r)), req.String(),
+				fmt.Sprintf("must be less than or equal to the %s limit of %s", r, limit.String())))
+		}
+	}
+
+	return errs
+}
+
+// validateProbe checks that a set probe has exactly one handler with a valid port
+func validateProbe(p *corev1.Probe, path *field.Path) field.ErrorList {
+	if p == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+	handlers := 0
+	if p.Exec != nil {
+		handlers++
+	}
+	if p.HTTPGet != nil {
+		handlers++
+		errs = append(errs, validateIntOrStringPort(p.HTTPGet.Port, path.Child("httpGet", "port"))...)
+		errs = append(errs, validateEnum(p.HTTPGet.Scheme, path.Child("httpGet", "scheme"), corev1.URISchemeHTTP, corev1.URISchemeHTTPS)...)
+	}
+	if p.TCPSocket != nil {
+		handlers++
+		errs = append(errs, validateIntOrStringPort(p.TCPSocket.Port, path.Child("tcpSocket", "port"))...)
+	}
+	if p.GRPC != nil {
+		handlers++
+		errs = append(errs, validatePort(p.GRPC.Port, path.Child("grpc", "port"))...)
+	}
+
+	switch {
+	case handlers == 0:
+		errs = append(errs, field.Required(path, "one of exec, httpGet, tcpSocket or grpc must be set"))
+	case handlers > 1:
+		errs = append(errs, field.Forbidden(path, "only one of exec, httpGet, tcpSocket or grpc can be set"))
+	}
+
+	return errs
+}
+
+// validateToleration checks the toleration operator and effect, and that only NoExecute tolerations set seconds
+func validateToleration(t corev1.Toleration, path *field.Path) field.ErrorList {
+	errs := validateEnum(t.Operator, path.Child("operator"), corev1.TolerationOpExists, corev1.TolerationOpEqual)
+	errs = append(errs, validateEnum(t.Effect, path.Child("effect"),
+		corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute)...)
+
+	if t.Operator == corev1.TolerationOpExists && t.Value != "" {
+		errs = append(errs, field.Invalid(path.Child("value"), t.Value, "must be empty when operator is Exists"))
+	}
+	if t.Key == "" && t.Operator != corev1.TolerationOpExists {
+		errs = append(errs, field.Invalid(path.Child("operator"), t.Operator, "must be Exists when key is empty"))
+	}
+	if t.Key != "" {
+		errs = append(errs, metav1validation.ValidateLabelName(t.Key, path.Child("key"))...)
+	}
+	if t.TolerationSeconds != nil && t.Effect != corev1.TaintEffectNoExecute {
+		errs = append(errs, field.Invalid(path.Child("effect"), t.Effect, "must be NoExecute when tolerationSeconds is set"))
+	}
+
+	return errs
+}
+
+// validateDataKeys checks that the keys are valid config map and secret keys
+func validateDataKeys[T any](data map[string]T, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for k := range data {
+		for _, msg := range validation.IsConfigMapKey(k) {
+			errs = append(errs, field.Invalid(path.Key(k), k, msg))
+		}
+	}
+
+	return errs
+}
+
+// validateAccessModes checks that at least one access mode is set and that every mode is supported
+func validateAccessModes(modes []corev1.PersistentVolumeAccessMode, path *field.Path) field.ErrorList {
+	if len(modes) == 0 {
+		return field.ErrorList{field.Required(path, "at least one access mode is required")}
+	}
+
+	var errs field.ErrorList
+	for i, m := range modes {
+		errs = append(errs, validateEnum(m, path.Index(i), corev1.ReadWriteOnce, corev1.ReadOnlyMany,
+			corev1.ReadWriteMany, corev1.ReadWriteOncePod)...)
+	}
+
+	return errs
+}
+
+// validateResourceList checks that the quantities are not negative
+func validateResourceList(l corev1.ResourceList, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for r, q := range l {
+		if q.Sign() < 0 {
+			errs = append(errs, field.Invalid(path.Key(string(r)), q.String(), "must be greater than or equal to 0"))
+		}
+	}
+
+	return errs
+}
+
+// validateIntOrPercent checks that a set value is a non-negative number or a percentage between 0% and 100%
+func validateIntOrPercent(v *intstr.IntOrString, path *field.Path) field.ErrorList {
+	if v == nil {
+		return nil
+	}
+
+	if v.Type == intstr.Int {
+		return apimachineryvalidation.ValidateNonnegativeField(int64(v.IntVal), path)
+	}
+
+	p, ok := strings.CutSuffix(v.StrVal, "%")
+	n, err := strconv.Atoi(p)
+	if !ok || err != nil || n < 0 || n > 100 {
+		return field.ErrorList{field.Invalid(path, v.StrVal, "must be a number or a percentage between 0% and 100%")}
+	}
+
+	return nil
+}
+
+// validateHostname checks that the host is a DNS-1123 subdomain with an optional leading wildcard label
+func validateHostname(host string, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	msgs := validation.IsDNS1123Subdomain(host)
+	if strings.HasPrefix(host, "*.") {
+		msgs = validation.IsWildcardDNS1123Subdomain(host)
+	}
+	for _, msg := range msgs {
+		errs = append(errs, field.Invalid(path, host, msg))
+	}
+
+	return errs
+}
+
+// validateOneOf checks that exactly one of the fields is set
+func validateOneOf(path *field.Path, set map[string]bool) field.ErrorList {
+	fields := make([]string, 0, len(set))
+	count := 0
+	for f, ok := range set {
+		fields = append(fields, f)
+		if ok {
+			count++
+		}
+	}
+	slices.Sort(fields)
+
+	switch {
+	case count == 0:
+		return field.ErrorList{field.Required(path, fmt.Sprintf("one of %s must be set", strings.Join(fields, ", ")))}
+	case count > 1:
+		return field.ErrorList{field.Forbidden(path, fmt.Sprintf("only one of %s can be set", strings.Join(fields, ", ")))}
+	}
+
+	return nil
+}
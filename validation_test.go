@@ -0,0 +1,190 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// validationFields returns the sorted field paths of the validation errors
+func validationFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+
+	var agg utilerrors.Aggregate
+	if !errors.As(err, &agg) {
+		t.Fatalf("error %v is not an aggregate", err)
+	}
+
+	var fields []string
+	for _, e := range agg.Errors() {
+		switch e := e.(type) {
+		case *field.Error:
+			fields = append(fields, e.Field)
+		case validationError:
+			fields = append(fields, e.err.Field)
+		default:
+			t.Fatalf("error %v is not a field error", e)
+		}
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+func TestValidate(t *testing.T) {
+	web := NewContainer("web", ContainerImage("nginx"))
+	pod := NewPodSpec("web", PodLabel("app", "web"), PodContainer(web))
+
+	tests := []struct {
+		name   string
+		object Validator
+		fields []string
+	}{
+		{
+			name:   "valid deployment",
+			object: NewDeployment("web", DeploymentSelector("app", "web"), DeploymentPodSpec(pod)),
+		},
+		{
+			name:   "invalid metadata",
+			object: NewDeployment("Web", DeploymentNamespace("Apps"), DeploymentLabel("-bad", "x"), DeploymentSelector("app", "web"), DeploymentPodSpec(pod)),
+			fields: []string{"metadata.labels", "metadata.name", "metadata.namespace"},
+		},
+		{
+			name:   "selector does not match the template",
+			object: NewDeployment("web", DeploymentSelector("app", "api"), DeploymentPodSpec(pod)),
+			fields: []string{"spec.selector"},
+		},
+		{
+			name: "invalid container",
+			object: NewDeployment("web", DeploymentSelector("app", "web"), DeploymentPodSpec(NewPodSpec("web",
+				PodLabel("app", "web"),
+				PodContainer(NewContainer("Web_1", ContainerPort("http", 70000))),
+			))),
+			fields: []string{
+				"spec.template.spec.containers[0].image",
+				"spec.template.spec.containers[0].name",
+				"spec.template.spec.containers[0].ports[0].containerPort",
+			},
+		},
+		{
+			name:   "container",
+			object: NewContainer("", ContainerImage("nginx"), ContainerImagePullPolicy("Sometimes")),
+			fields: []string{"imagePullPolicy", "name"},
+		},
+		{
+			name:   "cron job",
+			object: NewCronJob("backup", CronJobSchedule("* * *"), CronJobPodSpec(pod)),
+			fields: []string{"spec.jobTemplate.spec.template.spec.restartPolicy", "spec.schedule"},
+		},
+		{
+			name:   "valid cron job",
+			object: NewCronJob("backup", CronJobSchedule(Every10Minutes), CronJobPodSpec(pod), CronJobRestartPolicy(corev1.RestartPolicyOnFailure)),
+		},
+		{
+			name:   "unnamed service ports",
+			object: NewService("web", ServicePort(80, 8080), ServicePort(443, 8443)),
+			fields: []string{"spec.ports[0].name", "spec.ports[1].name"},
+		},
+		{
+			name:   "toleration seconds without NoExecute",
+			object: Toleration{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "db", Effect: corev1.TaintEffectNoSchedule, TolerationSeconds: ptrTo(30)},
+			fields: []string{"effect"},
+		},
+		{
+			name:   "namespace on a cluster scoped object",
+			object: NewClusterRole("reader", Meta[*ClusterRole](MetaNamespace("apps"))),
+			fields: []string{"metadata.namespace"},
+		},
+		{
+			name: "owner reference without a UID",
+			object: NewSecret("token", Meta[*Secret](MetaOwnerReference(metav1.OwnerReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "web",
+			}))),
+			fields: []string{"metadata.ownerReferences.uid"},
+		},
+		{
+			name: "binding without a parameter not found action",
+			object: NewValidatingAdmissionPolicyBinding("limits",
+				ValidatingAdmissionPolicyBindingPolicy(NewValidatingAdmissionPolicy("limits")),
+				ValidatingAdmissionPolicyBindingActions("Deny"),
+				func(b *ValidatingAdmissionPolicyBinding) {
+					ValidatingAdmissionPolicyBindingParamRef("limits", "")(b)
+					b.Spec.ParamRef.ParameterNotFoundAction = nil
+				},
+			),
+			fields: []string{"spec.paramRef.parameterNotFoundAction"},
+		},
+		{
+			name:   "object without kind",
+			object: NewObject("example.com/v1", "", "widget"),
+			fields: []string{"kind"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, tt.object.Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestValidateNameRequired(t *testing.T) {
+	err := NewDeployment("").Validate()
+	if !errors.Is(err, ErrNameRequired) {
+		t.Errorf("Validate() = %v, want ErrNameRequired", err)
+	}
+
+	err = NewDeployment("", Meta[*Deployment](MetaGenerateName("web-"))).Validate()
+	if errors.Is(err, ErrNameRequired) {
+		t.Errorf("Validate() = %v, want no ErrNameRequired with a generated name", err)
+	}
+}
+
+func TestMarshalYamlStrict(t *testing.T) {
+	c := NewContainer("web")
+	if _, err := MarshalYaml(c); err != nil {
+		t.Fatalf("MarshalYaml() error = %v", err)
+	}
+
+	if _, err := MarshalYamlStrict(c); err == nil || !strings.Contains(err.Error(), "image") {
+		t.Errorf("MarshalYamlStrict() error = %v, want a missing image", err)
+	}
+
+	c.Apply(ContainerImage("nginx"))
+	if _, err := MarshalYamlStrict(c); err != nil {
+		t.Errorf("MarshalYamlStrict() error = %v", err)
+	}
+
+	if _, err := MarshalYamlStrict(map[string]string{"a": "b"}); err != nil {
+		t.Errorf("MarshalYamlStrict() error = %v for a value without Validate", err)
+	}
+}
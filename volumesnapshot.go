@@ -0,0 +1,213 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	snapshotv1 "github.com/CoverWhale/kopts/apis/snapshot/v1"
+)
+
+// VolumeSnapshotClass holds a CSI volume snapshot class
+type VolumeSnapshotClass struct {
+	snapshotv1.VolumeSnapshotClass
+}
+
+type VolumeSnapshotClassOpt func(*VolumeSnapshotClass)
+
+// NewVolumeSnapshotClass returns a volume snapshot class with the given name and options.
+// The deletion policy defaults to Delete
+func NewVolumeSnapshotClass(name string, opts ...VolumeSnapshotClassOpt) VolumeSnapshotClass {
+	vsc := VolumeSnapshotClass{
+		VolumeSnapshotClass: snapshotv1.VolumeSnapshotClass{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "VolumeSnapshotClass",
+				APIVersion: snapshotv1.APIVersion,
+			},
+			ObjectMeta:     newObjectMeta(name),
+			DeletionPolicy: snapshotv1.VolumeSnapshotContentDelete,
+		},
+	}
+
+	for _, v := range opts {
+		v(&vsc)
+	}
+
+	return vsc
+}
+
+// VolumeSnapshotClassDriver sets the CSI driver taking the snapshots
+func VolumeSnapshotClassDriver(d string) VolumeSnapshotClassOpt {
+	return func(vsc *VolumeSnapshotClass) {
+		vsc.Driver = d
+	}
+}
+
+// VolumeSnapshotClassParameter sets a single driver parameter
+func VolumeSnapshotClassParameter(key, value string) VolumeSnapshotClassOpt {
+	return func(vsc *VolumeSnapshotClass) {
+		if vsc.Parameters == nil {
+			vsc.Parameters = make(map[string]string)
+		}
+		vsc.Parameters[key] = value
+	}
+}
+
+// VolumeSnapshotClassDeletionPolicy sets whether the snapshot content is deleted with the snapshot
+func VolumeSnapshotClassDeletionPolicy(p snapshotv1.DeletionPolicy) VolumeSnapshotClassOpt {
+	return func(vsc *VolumeSnapshotClass) {
+		vsc.DeletionPolicy = p
+	}
+}
+
+// VolumeSnapshotClassDefault marks the volume snapshot class as the default for its driver
+func VolumeSnapshotClassDefault() VolumeSnapshotClassOpt {
+	return func(vsc *VolumeSnapshotClass) {
+		addAnnotation("snapshot.storage.kubernetes.io/is-default-class", "true", &vsc.ObjectMeta)
+	}
+}
+
+// Apply applies the options to the volume snapshot class
+func (vsc *VolumeSnapshotClass) Apply(opts ...VolumeSnapshotClassOpt) {
+	for _, opt := range opts {
+		opt(vsc)
+	}
+}
+
+// With returns a copy of the volume snapshot class with the options applied
+func (vsc VolumeSnapshotClass) With(opts ...VolumeSnapshotClassOpt) VolumeSnapshotClass {
+	clone := vsc.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the volume snapshot class
+func (vsc VolumeSnapshotClass) Clone() VolumeSnapshotClass {
+	return VolumeSnapshotClass{VolumeSnapshotClass: deepCopyJSON(vsc.VolumeSnapshotClass)}
+}
+
+// Validate checks the metadata, that the driver is set and the deletion policy
+func (vsc VolumeSnapshotClass) Validate() error {
+	errs := validateObjectMeta(&vsc.ObjectMeta, false, apimachineryvalidation.NameIsDNSSubdomain)
+
+	if vsc.Driver == "" {
+		errs = append(errs, field.Required(field.NewPath("driver"), ""))
+	}
+	if vsc.DeletionPolicy == "" {
+		errs = append(errs, field.Required(field.NewPath("deletionPolicy"), ""))
+	}
+	errs = append(errs, validateEnum(vsc.DeletionPolicy, field.NewPath("deletionPolicy"),
+		snapshotv1.VolumeSnapshotContentDelete, snapshotv1.VolumeSnapshotContentRetain)...)
+
+	return toAggregate(errs)
+}
+
+// VolumeSnapshot holds a CSI volume snapshot
+type VolumeSnapshot struct {
+	snapshotv1.VolumeSnapshot
+}
+
+type VolumeSnapshotOpt func(*VolumeSnapshot)
+
+// NewVolumeSnapshot returns a volume snapshot with the given name and options
+func NewVolumeSnapshot(name string, opts ...VolumeSnapshotOpt) VolumeSnapshot {
+	vs := VolumeSnapshot{
+		VolumeSnapshot: snapshotv1.VolumeSnapshot{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "VolumeSnapshot",
+				APIVersion: snapshotv1.APIVersion,
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, v := range opts {
+		v(&vs)
+	}
+
+	return vs
+}
+
+// VolumeSnapshotNamespace sets the namespace for the volume snapshot
+func VolumeSnapshotNamespace(n string) VolumeSnapshotOpt {
+	return func(vs *VolumeSnapshot) {
+		setNamespace(n, &vs.ObjectMeta)
+	}
+}
+
+// VolumeSnapshotClaim sets the claim to snapshot. The snapshot is placed in the claim namespace
+func VolumeSnapshotClaim(pvc PersistentVolumeClaim) VolumeSnapshotOpt {
+	name := pvc.Name
+	namespace := pvc.Namespace
+	return func(vs *VolumeSnapshot) {
+		setNamespace(namespace, &vs.ObjectMeta)
+		vs.Spec.Source = snapshotv1.VolumeSnapshotSource{
+			PersistentVolumeClaimName: ptrTo(name),
+		}
+	}
+}
+
+// VolumeSnapshotContentName sets a pre-provisioned snapshot content as the source
+func VolumeSnapshotContentName(content string) VolumeSnapshotOpt {
+	return func(vs *VolumeSnapshot) {
+		vs.Spec.Source = snapshotv1.VolumeSnapshotSource{
+			VolumeSnapshotContentName: ptrTo(content),
+		}
+	}
+}
+
+// VolumeSnapshotClassRef sets the volume snapshot class used to take the snapshot
+func VolumeSnapshotClassRef(vsc VolumeSnapshotClass) VolumeSnapshotOpt {
+	name := vsc.Name
+	return func(vs *VolumeSnapshot) {
+		vs.Spec.VolumeSnapshotClassName = ptrTo(name)
+	}
+}
+
+// Apply applies the options to the volume snapshot
+func (vs *VolumeSnapshot) Apply(opts ...VolumeSnapshotOpt) {
+	for _, opt := range opts {
+		opt(vs)
+	}
+}
+
+// With returns a copy of the volume snapshot with the options applied
+func (vs VolumeSnapshot) With(opts ...VolumeSnapshotOpt) VolumeSnapshot {
+	clone := vs.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the volume snapshot
+func (vs VolumeSnapshot) Clone() VolumeSnapshot {
+	return VolumeSnapshot{VolumeSnapshot: deepCopyJSON(vs.VolumeSnapshot)}
+}
+
+// Validate checks the metadata and that exactly one of the claim and the snapshot content is set as the source
+func (vs VolumeSnapshot) Validate() error {
+	errs := validateObjectMeta(&vs.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+	path := field.NewPath("spec", "source")
+
+	errs = append(errs, validateOneOf(path, map[string]bool{
+		"persistentVolumeClaimName": ptrValue(vs.Spec.Source.PersistentVolumeClaimName) != "",
+		"volumeSnapshotContentName": ptrValue(vs.Spec.Source.VolumeSnapshotContentName) != "",
+	})...)
+
+	return toAggregate(errs)
+}
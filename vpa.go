@@ -0,0 +1,303 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"slices"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	vpav1 "github.com/CoverWhale/kopts/apis/vpa/v1"
+)
+
+// VPA holds a Kubernetes vertical pod autoscaler
+type VPA struct {
+	vpav1.VerticalPodAutoscaler
+
+	// containers holds the container names of the target pod template used to check the container policies
+	containers []string
+}
+
+type VPAOpt func(*VPA)
+
+// ContainerResourcePolicy holds the resource policy of a single container
+type ContainerResourcePolicy struct {
+	vpav1.ContainerResourcePolicy
+}
+
+type ContainerResourcePolicyOpt func(*ContainerResourcePolicy)
+
+// NewVerticalPodAutoscaler returns a vertical pod autoscaler with the given name and options
+func NewVerticalPodAutoscaler(name string, opts ...VPAOpt) VPA {
+	v := VPA{
+		VerticalPodAutoscaler: vpav1.VerticalPodAutoscaler{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "VerticalPodAutoscaler",
+				APIVersion: vpav1.APIVersion,
+			},
+			ObjectMeta: newObjectMeta(name),
+		},
+	}
+
+	for _, o := range opts {
+		o(&v)
+	}
+
+	return v
+}
+
+// NewDeploymentVerticalPodAutoscaler returns a recommendation only vertical pod autoscaler named after the deployment.
+// The update mode can be changed with VPAUpdateMode
+func NewDeploymentVerticalPodAutoscaler(d *Deployment, opts ...VPAOpt) VPA {
+	return NewVerticalPodAutoscaler(d.Name, append([]VPAOpt{VPADeployment(d), VPAUpdateMode(vpav1.UpdateModeOff)}, opts...)...)
+}
+
+// VPANamespace sets the namespace for the autoscaler
+func VPANamespace(n string) VPAOpt {
+	return func(v *VPA) {
+		setNamespace(n, &v.ObjectMeta)
+	}
+}
+
+// VPADeployment sets the deployment as the target. The autoscaler is placed in the deployment namespace
+func VPADeployment(d *Deployment) VPAOpt {
+	return vpaTarget(d.TypeMeta, d.ObjectMeta, d.Spec.Template.Spec)
+}
+
+// VPAStatefulSet sets the stateful set as the target. The autoscaler is placed in the stateful set namespace
+func VPAStatefulSet(s *StatefulSet) VPAOpt {
+	return vpaTarget(s.TypeMeta, s.ObjectMeta, s.Spec.Template.Spec)
+}
+
+func vpaTarget(t metav1.TypeMeta, m metav1.ObjectMeta, p corev1.PodSpec) VPAOpt {
+	var containers []string
+	for _, c := range p.Containers {
+		containers = append(containers, c.Name)
+	}
+
+	return func(v *VPA) {
+		setNamespace(m.Namespace, &v.ObjectMeta)
+		v.Spec.TargetRef = &autoscalingv1.CrossVersionObjectReference{
+			Kind:       t.Kind,
+			APIVersion: t.APIVersion,
+			Name:       m.Name,
+		}
+		v.containers = slices.Clone(containers)
+	}
+}
+
+// VPAUpdateMode sets when the recommendations are applied. Off only publishes the recommendations in the status
+func VPAUpdateMode(m vpav1.UpdateMode) VPAOpt {
+	return func(v *VPA) {
+		v.updatePolicy().UpdateMode = ptrTo(m)
+	}
+}
+
+// VPAMinReplicas sets the minimum live replicas needed before pods are evicted to apply the recommendations
+func VPAMinReplicas(i int) VPAOpt {
+	return func(v *VPA) {
+		v.updatePolicy().MinReplicas = ptrTo(int32(i))
+	}
+}
+
+func (v *VPA) updatePolicy() *vpav1.PodUpdatePolicy {
+	if v.Spec.UpdatePolicy == nil {
+		v.Spec.UpdatePolicy = &vpav1.PodUpdatePolicy{}
+	}
+	return v.Spec.UpdatePolicy
+}
+
+// VPAContainerPolicy adds a container resource policy
+func VPAContainerPolicy(p ContainerResourcePolicy) VPAOpt {
+	policy := deepCopyJSON(p.ContainerResourcePolicy)
+	return func(v *VPA) {
+		if v.Spec.ResourcePolicy == nil {
+			v.Spec.ResourcePolicy = &vpav1.PodResourcePolicy{}
+		}
+		v.Spec.ResourcePolicy.ContainerPolicies = append(v.Spec.ResourcePolicy.ContainerPolicies, deepCopyJSON(policy))
+	}
+}
+
+// Apply applies the options to the vertical pod autoscaler
+func (v *VPA) Apply(opts ...VPAOpt) {
+	for _, opt := range opts {
+		opt(v)
+	}
+}
+
+// With returns a copy of the vertical pod autoscaler with the options applied
+func (v VPA) With(opts ...VPAOpt) VPA {
+	clone := v.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the vertical pod autoscaler
+func (v VPA) Clone() VPA {
+	return VPA{VerticalPodAutoscaler: deepCopyJSON(v.VerticalPodAutoscaler), containers: slices.Clone(v.containers)}
+}
+
+// Validate checks the metadata, that the autoscaler has a target and that the container policies are unique and refer to
+// containers of the target pod template or to the default policy name *. Container names are only checked when
+// the target was set with VPADeployment or VPAStatefulSet
+func (v VPA) Validate() error {
+	errs := validateObjectMeta(&v.ObjectMeta, true, apimachineryvalidation.NameIsDNSSubdomain)
+
+	if v.Spec.TargetRef == nil {
+		errs = append(errs, field.Required(field.NewPath("spec", "targetRef"), ""))
+	}
+
+	if v.Spec.UpdatePolicy != nil {
+		errs = append(errs, validateEnum(ptrValue(v.Spec.UpdatePolicy.UpdateMode), field.NewPath("spec", "updatePolicy", "updateMode"),
+			vpav1.UpdateModeOff, vpav1.UpdateModeInitial, vpav1.UpdateModeRecreate, vpav1.UpdateModeAuto)...)
+	}
+
+	if v.Spec.ResourcePolicy == nil {
+		return toAggregate(errs)
+	}
+
+	known := map[string]bool{vpav1.DefaultContainerResourcePolicy: true}
+	for _, c := range v.containers {
+		known[c] = true
+	}
+
+	seen := make(map[string]bool)
+	path := field.NewPath("spec", "resourcePolicy", "containerPolicies")
+	for i, p := range v.Spec.ResourcePolicy.ContainerPolicies {
+		namePath := path.Index(i).Child("containerName")
+		switch {
+		case seen[p.ContainerName]:
+			errs = append(errs, field.Duplicate(namePath, p.ContainerName))
+		case v.containers != nil && !known[p.ContainerName]:
+			errs = append(errs, field.NotFound(namePath, p.ContainerName))
+		}
+		seen[p.ContainerName] = true
+
+		errs = append(errs, validateContainerResourcePolicy(p, path.Index(i))...)
+	}
+
+	return toAggregate(errs)
+}
+
+func validateContainerResourcePolicy(p vpav1.ContainerResourcePolicy, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if p.ContainerName == "" {
+		errs = append(errs, field.Required(path.Child("containerName"), ""))
+	}
+	errs = append(errs, validateEnum(ptrValue(p.Mode), path.Child("mode"), vpav1.ContainerScalingModeAuto, vpav1.ContainerScalingModeOff)...)
+	errs = append(errs, validateEnum(ptrValue(p.ControlledValues), path.Child("controlledValues"),
+		vpav1.ContainerControlledValuesRequestsAndLimits, vpav1.ContainerControlledValuesRequestsOnly)...)
+
+	for r, min := range p.MinAllowed {
+		if max, ok := p.MaxAllowed[r]; ok && min.Cmp(max) > 0 {
+			errs = append(errs, field.Invalid(path.Child("minAllowed").Key(string(r)), min.String(),
+				fmt.Sprintf("must not be greater than maxAllowed %s", max.String())))
+		}
+	}
+
+	return errs
+}
+
+// NewContainerResourcePolicy returns a resource policy for the container. Use * as the container name for the
+// policy applying to all containers without their own policy
+func NewContainerResourcePolicy(container string, opts ...ContainerResourcePolicyOpt) ContainerResourcePolicy {
+	p := ContainerResourcePolicy{
+		ContainerResourcePolicy: vpav1.ContainerResourcePolicy{
+			ContainerName: container,
+		},
+	}
+
+	for _, v := range opts {
+		v(&p)
+	}
+
+	return p
+}
+
+// VPAContainerMode sets whether the container is scaled. Off excludes the container from the recommendations
+func VPAContainerMode(m vpav1.ContainerScalingMode) ContainerResourcePolicyOpt {
+	return func(p *ContainerResourcePolicy) {
+		p.Mode = ptrTo(m)
+	}
+}
+
+// VPAContainerMinAllowed sets the lowest recommendation for the resource
+func VPAContainerMinAllowed(r corev1.ResourceName, q resource.Quantity) ContainerResourcePolicyOpt {
+	return func(p *ContainerResourcePolicy) {
+		if p.MinAllowed == nil {
+			p.MinAllowed = corev1.ResourceList{}
+		}
+		p.MinAllowed[r] = q.DeepCopy()
+	}
+}
+
+// VPAContainerMaxAllowed sets the highest recommendation for the resource
+func VPAContainerMaxAllowed(r corev1.ResourceName, q resource.Quantity) ContainerResourcePolicyOpt {
+	return func(p *ContainerResourcePolicy) {
+		if p.MaxAllowed == nil {
+			p.MaxAllowed = corev1.ResourceList{}
+		}
+		p.MaxAllowed[r] = q.DeepCopy()
+	}
+}
+
+// VPAContainerControlledResources sets the resources the autoscaler computes recommendations for. Defaults to CPU and memory
+func VPAContainerControlledResources(resources ...corev1.ResourceName) ContainerResourcePolicyOpt {
+	resources = slices.Clone(resources)
+	return func(p *ContainerResourcePolicy) {
+		p.ControlledResources = ptrTo(slices.Clone(resources))
+	}
+}
+
+// VPAContainerControlledValues sets whether the autoscaler sets only the requests or the requests and limits
+func VPAContainerControlledValues(c vpav1.ContainerControlledValues) ContainerResourcePolicyOpt {
+	return func(p *ContainerResourcePolicy) {
+		p.ControlledValues = ptrTo(c)
+	}
+}
+
+// Apply applies the options to the container policy
+func (p *ContainerResourcePolicy) Apply(opts ...ContainerResourcePolicyOpt) {
+	for _, opt := range opts {
+		opt(p)
+	}
+}
+
+// With returns a copy of the container policy with the options applied
+func (p ContainerResourcePolicy) With(opts ...ContainerResourcePolicyOpt) ContainerResourcePolicy {
+	clone := p.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the container policy
+func (p ContainerResourcePolicy) Clone() ContainerResourcePolicy {
+	return ContainerResourcePolicy{ContainerResourcePolicy: deepCopyJSON(p.ContainerResourcePolicy)}
+}
+
+// Validate checks that the policy names a container, the mode and controlled values and that the minimum allowed
+// resources are not greater than the maximum
+func (p ContainerResourcePolicy) Validate() error {
+	return toAggregate(validateContainerResourcePolicy(p.ContainerResourcePolicy, nil))
+}
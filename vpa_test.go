@@ -0,0 +1,162 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"reflect"
+	"testing"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	vpav1 "github.com/CoverWhale/kopts/apis/vpa/v1"
+)
+
+func vpaDeployment() *Deployment {
+	return NewDeployment("web",
+		DeploymentNamespace("apps"),
+		DeploymentPodSpec(NewPodSpec("web",
+			PodContainer(NewContainer("web", ContainerImage("nginx"))),
+			PodContainer(NewContainer("proxy", ContainerImage("envoy"))),
+		)),
+	)
+}
+
+func TestDeploymentVerticalPodAutoscaler(t *testing.T) {
+	policy := NewContainerResourcePolicy("web",
+		VPAContainerMinAllowed(corev1.ResourceCPU, resource.MustParse("100m")),
+		VPAContainerMaxAllowed(corev1.ResourceCPU, resource.MustParse("2")),
+		VPAContainerControlledResources(corev1.ResourceCPU),
+		VPAContainerControlledValues(vpav1.ContainerControlledValuesRequestsOnly),
+	)
+	opt := VPAContainerPolicy(policy)
+	v := NewDeploymentVerticalPodAutoscaler(vpaDeployment(), opt)
+
+	if v.Name != "web" || v.Namespace != "apps" {
+		t.Errorf("metadata = %s/%s, want apps/web", v.Namespace, v.Name)
+	}
+	if ref := v.Spec.TargetRef; ref.Kind != "Deployment" || ref.APIVersion != "apps/v1" || ref.Name != "web" {
+		t.Errorf("targetRef = %+v, want the web deployment", *ref)
+	}
+	if got := ptrValue(v.Spec.UpdatePolicy.UpdateMode); got != vpav1.UpdateModeOff {
+		t.Errorf("updateMode = %q, want Off for recommendations only", got)
+	}
+	if err := v.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	p := v.Spec.ResourcePolicy.ContainerPolicies[0]
+	(*p.ControlledResources)[0] = corev1.ResourceMemory
+	*p.ControlledValues = vpav1.ContainerControlledValuesRequestsAndLimits
+	second := NewVerticalPodAutoscaler("second", opt).Spec.ResourcePolicy.ContainerPolicies[0]
+	if got := *second.ControlledResources; !reflect.DeepEqual(got, []corev1.ResourceName{corev1.ResourceCPU}) {
+		t.Errorf("controlledResources = %v for a second autoscaler, want [cpu]", got)
+	}
+	if got := ptrValue(second.ControlledValues); got != vpav1.ContainerControlledValuesRequestsOnly {
+		t.Errorf("controlledValues = %q for a second autoscaler, want RequestsOnly", got)
+	}
+	if got := ptrValue(policy.ControlledValues); got != vpav1.ContainerControlledValuesRequestsOnly {
+		t.Errorf("controlledValues = %q for the policy the option was built from, want RequestsOnly", got)
+	}
+}
+
+func TestVPAUpdateMode(t *testing.T) {
+	opt := VPAUpdateMode(vpav1.UpdateModeInitial)
+	v := NewDeploymentVerticalPodAutoscaler(vpaDeployment(), opt, VPAMinReplicas(2))
+
+	if got := ptrValue(v.Spec.UpdatePolicy.UpdateMode); got != vpav1.UpdateModeInitial {
+		t.Errorf("updateMode = %q, want Initial to override the recommendation only default", got)
+	}
+	if got := ptrValue(v.Spec.UpdatePolicy.MinReplicas); got != 2 {
+		t.Errorf("minReplicas = %d, want 2", got)
+	}
+
+	*v.Spec.UpdatePolicy.UpdateMode = vpav1.UpdateModeAuto
+	if got := ptrValue(NewVerticalPodAutoscaler("second", opt).Spec.UpdatePolicy.UpdateMode); got != vpav1.UpdateModeInitial {
+		t.Errorf("updateMode = %q for a second autoscaler, want Initial", got)
+	}
+}
+
+func TestVPAValidate(t *testing.T) {
+	target := VPADeployment(vpaDeployment())
+
+	tests := []struct {
+		name   string
+		opts   []VPAOpt
+		fields []string
+	}{
+		{
+			name:   "without target",
+			opts:   []VPAOpt{VPANamespace("apps")},
+			fields: []string{"spec.targetRef"},
+		},
+		{
+			name: "policies for known containers",
+			opts: []VPAOpt{target,
+				VPAContainerPolicy(NewContainerResourcePolicy("proxy", VPAContainerMode(vpav1.ContainerScalingModeOff))),
+				VPAContainerPolicy(NewContainerResourcePolicy(vpav1.DefaultContainerResourcePolicy)),
+			},
+			fields: nil,
+		},
+		{
+			name:   "unknown container",
+			opts:   []VPAOpt{target, VPAContainerPolicy(NewContainerResourcePolicy("sidecar"))},
+			fields: []string{"spec.resourcePolicy.containerPolicies[0].containerName"},
+		},
+		{
+			name: "unknown container without a known target",
+			opts: []VPAOpt{VPANamespace("apps"), VPAContainerPolicy(NewContainerResourcePolicy("sidecar")), func(v *VPA) {
+				v.Spec.TargetRef = &autoscalingv1.CrossVersionObjectReference{Kind: "Deployment", APIVersion: "apps/v1", Name: "web"}
+			}},
+			fields: nil,
+		},
+		{
+			name: "duplicate container",
+			opts: []VPAOpt{target,
+				VPAContainerPolicy(NewContainerResourcePolicy("web")),
+				VPAContainerPolicy(NewContainerResourcePolicy("web")),
+			},
+			fields: []string{"spec.resourcePolicy.containerPolicies[1].containerName"},
+		},
+		{
+			name: "minimum over maximum",
+			opts: []VPAOpt{target, VPAContainerPolicy(NewContainerResourcePolicy("web",
+				VPAContainerMinAllowed(corev1.ResourceMemory, resource.MustParse("2Gi")),
+				VPAContainerMaxAllowed(corev1.ResourceMemory, resource.MustParse("1Gi")),
+			))},
+			fields: []string{"spec.resourcePolicy.containerPolicies[0].minAllowed[memory]"},
+		},
+		{
+			name:   "unknown update mode",
+			opts:   []VPAOpt{target, VPAUpdateMode("InPlace")},
+			fields: []string{"spec.updatePolicy.updateMode"},
+		},
+		{
+			name:   "unknown controlled values",
+			opts:   []VPAOpt{target, VPAContainerPolicy(NewContainerResourcePolicy("web", VPAContainerControlledValues("LimitsOnly")))},
+			fields: []string{"spec.resourcePolicy.containerPolicies[0].controlledValues"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := validationFields(t, NewVerticalPodAutoscaler("web", tt.opts...).Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// caBundleKey is the secret key holding the CA certificate of cert-manager issued secrets and injectCAFromKey is
+// the annotation read by the cert-manager CA injector
+const (
+	caBundleKey     = "ca.crt"
+	injectCAFromKey = "cert-manager.io/inject-ca-from"
+)
+
+// Webhook holds an admission webhook. The same webhook can be added to a validating or a mutating webhook
+// configuration. The reinvocation policy is only used by mutating webhooks
+type Webhook struct {
+	admissionregistrationv1.ValidatingWebhook
+
+	ReinvocationPolicy *admissionregistrationv1.ReinvocationPolicyType
+}
+
+type WebhookOpt func(*Webhook)
+
+// NewWebhook returns a webhook with the given name and options. The name must be a fully qualified domain name
+// such as validate.example.com. The webhook defaults to the v1 admission review version and no side effects
+func NewWebhook(name string, opts ...WebhookOpt) Webhook {
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	w := Webhook{
+		ValidatingWebhook: admissionregistrationv1.ValidatingWebhook{
+			Name:                    name,
+			SideEffects:             &sideEffects,
+			AdmissionReviewVersions: []string{"v1"},
+		},
+	}
+
+	for _, v := range opts {
+		v(&w)
+	}
+
+	return w
+}
+
+// WebhookService calls the webhook through the service on the path and service port
+func WebhookService(s Service, path string, port int) WebhookOpt {
+	name := s.Name
+	namespace := s.Namespace
+	return func(w *Webhook) {
+		w.ClientConfig.Service = &admissionregistrationv1.ServiceReference{
+			Namespace: namespace,
+			Name:      name,
+			Path:      ptrTo(path),
+			Port:      ptrTo(int32(port)),
+		}
+		w.ClientConfig.URL = nil
+	}
+}
+
+// WebhookURL calls the webhook at the https URL outside of the cluster
+func WebhookURL(url string) WebhookOpt {
+	return func(w *Webhook) {
+		w.ClientConfig.URL = ptrTo(url)
+		w.ClientConfig.Service = nil
+	}
+}
+
+// WebhookCABundle sets the PEM encoded CA bundle used to verify the webhook server certificate
+func WebhookCABundle(pem []byte) WebhookOpt {
+	pem = slices.Clone(pem)
+	return func(w *Webhook) {
+		w.ClientConfig.CABundle = slices.Clone(pem)
+	}
+}
+
+// WebhookCABundleFromFile reads the PEM encoded CA bundle from the file
+func WebhookCABundleFromFile(path string) (WebhookOpt, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return WebhookCABundle(pem), nil
+}
+
+// WebhookCABundleFromSecret sets the CA bundle from the ca.crt key of the secret. Secrets without a CA
+// certificate fall back to the tls.crt key, which works for self-signed server certificates. An error is returned
+// when the secret has neither key
+func WebhookCABundleFromSecret(s Secret) (WebhookOpt, error) {
+	pem, ok := s.Data[caBundleKey]
+	if !ok {
+		pem, ok = s.Data[corev1.TLSCertKey]
+	}
+	if !ok || len(pem) == 0 {
+		return nil, fmt.Errorf("secret %s has no %s or %s key", s.Name, caBundleKey, corev1.TLSCertKey)
+	}
+
+	return WebhookCABundle(pem), nil
+}
+
+// WebhookRule adds a rule matching the operations on the resources
+func WebhookRule(r admissionregistrationv1.RuleWithOperations) WebhookOpt {
+	rule := r.DeepCopy()
+	return func(w *Webhook) {
+		w.Rules = append(w.Rules, *rule.DeepCopy())
+	}
+}
+
+// WebhookResource adds a rule matching the operations on a resource of the API group and version.
+// Use an empty group for the core API group and * to match all groups, versions or resources
+func WebhookResource(group, version, resource string, operations ...admissionregistrationv1.OperationType) WebhookOpt {
+	return WebhookRule(admissionregistrationv1.RuleWithOperations{
+		Operations: operations,
+		Rule: admissionregistrationv1.Rule{
+			APIGroups:   []string{group},
+			APIVersions: []string{version},
+			Resources:   []string{resource},
+		},
+	})
+}
+
+// WebhookNamespaceSelector adds a label to the selector of the namespaces whose objects are sent to the webhook
+func WebhookNamespaceSelector(key, value string) WebhookOpt {
+	return func(w *Webhook) {
+		if w.NamespaceSelector == nil {
+			w.NamespaceSelector = &metav1.LabelSelector{}
+		}
+		metav1.AddLabelToSelector(w.NamespaceSelector, key, value)
+	}
+}
+
+// WebhookExcludeNamespaces skips objects in the namespaces, for instance the namespace the webhook runs in
+func WebhookExcludeNamespaces(namespaces ...string) WebhookOpt {
+	namespaces = slices.Clone(namespaces)
+	return func(w *Webhook) {
+		if w.NamespaceSelector == nil {
+			w.NamespaceSelector = &metav1.LabelSelector{}
+		}
+		w.NamespaceSelector.MatchExpressions = append(w.NamespaceSelector.MatchExpressions, metav1.LabelSelectorRequirement{
+			Key:      corev1.LabelMetadataName,
+			Operator: metav1.LabelSelectorOpNotIn,
+			Values:   slices.Clone(namespaces),
+		})
+	}
+}
+
+// WebhookObjectSelector adds a label to the selector of the objects sent to the webhook
+func WebhookObjectSelector(key, value string) WebhookOpt {
+	return func(w *Webhook) {
+		if w.ObjectSelector == nil {
+			w.ObjectSelector = &metav1.LabelSelector{}
+		}
+		metav1.AddLabelToSelector(w.ObjectSelector, key, value)
+	}
+}
+
+// WebhookFailurePolicy sets whether requests are rejected or allowed when the webhook can't be called
+func WebhookFailurePolicy(p admissionregistrationv1.FailurePolicyType) WebhookOpt {
+	return func(w *Webhook) {
+		w.FailurePolicy = ptrTo(p)
+	}
+}
+
+// WebhookMatchPolicy sets whether requests for other versions of the matched resources are sent to the webhook
+func WebhookMatchPolicy(p admissionregistrationv1.MatchPolicyType) WebhookOpt {
+	return func(w *Webhook) {
+		w.MatchPolicy = ptrTo(p)
+	}
+}
+
+// WebhookSideEffects sets whether the webhook has side effects outside of the admitted object
+func WebhookSideEffects(s admissionregistrationv1.SideEffectClass) WebhookOpt {
+	return func(w *Webhook) {
+		w.SideEffects = ptrTo(s)
+	}
+}
+
+// WebhookTimeoutSeconds sets how long the API server waits for the webhook, between 1 and 30 seconds
+func WebhookTimeoutSeconds(i int) WebhookOpt {
+	return func(w *Webhook) {
+		w.TimeoutSeconds = ptrTo(int32(i))
+	}
+}
+
+// WebhookMatchCondition adds a CEL expression that must be true for the request to be sent to the webhook
+func WebhookMatchCondition(name, expression string) WebhookOpt {
+	return func(w *Webhook) {
+		w.MatchConditions = append(w.MatchConditions, admissionregistrationv1.MatchCondition{
+			Name:       name,
+			Expression: expression,
+		})
+	}
+}
+
+// WebhookReinvocationPolicy sets whether a mutating webhook is called again after other webhooks changed the object
+func WebhookReinvocationPolicy(p admissionregistrationv1.ReinvocationPolicyType) WebhookOpt {
+	return func(w *Webhook) {
+		w.ReinvocationPolicy = ptrTo(p)
+	}
+}
+
+func (w Webhook) mutatingWebhook() admissionregistrationv1.MutatingWebhook {
+	c := w.Clone()
+	v := c.ValidatingWebhook
+	return admissionregistrationv1.MutatingWebhook{
+		Name:                    v.Name,
+		ClientConfig:            v.ClientConfig,
+		Rules:                   v.Rules,
+		FailurePolicy:           v.FailurePolicy,
+		MatchPolicy:             v.MatchPolicy,
+		NamespaceSelector:       v.NamespaceSelector,
+		ObjectSelector:          v.ObjectSelector,
+		SideEffects:             v.SideEffects,
+		TimeoutSeconds:          v.TimeoutSeconds,
+		AdmissionReviewVersions: v.AdmissionReviewVersions,
+		ReinvocationPolicy:      c.ReinvocationPolicy,
+		MatchConditions:         v.MatchConditions,
+	}
+}
+
+func injectCAFromAnnotation(c Certificate) string {
+	return fmt.Sprintf("%s/%s", c.Namespace, c.Name)
+}
+
+// Apply applies the options to the webhook
+func (w *Webhook) Apply(opts ...WebhookOpt) {
+	for _, opt := range opts {
+		opt(w)
+	}
+}
+
+// With returns a copy of the webhook with the options applied
+func (w Webhook) With(opts ...WebhookOpt) Webhook {
+	clone := w.Clone()
+	clone.Apply(opts...)
+
+	return clone
+}
+
+// Clone returns a deep copy of the webhook
+func (w Webhook) Clone() Webhook {
+	c := Webhook{ValidatingWebhook: *w.ValidatingWebhook.DeepCopy()}
+	if w.ReinvocationPolicy != nil {
+		c.ReinvocationPolicy = ptrTo(*w.ReinvocationPolicy)
+	}
+
+	return c
+}
+
+// Validate checks that the name is fully qualified, that the webhook is called through exactly one of a service or
+// https URL, the rules, the selectors, the match conditions and the enum and timeout settings
+func (w Webhook) Validate() error {
+	errs := validateWebhook(w.ValidatingWebhook, nil)
+	errs = append(errs, validateEnum(ptrValue(w.ReinvocationPolicy), field.NewPath("reinvocationPolicy"),
+		admissionregistrationv1.NeverReinvocationPolicy, admissionregistrationv1.IfNeededReinvocationPolicy)...)
+
+	return toAggregate(errs)
+}
+
+func validateWebhook(w admissionregistrationv1.ValidatingWebhook, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if w.Name == "" {
+		errs = append(errs, field.Required(path.Child("name"), ""))
+	} else {
+		errs = append(errs, validation.IsFullyQualifiedName(path.Child("name"), w.Name)...)
+	}
+
+	errs = append(errs, validateWebhookClientConfig(w.ClientConfig, path.Child("clientConfig"))...)
+
+	for i, r := range w.Rules {
+		errs = append(errs, validateRuleWithOperations(r, path.Child("rules").Index(i))...)
+	}
+
+	opts := metav1validation.LabelSelectorValidationOptions{}
+	errs = append(errs, metav1validation.ValidateLabelSelector(w.NamespaceSelector, opts, path.Child("namespaceSelector"))...)
+	errs = append(errs, metav1validation.ValidateLabelSelector(w.ObjectSelector, opts, path.Child("objectSelector"))...)
+	errs = append(errs, validateMatchConditions(w.MatchConditions, path.Child("matchConditions"))...)
+
+	if len(w.AdmissionReviewVersions) == 0 {
+		errs = append(errs, field.Required(path.Child("admissionReviewVersions"), "at least one admission review version is required"))
+	}
+	for i, v := range w.AdmissionReviewVersions {
+		errs = append(errs, validateEnum(v, path.Child("admissionReviewVersions").Index(i), "v1", "v1beta1")...)
+	}
+
+	if w.SideEffects == nil {
+		errs = append(errs, field.Required(path.Child("sideEffects"), ""))
+	} else {
+		errs = append(errs, validateEnum(*w.SideEffects, path.Child("sideEffects"),
+			admissionregistrationv1.SideEffectClassNone, admissionregistrationv1.SideEffectClassNoneOnDryRun)...)
+	}
+
+	errs = append(errs, validateEnum(ptrValue(w.FailurePolicy), path.Child("failurePolicy"), admissionregistrationv1.Ignore, admissionregistrationv1.Fail)...)
+	errs = append(errs, validateEnum(ptrValue(w.MatchPolicy), path.Child("matchPolicy"), admissionregistrationv1.Exact, admissionregistrationv1.Equivalent)...)
+	if t := w.TimeoutSeconds; t != nil && (*t < 1 || *t > 30) {
+		errs = append(errs, field.Invalid(path.Child("timeoutSeconds"), *t, "must be between 1 and 30 seconds"))
+	}
+
+	return errs
+}
+
+func validateWebhookClientConfig(c admissionregistrationv1.WebhookClientConfig, path *field.Path) field.ErrorList {
+	switch {
+	case c.URL == nil && c.Service == nil:
+		return field.ErrorList{field.Required(path, "exactly one of url or service is required")}
+	case c.URL != nil && c.Service != nil:
+		return field.ErrorList{field.Forbidden(path, "only one of url or service can be set")}
+	case c.URL != nil:
+		u, err := url.Parse(*c.URL)
+		if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+			return field.ErrorList{field.Invalid(path.Child("url"), *c.URL, "must be a https URL without user info, query or fragment")}
+		}
+		return nil
+	}
+
+	servicePath := path.Child("service")
+	errs := validateDNSLabel(c.Service.Namespace, servicePath.Child("namespace"))
+	errs = append(errs, validateDNSLabel(c.Service.Name, servicePath.Child("name"))...)
+	if c.Service.Port != nil {
+		errs = append(errs, validatePort(*c.Service.Port, servicePath.Child("port"))...)
+	}
+	if p := c.Service.Path; p != nil && !strings.HasPrefix(*p, "/") {
+		errs = append(errs, field.Invalid(servicePath.Child("path"), *p, "must be an absolute path"))
+	}
+
+	return errs
+}
+
+// validateWebhookNames checks that the webhook names of a configuration are unique
+func validateWebhookNames(names []string, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	seen := sets.New[string]()
+	for i, n := range names {
+		if seen.Has(n) {
+			errs = append(errs, field.Duplicate(path.Index(i).Child("name"), n))
+		}
+		seen.Insert(n)
+	}
+
+	return errs
+}
@@ -0,0 +1,242 @@
+// Copyright 2023 Cover Whale Insurance Solutions Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kopts
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const testCABundle = "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+
+func webhookService() Service {
+	return NewService("policy-webhook", ServiceNamespace("policy"), ServiceNamedPort("https", 443, 9443))
+}
+
+func TestWebhook(t *testing.T) {
+	service := WebhookService(webhookService(), "/validate", 443)
+	w := NewWebhook("validate.policy.example.com",
+		service,
+		WebhookResource("apps", "v1", "deployments", admissionregistrationv1.Create, admissionregistrationv1.Update),
+		WebhookExcludeNamespaces("kube-system", "policy"),
+		WebhookObjectSelector("policy.example.com/enforce", "true"),
+		WebhookFailurePolicy(admissionregistrationv1.Fail),
+		WebhookTimeoutSeconds(5),
+		WebhookMatchCondition("not-dry-run", "!request.dryRun"),
+	)
+
+	ref := w.ClientConfig.Service
+	if ref.Namespace != "policy" || ref.Name != "policy-webhook" || ptrValue(ref.Path) != "/validate" || ptrValue(ref.Port) != 443 {
+		t.Errorf("service = %+v, want policy/policy-webhook:443/validate", *ref)
+	}
+	if got := ptrValue(w.SideEffects); got != admissionregistrationv1.SideEffectClassNone {
+		t.Errorf("sideEffects = %q, want None", got)
+	}
+	if got := w.NamespaceSelector.MatchExpressions[0]; got.Key != corev1.LabelMetadataName || !reflect.DeepEqual(got.Values, []string{"kube-system", "policy"}) {
+		t.Errorf("namespace selector = %+v, want the excluded namespaces", got)
+	}
+	if err := w.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	*w.ClientConfig.Service.Path = "/changed"
+	*w.TimeoutSeconds = 30
+	second := NewWebhook("second.policy.example.com", service, WebhookURL("https://policy.example.com/validate"))
+	if second.ClientConfig.Service != nil {
+		t.Errorf("service = %+v, want the URL to replace the service", second.ClientConfig.Service)
+	}
+	if got := ptrValue(NewWebhook("third.policy.example.com", service).ClientConfig.Service.Path); got != "/validate" {
+		t.Errorf("path = %q for a third webhook, want /validate", got)
+	}
+}
+
+func TestWebhookCABundle(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "ca.crt")
+	if err := os.WriteFile(file, []byte(testCABundle), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fromFile, err := WebhookCABundleFromFile(file)
+	if err != nil {
+		t.Fatalf("WebhookCABundleFromFile() = %v", err)
+	}
+	if _, err := WebhookCABundleFromFile(filepath.Join(t.TempDir(), "missing.crt")); err == nil {
+		t.Error("WebhookCABundleFromFile() for a missing file = nil, want an error")
+	}
+
+	tests := []struct {
+		name    string
+		secret  Secret
+		wantErr bool
+	}{
+		{
+			name:   "CA certificate",
+			secret: NewSecret("webhook-tls", SecretData(caBundleKey, []byte(testCABundle)), SecretData(corev1.TLSCertKey, []byte("server"))),
+		},
+		{
+			name:   "self-signed server certificate",
+			secret: NewSecret("webhook-tls", SecretTLS([]byte(testCABundle), []byte("key"))),
+		},
+		{
+			name:    "without certificate",
+			secret:  NewSecret("webhook-tls", SecretData("token", []byte("secret"))),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opt, err := WebhookCABundleFromSecret(tt.secret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WebhookCABundleFromSecret() error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			w := NewWebhook("validate.policy.example.com", opt)
+			if string(w.ClientConfig.CABundle) != testCABundle {
+				t.Errorf("caBundle = %q, want %q", w.ClientConfig.CABundle, testCABundle)
+			}
+			w.ClientConfig.CABundle[0] = 'x'
+			for k, v := range tt.secret.Data {
+				if k != "token" && v[0] == 'x' {
+					t.Errorf("changing the CA bundle changed the secret key %s", k)
+				}
+			}
+		})
+	}
+
+	if w := NewWebhook("validate.policy.example.com", fromFile); string(w.ClientConfig.CABundle) != testCABundle {
+		t.Errorf("caBundle = %q from the file, want %q", w.ClientConfig.CABundle, testCABundle)
+	}
+}
+
+func TestWebhookConfigurations(t *testing.T) {
+	w := NewWebhook("mutate.policy.example.com",
+		WebhookService(webhookService(), "/mutate", 443),
+		WebhookResource("", "v1", "pods", admissionregistrationv1.Create),
+		WebhookReinvocationPolicy(admissionregistrationv1.IfNeededReinvocationPolicy),
+	)
+	cert := NewCertificate("policy-webhook", CertificateNamespace("policy"))
+
+	vwcOpt := ValidatingWebhookConfigurationWebhook(w)
+	vwc := NewValidatingWebhookConfiguration("policy", vwcOpt, ValidatingWebhookConfigurationInjectCAFrom(cert))
+	mwcOpt := MutatingWebhookConfigurationWebhook(w)
+	mwc := NewMutatingWebhookConfiguration("policy", mwcOpt, MutatingWebhookConfigurationInjectCAFrom(cert))
+
+	for name, annotations := range map[string]map[string]string{"validating": vwc.Annotations, "mutating": mwc.Annotations} {
+		if got := annotations[injectCAFromKey]; got != "policy/policy-webhook" {
+			t.Errorf("%s %s = %q, want policy/policy-webhook", name, injectCAFromKey, got)
+		}
+	}
+	if got := ptrValue(mwc.Webhooks[0].ReinvocationPolicy); got != admissionregistrationv1.IfNeededReinvocationPolicy {
+		t.Errorf("reinvocationPolicy = %q, want IfNeeded", got)
+	}
+	for _, v := range []Validator{vwc, mwc} {
+		if err := v.Validate(); err != nil {
+			t.Errorf("Validate() = %v", err)
+		}
+	}
+
+	vwc.Webhooks[0].Rules[0].Resources[0] = "changed"
+	*mwc.Webhooks[0].ReinvocationPolicy = admissionregistrationv1.NeverReinvocationPolicy
+	if got := w.Rules[0].Resources[0]; got != "pods" {
+		t.Errorf("webhook resource = %q after changing the configuration, want pods", got)
+	}
+	if got := NewValidatingWebhookConfiguration("second", vwcOpt).Webhooks[0].Rules[0].Resources[0]; got != "pods" {
+		t.Errorf("resource = %q for a second configuration, want pods", got)
+	}
+	if got := ptrValue(NewMutatingWebhookConfiguration("second", mwcOpt).Webhooks[0].ReinvocationPolicy); got != admissionregistrationv1.IfNeededReinvocationPolicy {
+		t.Errorf("reinvocationPolicy = %q for a second configuration, want IfNeeded", got)
+	}
+	if got := ptrValue(w.ReinvocationPolicy); got != admissionregistrationv1.IfNeededReinvocationPolicy {
+		t.Errorf("webhook reinvocationPolicy = %q after changing the configuration, want IfNeeded", got)
+	}
+}
+
+func TestWebhookValidate(t *testing.T) {
+	service := WebhookService(webhookService(), "/validate", 443)
+
+	tests := []struct {
+		name     string
+		webhooks []Webhook
+		fields   []string
+	}{
+		{
+			name:     "unqualified name",
+			webhooks: []Webhook{NewWebhook("validate", service)},
+			fields:   []string{"webhooks[0].name"},
+		},
+		{
+			name:     "without client config",
+			webhooks: []Webhook{NewWebhook("validate.policy.example.com")},
+			fields:   []string{"webhooks[0].clientConfig"},
+		},
+		{
+			name:     "http URL",
+			webhooks: []Webhook{NewWebhook("validate.policy.example.com", WebhookURL("http://policy.example.com/validate"))},
+			fields:   []string{"webhooks[0].clientConfig.url"},
+		},
+		{
+			name:     "relative service path",
+			webhooks: []Webhook{NewWebhook("validate.policy.example.com", WebhookService(webhookService(), "validate", 443))},
+			fields:   []string{"webhooks[0].clientConfig.service.path"},
+		},
+		{
+			name: "rule without operations and unknown side effects",
+			webhooks: []Webhook{NewWebhook("validate.policy.example.com", service,
+				WebhookResource("apps", "v1", "deployments"),
+				WebhookSideEffects(admissionregistrationv1.SideEffectClassSome),
+			)},
+			fields: []string{"webhooks[0].rules[0].operations", "webhooks[0].sideEffects"},
+		},
+		{
+			name:     "timeout over 30 seconds",
+			webhooks: []Webhook{NewWebhook("validate.policy.example.com", service, WebhookTimeoutSeconds(60))},
+			fields:   []string{"webhooks[0].timeoutSeconds"},
+		},
+		{
+			name:     "invalid match condition",
+			webhooks: []Webhook{NewWebhook("validate.policy.example.com", service, WebhookMatchCondition("dry run", "request.dryRun =="))},
+			fields:   []string{"webhooks[0].matchConditions[0].expression", "webhooks[0].matchConditions[0].name"},
+		},
+		{
+			name: "duplicate names",
+			webhooks: []Webhook{
+				NewWebhook("validate.policy.example.com", service),
+				NewWebhook("validate.policy.example.com", service),
+			},
+			fields: []string{"webhooks[1].name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []ValidatingWebhookConfigurationOpt
+			for _, w := range tt.webhooks {
+				opts = append(opts, ValidatingWebhookConfigurationWebhook(w))
+			}
+			fields := validationFields(t, NewValidatingWebhookConfiguration("policy", opts...).Validate())
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}